@@ -0,0 +1,399 @@
+package telegram
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/bot/telegram/markup"
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// Actions of the assign keyboards.
+//
+// Callback query data is "assign:item=<item ID>" for buttons that
+// choose an item, "assign:to=<item ID>,<user ID>" for buttons that
+// choose who buys the item, "assign:menu" for the button that goes back
+// to items and "assign:close" for the button that closes the keyboard.
+// User ID 0 means that nobody buys the item.
+const (
+	assignActionItem  = "item"
+	assignActionTo    = "to"
+	assignActionMenu  = "menu"
+	assignActionClose = "close"
+)
+
+const (
+	assignValueSeparator = "="
+	assignIDSeparator    = ","
+)
+
+// assignMaxItems is how many items we show in the keyboard.
+// Telegram doesn't show huge keyboards well
+const assignMaxItems = 30
+
+func init() {
+	registerCommands(
+		botCommand{
+			name:        commandAssign,
+			description: "command.assign.description",
+			args: []commandArg{
+				{name: "item", optional: true},
+			},
+			helpSection:          helpSectionShoppingList,
+			showInHelpMessage:    true,
+			commandHandler:       handleAssign,
+			callbackQueryHandler: handleAssignCallbackQuery,
+		},
+		botCommand{
+			name:              commandMine,
+			description:       "command.mine.description",
+			helpSection:       helpSectionShoppingList,
+			showInHelpMessage: true,
+			commandHandler:    handleMine,
+		},
+	)
+}
+
+// handleAssign asks who buys an item: `/assign milk`.
+// Without an item it asks to choose an item first
+func handleAssign(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	chatID := message.Chat.ID
+
+	tr, err := userTranslator(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	if len(chatItems) == 0 {
+		msg := tgbotapi.NewMessage(chatID, tr.T("list.empty"))
+		_, err = client.Send(msg)
+		return err
+	}
+
+	if itemName := strings.TrimSpace(message.CommandArguments()); itemName != "" {
+		chatItems, _ = matchItems(chatItems, itemName)
+		if len(chatItems) == 0 {
+			msg := tgbotapi.NewMessage(chatID, tr.T("assign.not_found", itemName))
+			_, err = client.Send(msg)
+			return err
+		}
+	}
+
+	names, err := assignees(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	var text string
+	var keyboard tgbotapi.InlineKeyboardMarkup
+	if len(chatItems) == 1 {
+		text, keyboard = assignMembersMenu(tr, chatItems[0], names)
+	} else {
+		text, keyboard = assignItemsMenu(tr, chatItems, names)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.BaseChat.ReplyMarkup = keyboard
+	_, err = client.Send(msg)
+	return err
+}
+
+// handleAssignCallbackQuery navigates the assign keyboards
+// by editing the message in place and assigns items
+func handleAssignCallbackQuery(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	callbackQuery *tgbotapi.CallbackQuery,
+	data string,
+) error {
+	client.AnswerCallbackQuery(tgbotapi.NewCallback(
+		callbackQuery.ID, ""))
+
+	chatID := callbackQuery.Message.Chat.ID
+	messageID := callbackQuery.Message.MessageID
+
+	if data == assignActionClose {
+		return hideInlineKeyboard(client, chatID, messageID)
+	}
+
+	tr, err := userTranslator(st, chatID, callbackQuery.From)
+	if err != nil {
+		return err
+	}
+
+	names, err := assignees(st, chatID, callbackQuery.From)
+	if err != nil {
+		return err
+	}
+
+	if data == assignActionMenu {
+		chatItems, err := st.GetShoppingItems(chatID)
+		if err != nil {
+			return fmt.Errorf(
+				"Unable to get all shopping items (ChatID=%d): %v",
+				chatID, err)
+		}
+
+		text, keyboard := assignItemsMenu(tr, chatItems, names)
+		return editAssignMenu(client, chatID, messageID, text, keyboard)
+	}
+
+	action, itemID, userID, err := splitAssignData(data)
+	if err != nil {
+		return err
+	}
+
+	item, err := st.GetShoppingItem(itemID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get a shopping item (ItemID=%d): %v",
+			itemID, err)
+	}
+
+	if item == nil || item.ChatID != chatID {
+		// Someone has removed the item, since we asked
+		msg := tgbotapi.NewMessage(chatID, tr.T("add.not_found"))
+		return sendAfterHidingKeyboard(client, chatID, messageID, msg)
+	}
+
+	if action == assignActionItem {
+		text, keyboard := assignMembersMenu(tr, item, names)
+		return editAssignMenu(client, chatID, messageID, text, keyboard)
+	}
+
+	if err := st.AssignShoppingItem(itemID, userID); err != nil {
+		return fmt.Errorf(
+			"Unable to assign a shopping item (ItemID=%d, UserID=%d): %v",
+			itemID, userID, err)
+	}
+
+	text := markup.NewBuilder(markup.ModeHTML)
+	if userID == 0 {
+		text.Text(tr.T("assign.removed", item.Name))
+	} else {
+		// Assignees get a notification, so they know what to buy
+		text.Format(tr.Template("assign.done"),
+			markup.Mention(assigneeName(tr, names, userID), userID), item.Name)
+	}
+
+	msg := newMarkupMessage(chatID, text)
+	return sendAfterHidingKeyboard(client, chatID, messageID, msg)
+}
+
+// handleMine shows items which are assigned to the user
+func handleMine(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	chatID := message.Chat.ID
+
+	tr, err := userTranslator(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	var mine []*models.ShoppingItem
+	for _, item := range chatItems {
+		if item.AssignedTo == message.From.ID {
+			mine = append(mine, item)
+		}
+	}
+
+	text := markup.NewBuilder(markup.ModeHTML)
+	if len(mine) == 0 {
+		text.Text(tr.T("mine.empty"))
+	} else {
+		text.Text(tr.N("mine.header", len(mine), len(mine)) + "\n\n")
+		text.Pre(formatShoppingItems(mine))
+	}
+
+	msg := newMarkupMessage(chatID, text)
+	_, err = client.Send(msg)
+	return err
+}
+
+// assignees returns names of users who can buy items by their IDs.
+// Bots can't get all members of a chat, so these are users
+// who changed the list and the user who assigns items
+func assignees(
+	st storage.DataStorageInterface,
+	chatID int64,
+	from *tgbotapi.User,
+) (map[int]string, error) {
+	names, err := st.GetActorNames(chatID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Unable to get names of users (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	if names == nil {
+		names = map[int]string{}
+	}
+	if from != nil {
+		names[from.ID] = userName(from)
+	}
+	return names, nil
+}
+
+// assigneeName returns a name of an user who buys an item
+func assigneeName(tr *i18n.Translator, names map[int]string, userID int) string {
+	if name := names[userID]; name != "" {
+		return name
+	}
+	return tr.T("assign.someone")
+}
+
+// assignItemsMenu returns text and an inline keyboard to choose an item.
+// Buttons of assigned items show who buys them
+func assignItemsMenu(
+	tr *i18n.Translator,
+	items []*models.ShoppingItem,
+	names map[int]string,
+) (string, tgbotapi.InlineKeyboardMarkup) {
+	if len(items) > assignMaxItems {
+		items = items[:assignMaxItems]
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, item := range items {
+		text := shoppingItemName(item)
+		if item.AssignedTo != 0 {
+			text += " → " + assigneeName(tr, names, item.AssignedTo)
+		}
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(text, joinAssignData(
+				assignActionItem, strconv.FormatInt(item.ID, 10)))))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(
+			tr.T("settings.close"),
+			joinCallbackQueryData(commandAssign, assignActionClose))))
+
+	return tr.T("assign.prompt"), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// assignMembersMenu returns text and an inline keyboard
+// to choose who buys an item. The current assignee is marked
+func assignMembersMenu(
+	tr *i18n.Translator,
+	item *models.ShoppingItem,
+	names map[int]string,
+) (string, tgbotapi.InlineKeyboardMarkup) {
+	userIDs := make([]int, 0, len(names))
+	for userID := range names {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool {
+		a, b := strings.ToLower(names[userIDs[i]]), strings.ToLower(names[userIDs[j]])
+		if a != b {
+			return a < b
+		}
+		return userIDs[i] < userIDs[j]
+	})
+
+	itemID := strconv.FormatInt(item.ID, 10)
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, userID := range userIDs {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(settingsButton(
+			names[userID],
+			userID == item.AssignedTo,
+			joinAssignData(assignActionTo,
+				itemID+assignIDSeparator+strconv.Itoa(userID)))))
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(settingsButton(
+			tr.T("assign.nobody"),
+			item.AssignedTo == 0,
+			joinAssignData(assignActionTo, itemID+assignIDSeparator+"0"))),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			tr.T("settings.back"),
+			joinCallbackQueryData(commandAssign, assignActionMenu))),
+	)
+
+	return tr.T("assign.member.prompt", item.Name), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func editAssignMenu(
+	client botClientInterface,
+	chatID int64,
+	messageID int,
+	text string,
+	keyboard tgbotapi.InlineKeyboardMarkup,
+) error {
+	msg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	msg.ReplyMarkup = &keyboard
+	_, err := client.Send(msg)
+	if isMessageNotModifiedError(err) {
+		return nil
+	}
+	return err
+}
+
+func joinAssignData(action, value string) string {
+	return joinCallbackQueryData(
+		commandAssign, action+assignValueSeparator+value)
+}
+
+// splitAssignData parses callback query data of buttons
+// which choose items and users. User ID is zero for items
+func splitAssignData(data string) (action string, itemID int64, userID int, err error) {
+	pieces := strings.SplitN(data, assignValueSeparator, 2)
+	if len(pieces) != 2 || (pieces[0] != assignActionItem && pieces[0] != assignActionTo) {
+		return "", 0, 0, fmt.Errorf(
+			"Unable to parse an action from the CallbackQuery data %#v",
+			data)
+	}
+	action = pieces[0]
+
+	ids := strings.SplitN(pieces[1], assignIDSeparator, 2)
+	if (action == assignActionTo) != (len(ids) == 2) {
+		return "", 0, 0, fmt.Errorf(
+			"Unable to parse IDs from the CallbackQuery data %#v",
+			data)
+	}
+
+	// User can't amend CallBackData, so most likely it's our fault
+	itemID, err = strconv.ParseInt(ids[0], 10, 64)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf(
+			"Unable to parse ItemID from the CallbackQuery data %s: %v",
+			data, err)
+	}
+
+	if action == assignActionTo {
+		userID, err = strconv.Atoi(ids[1])
+		if err != nil {
+			return "", 0, 0, fmt.Errorf(
+				"Unable to parse UserID from the CallbackQuery data %s: %v",
+				data, err)
+		}
+	}
+	return action, itemID, userID, nil
+}
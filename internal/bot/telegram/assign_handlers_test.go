@@ -0,0 +1,260 @@
+package telegram
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+func TestHandleAssign(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Common data mocks
+	chatID := int64(123)
+	newMessageMock := func(args string) *tgbotapi.Message {
+		messageMock := mock_telegram.MessageCommandMockSetup(commandAssign, args)
+		messageMock.Chat = &tgbotapi.Chat{ID: chatID}
+		messageMock.From = &tgbotapi.User{ID: 321, FirstName: "Bob"}
+		return messageMock
+	}
+	itemsMock := []*models.ShoppingItem{
+		{ID: 1, ChatID: chatID, Name: "Milk", AssignedTo: 555},
+		{ID: 2, ChatID: chatID, Name: "Bread"},
+	}
+
+	expectMenu := func(t *testing.T, expectedText string, expectedButtons, expectedData []string) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if msgCfg.Text != expectedText {
+				t.Errorf("Expected %#v, got %#v", expectedText, msgCfg.Text)
+			}
+
+			keyboard, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+			if !ok {
+				t.Fatalf("Expected an inline keyboard, got %#v", msgCfg.ReplyMarkup)
+			}
+
+			data := keyboardCallbackData(keyboard)
+			if !reflect.DeepEqual(data, expectedData) {
+				t.Errorf("Expected callback data %v, got %v", expectedData, data)
+			}
+
+			for i, expectedButton := range expectedButtons {
+				if keyboard.InlineKeyboard[i][0].Text != expectedButton {
+					t.Errorf("Expected button %#v, got %#v",
+						expectedButton, keyboard.InlineKeyboard[i][0].Text)
+				}
+			}
+		})
+	}
+
+	t.Run("Without arguments", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(chatID).Return(itemsMock, nil)
+		stMock.EXPECT().GetActorNames(chatID).Return(map[int]string{555: "Alice"}, nil)
+		expectMenu(t, "Which item do you want to assign?",
+			[]string{"Milk → Alice", "Bread"},
+			[]string{"assign:item=1", "assign:item=2", "assign:close"})
+
+		err := handleAssign(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("With an item", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(chatID).Return(itemsMock, nil)
+		stMock.EXPECT().GetActorNames(chatID).Return(map[int]string{555: "Alice"}, nil)
+		expectMenu(t, `Who buys "Bread"?`,
+			[]string{"Alice", "Bob", "✓ Nobody"},
+			[]string{"assign:to=2,555", "assign:to=2,321", "assign:to=2,0", "assign:menu"})
+
+		err := handleAssign(clientMock, stMock, newMessageMock("bread"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Unknown item", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(chatID).Return(itemsMock, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			expectedText := `I can't find "cheese" in your shopping list.`
+			if msgCfg.Text != expectedText {
+				t.Errorf("Expected %#v, got %#v", expectedText, msgCfg.Text)
+			}
+		})
+
+		err := handleAssign(clientMock, stMock, newMessageMock("cheese"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
+
+func TestHandleAssignCallbackQuery(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+	clientMock.EXPECT().AnswerCallbackQuery(gomock.Any()).AnyTimes()
+
+	// Common data mocks
+	callbackQueryMock := &tgbotapi.CallbackQuery{
+		ID:   "some-callback-id",
+		From: &tgbotapi.User{ID: 321, FirstName: "Bob"},
+		Message: &tgbotapi.Message{
+			MessageID: 456,
+			Chat:      &tgbotapi.Chat{ID: 123},
+		},
+	}
+	chatID := callbackQueryMock.Message.Chat.ID
+	itemMock := &models.ShoppingItem{ID: 2, ChatID: chatID, Name: "Bread"}
+
+	expectText := func(t *testing.T, expectedText string) {
+		gomock.InOrder(
+			clientMock.EXPECT().Send(gomock.Any()).Do(
+				generateSendHideKeybaordCallChecker(t, callbackQueryMock)),
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				if msgCfg.Text != expectedText {
+					t.Errorf("Expected %#v, got %#v", expectedText, msgCfg.Text)
+				}
+			}),
+		)
+	}
+
+	t.Run("Choose an item", func(t *testing.T) {
+		stMock.EXPECT().GetActorNames(chatID).Return(nil, nil)
+		stMock.EXPECT().GetShoppingItem(int64(2)).Return(itemMock, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.EditMessageTextConfig) {
+			data := keyboardCallbackData(*msgCfg.ReplyMarkup)
+			expectedData := []string{"assign:to=2,321", "assign:to=2,0", "assign:menu"}
+			if !reflect.DeepEqual(data, expectedData) {
+				t.Errorf("Expected callback data %v, got %v", expectedData, data)
+			}
+		})
+
+		err := handleAssignCallbackQuery(clientMock, stMock, callbackQueryMock, "item=2")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Assign", func(t *testing.T) {
+		stMock.EXPECT().GetActorNames(chatID).Return(map[int]string{555: "Alice"}, nil)
+		stMock.EXPECT().GetShoppingItem(int64(2)).Return(itemMock, nil)
+		stMock.EXPECT().AssignShoppingItem(int64(2), 555).Return(nil)
+		expectText(t, `<a href="tg://user?id=555">Alice</a>, you're buying &quot;Bread&quot;.`)
+
+		err := handleAssignCallbackQuery(clientMock, stMock, callbackQueryMock, "to=2,555")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Unassign", func(t *testing.T) {
+		stMock.EXPECT().GetActorNames(chatID).Return(nil, nil)
+		stMock.EXPECT().GetShoppingItem(int64(2)).Return(itemMock, nil)
+		stMock.EXPECT().AssignShoppingItem(int64(2), 0).Return(nil)
+		expectText(t, "Ok, nobody is assigned to &quot;Bread&quot; now.")
+
+		err := handleAssignCallbackQuery(clientMock, stMock, callbackQueryMock, "to=2,0")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Item of another chat", func(t *testing.T) {
+		stMock.EXPECT().GetActorNames(chatID).Return(nil, nil)
+		stMock.EXPECT().GetShoppingItem(int64(2)).Return(
+			&models.ShoppingItem{ID: 2, ChatID: 999, Name: "Bread"}, nil)
+		expectText(t, "Can't find this item, someone has probably removed it. Please, add it again.")
+
+		err := handleAssignCallbackQuery(clientMock, stMock, callbackQueryMock, "to=2,321")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Close the keyboard", func(t *testing.T) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(
+			generateSendHideKeybaordCallChecker(t, callbackQueryMock))
+
+		err := handleAssignCallbackQuery(clientMock, stMock, callbackQueryMock, "close")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		stMock.EXPECT().GetActorNames(chatID).Return(nil, nil).AnyTimes()
+
+		for _, data := range []string{"unknown", "item=x", "item=1,2", "to=1", "to=1,x"} {
+			err := handleAssignCallbackQuery(clientMock, stMock, callbackQueryMock, data)
+			if err == nil {
+				t.Errorf("Expected an error for %#v", data)
+			}
+		}
+	})
+}
+
+func TestHandleMine(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Common data mocks
+	messageMock := mock_telegram.MessageCommandMockSetup(commandMine, "")
+	messageMock.Chat = &tgbotapi.Chat{ID: 123}
+	messageMock.From = &tgbotapi.User{ID: 321}
+
+	expectText := func(t *testing.T, expectedText string) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+	}
+
+	t.Run("Assigned items", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(int64(123)).Return([]*models.ShoppingItem{
+			{ID: 1, Name: "Milk", AssignedTo: 321, Quantity: 2},
+			{ID: 2, Name: "Bread", AssignedTo: 555},
+			{ID: 3, Name: "Eggs"},
+			{ID: 4, Name: "Cheese", AssignedTo: 321},
+		}, nil)
+		expectText(t, "You're buying 2 items:\n\n<pre>1. Milk ×2\n2. Cheese\n</pre>")
+
+		err := handleMine(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Nothing assigned", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(int64(123)).Return([]*models.ShoppingItem{
+			{ID: 2, Name: "Bread", AssignedTo: 555},
+		}, nil)
+		expectText(t, "Nobody has assigned items to you yet.")
+
+		err := handleMine(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/bot/telegram/markup"
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/money"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// budgetSymbols are currency symbols which users can type with a budget:
+// `/budget €50`. A budget is always in the currency of the chat
+const budgetSymbols = "€$£₴zł "
+
+func init() {
+	registerCommands(botCommand{
+		name:        commandBudget,
+		description: "command.budget.description",
+		args: []commandArg{
+			{name: "amount", optional: true},
+		},
+		helpSection:       helpSectionShoppingList,
+		showInHelpMessage: true,
+		permission:        permissionAdmin,
+		commandHandler:    handleBudget,
+	})
+}
+
+// handleBudget shows the budget of the shopping list or changes it.
+// Zero amount removes the budget: `/budget 0`
+func handleBudget(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	chatID := message.Chat.ID
+
+	tr, err := userTranslator(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	settings, err := st.GetChatSettings(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get settings of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		return sendBudget(client, st, tr, settings)
+	}
+
+	budget, err := money.Parse(strings.Trim(args, budgetSymbols))
+	if err != nil {
+		text := tr.T("budget.invalid") + budgetHelp(tr, settings)
+		msg := tgbotapi.NewMessage(chatID, text)
+		_, err = client.Send(msg)
+		return err
+	}
+
+	settings.Budget = budget
+	if err := st.UpdateChatSettings(*settings); err != nil {
+		return fmt.Errorf(
+			"Unable to update settings of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	text := tr.T("budget.removed")
+	if budget > 0 {
+		text = tr.T("budget.done", money.Format(budget, settings.Currency))
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	_, err = client.Send(msg)
+	return err
+}
+
+// sendBudget sends the budget of the chat and
+// the total of estimated prices of items in the list
+func sendBudget(
+	client sender,
+	st storage.DataStorageInterface,
+	tr *i18n.Translator,
+	settings *models.ChatSettings,
+) error {
+	chatID := settings.ChatID
+
+	if settings.Budget == 0 {
+		msg := tgbotapi.NewMessage(chatID, tr.T("budget.none")+budgetHelp(tr, settings))
+		_, err := client.Send(msg)
+		return err
+	}
+
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	total, _ := shoppingListTotal(chatItems)
+	text := markup.NewBuilder(markup.ModeHTML).Text(tr.T("budget.current",
+		money.Format(settings.Budget, settings.Currency),
+		money.Format(total, settings.Currency)))
+	if total > settings.Budget {
+		overspend := money.Format(total-settings.Budget, settings.Currency)
+		text.Text("\n").Bold(tr.T("list.budget.exceeded", overspend))
+	}
+	text.Text(budgetHelp(tr, settings))
+
+	msg := newMarkupMessage(chatID, text)
+	_, err = client.Send(msg)
+	return err
+}
+
+// budgetHelp explains how to change the budget and how to add prices.
+// Examples of prices are in the currency of the chat
+func budgetHelp(tr *i18n.Translator, settings *models.ChatSettings) string {
+	return tr.T("budget.help", money.Format(120, settings.Currency))
+}
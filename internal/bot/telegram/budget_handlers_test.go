@@ -0,0 +1,106 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/money"
+)
+
+func TestHandleBudget(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Common data mocks
+	chatID := int64(123)
+	newMessageMock := func(args string) *tgbotapi.Message {
+		messageMock := mock_telegram.MessageCommandMockSetup(commandBudget, args)
+		messageMock.Chat = &tgbotapi.Chat{ID: chatID}
+		messageMock.From = &tgbotapi.User{ID: 321}
+		return messageMock
+	}
+	newSettingsMock := func(budget money.Amount) *models.ChatSettings {
+		settings := models.NewChatSettings(chatID)
+		settings.Budget = budget
+		return settings
+	}
+
+	expectText := func(t *testing.T, expectedText string) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.HasPrefix(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to start with %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+	}
+
+	t.Run("Without a budget", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(newSettingsMock(0), nil)
+		expectText(t, "This list doesn't have a budget yet.")
+
+		err := handleBudget(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Over the budget", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(newSettingsMock(1000), nil)
+		stMock.EXPECT().GetShoppingItems(chatID).Return([]*models.ShoppingItem{
+			{Name: "Cheese", Quantity: 2, Price: 650},
+			{Name: "Bread"},
+		}, nil)
+		expectText(t, "The budget of this list is €10.00. Items with prices cost €13.00 so far.\n"+
+			"<b>⚠️ The list is over the budget by €3.00</b>")
+
+		err := handleBudget(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Set the budget", func(t *testing.T) {
+		expectedSettings := newSettingsMock(0)
+		expectedSettings.Budget = 4999
+
+		stMock.EXPECT().GetChatSettings(chatID).Return(newSettingsMock(0), nil)
+		stMock.EXPECT().UpdateChatSettings(*expectedSettings).Return(nil)
+		expectText(t, "Done! The budget of this list is €49.99.")
+
+		err := handleBudget(clientMock, stMock, newMessageMock("€49,99"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Remove the budget", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(newSettingsMock(1000), nil)
+		stMock.EXPECT().UpdateChatSettings(*newSettingsMock(0)).Return(nil)
+		expectText(t, "Ok, I've removed the budget of this list.")
+
+		err := handleBudget(clientMock, stMock, newMessageMock("0"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Invalid amount", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(newSettingsMock(0), nil)
+		expectText(t, "Sorry, I don't understand the amount.")
+
+		err := handleBudget(clientMock, stMock, newMessageMock("a lot"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
@@ -0,0 +1,247 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/category"
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+func init() {
+	registerCommands(
+		botCommand{
+			name:        commandCategory,
+			description: "command.category.description",
+			args: []commandArg{
+				{name: "item", optional: true},
+				{name: "category", optional: true},
+			},
+			helpSection:       helpSectionShoppingList,
+			showInHelpMessage: true,
+			commandHandler:    handleCategory,
+		},
+		botCommand{
+			name:              commandAisles,
+			description:       "command.aisles.description",
+			args:              []commandArg{{name: "categories", optional: true}},
+			helpSection:       helpSectionSettings,
+			showInHelpMessage: true,
+			permission:        permissionAdmin,
+			commandHandler:    handleAisles,
+		},
+	)
+}
+
+// handleCategory teaches the bot a category of an item: `/category milk dairy`.
+// The last word is a category, so names of items can have several words
+func handleCategory(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	tr, err := userTranslator(st, message.Chat.ID, message.From)
+	if err != nil {
+		return err
+	}
+
+	chatID := message.Chat.ID
+	words := strings.Fields(message.CommandArguments())
+	if len(words) < 2 {
+		text := tr.T("category.prompt", categoryNames(tr, category.DefaultOrder))
+		msg := tgbotapi.NewMessage(chatID, text)
+		_, err = client.Send(msg)
+		return err
+	}
+
+	itemName := strings.Join(words[:len(words)-1], " ")
+	c, ok := parseCategory(tr, words[len(words)-1])
+	if !ok {
+		text := tr.T("category.unknown",
+			words[len(words)-1], categoryNames(tr, category.DefaultOrder))
+		msg := tgbotapi.NewMessage(chatID, text)
+		_, err = client.Send(msg)
+		return err
+	}
+
+	keyword := category.Keyword(itemName)
+	if err := st.SetCategoryKeyword(chatID, keyword, c); err != nil {
+		return fmt.Errorf(
+			"Unable to set a category keyword (ChatID=%d, Category=%s): %v",
+			chatID, c, err)
+	}
+
+	// Items which are already in the list
+	// must follow the new keyword too
+	if err := recategoriseShoppingItems(st, chatID); err != nil {
+		return err
+	}
+
+	text := tr.T("category.done", itemName, tr.T("category."+c))
+	msg := tgbotapi.NewMessage(chatID, text)
+	_, err = client.Send(msg)
+	return err
+}
+
+// handleAisles changes the order of categories in the shopping list:
+// `/aisles produce, dairy, bakery`. Categories which users don't mention
+// go after mentioned ones in the default order
+func handleAisles(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	tr, err := userTranslator(st, message.Chat.ID, message.From)
+	if err != nil {
+		return err
+	}
+
+	chatID := message.Chat.ID
+	settings, err := st.GetChatSettings(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get settings of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	args := strings.FieldsFunc(message.CommandArguments(), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	if len(args) == 0 {
+		text := tr.T("aisles.prompt",
+			formatCategoryOrder(tr, category.Order(settings.CategoryOrder)))
+		msg := tgbotapi.NewMessage(chatID, text)
+		_, err = client.Send(msg)
+		return err
+	}
+
+	var order []string
+	for _, arg := range args {
+		c, ok := parseCategory(tr, arg)
+		if !ok {
+			text := tr.T("category.unknown",
+				arg, categoryNames(tr, category.DefaultOrder))
+			msg := tgbotapi.NewMessage(chatID, text)
+			_, err = client.Send(msg)
+			return err
+		}
+		order = append(order, c)
+	}
+
+	// The order only makes sense, when the list is grouped by categories
+	settings.CategoryOrder = category.Order(order)
+	settings.SortOrder = models.SortOrderCategory
+	if err := st.UpdateChatSettings(*settings); err != nil {
+		return fmt.Errorf(
+			"Unable to update settings of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	text := tr.T("aisles.done", formatCategoryOrder(tr, settings.CategoryOrder))
+	msg := tgbotapi.NewMessage(chatID, text)
+	_, err = client.Send(msg)
+	return err
+}
+
+// recategoriseShoppingItems updates categories of all items in a chat
+// according to keywords which users have taught the bot
+func recategoriseShoppingItems(st storage.DataStorageInterface, chatID int64) error {
+	learned, err := st.GetCategoryKeywords(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get category keywords (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	for _, item := range chatItems {
+		c := category.Detect(item.Name, learned)
+		if c == item.Category {
+			continue
+		}
+
+		if err := st.UpdateShoppingItemCategory(item.ID, c); err != nil {
+			return fmt.Errorf(
+				"Unable to update a category of a shopping item (ItemID=%d): %v",
+				item.ID, err)
+		}
+	}
+	return nil
+}
+
+// newShoppingItem returns a new item with a category
+// detected using keywords which users have taught the bot
+func newShoppingItem(
+	st storage.DataStorageInterface,
+	chatID int64,
+	itemName string,
+	createdBy int,
+) (models.ShoppingItem, error) {
+	item := models.ShoppingItem{
+		Name:      itemName,
+		ChatID:    chatID,
+		CreatedBy: createdBy,
+	}
+
+	learned, err := st.GetCategoryKeywords(chatID)
+	if err != nil {
+		return item, fmt.Errorf(
+			"Unable to get category keywords (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	item.Category = category.Detect(itemName, learned)
+	return item, nil
+}
+
+// itemCategory returns a category of an item.
+// Items which were added before we had categories
+// are categorised using built-in keywords
+func itemCategory(item *models.ShoppingItem) string {
+	if item.Category == "" {
+		return category.Detect(item.Name, nil)
+	}
+	if !category.IsSupported(item.Category) {
+		return category.Other
+	}
+	return item.Category
+}
+
+// parseCategory returns a category by its ID or its name in the user's language
+func parseCategory(tr *i18n.Translator, text string) (string, bool) {
+	for _, c := range category.DefaultOrder {
+		if strings.EqualFold(text, c) || strings.EqualFold(text, tr.T("category."+c)) {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// categoryNames returns comma separated names of categories
+func categoryNames(tr *i18n.Translator, categories []string) string {
+	var names []string
+	for _, c := range categories {
+		names = append(names, tr.T("category."+c))
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatCategoryOrder returns a numbered list of categories
+func formatCategoryOrder(tr *i18n.Translator, order []string) string {
+	var text string
+	for i, c := range order {
+		text += "\n" + strconv.Itoa(i+1) + ". " + tr.T("category."+c)
+	}
+	return text
+}
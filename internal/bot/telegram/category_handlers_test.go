@@ -0,0 +1,241 @@
+package telegram
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/category"
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/money"
+)
+
+func TestHandleCategory(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
+
+	// Common data mocks
+	errMock := errors.New("Fake error")
+	chatID := int64(123)
+	newMessageMock := func(args string) *tgbotapi.Message {
+		messageMock := mock_telegram.MessageCommandMockSetup(commandCategory, args)
+		messageMock.Chat = &tgbotapi.Chat{ID: chatID}
+		messageMock.From = &tgbotapi.User{ID: 321}
+		return messageMock
+	}
+
+	expectText := func(t *testing.T, expectedText string) {
+		clientMock.EXPECT().Send(
+			gomock.Any(),
+		).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+	}
+
+	t.Run("Without arguments", func(t *testing.T) {
+		expectText(t, "/category milk dairy")
+
+		err := handleCategory(clientMock, stMock, newMessageMock("milk"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Unknown category", func(t *testing.T) {
+		expectText(t, `don't know the "aisle7" category`)
+
+		err := handleCategory(clientMock, stMock, newMessageMock("milk aisle7"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		learned := map[string]string{"oat milk": category.Pantry}
+		stMock.EXPECT().SetCategoryKeyword(chatID, "oat milk", category.Pantry).Return(nil)
+		stMock.EXPECT().GetCategoryKeywords(chatID).Return(learned, nil)
+		stMock.EXPECT().GetShoppingItems(chatID).Return([]*models.ShoppingItem{
+			{ID: 1, Name: "Oat Milk", Category: category.Dairy},
+			{ID: 2, Name: "Milk", Category: category.Dairy},
+			{ID: 3, Name: "Bread"},
+		}, nil)
+		stMock.EXPECT().UpdateShoppingItemCategory(int64(1), category.Pantry).Return(nil)
+		stMock.EXPECT().UpdateShoppingItemCategory(int64(3), category.Bakery).Return(nil)
+		expectText(t, `"Oat Milk" goes to the Pantry category`)
+
+		err := handleCategory(clientMock, stMock, newMessageMock("Oat Milk pantry"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().SetCategoryKeyword(chatID, "milk", category.Dairy).Return(errMock)
+
+		err := handleCategory(clientMock, stMock, newMessageMock("milk Dairy"))
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+}
+
+func TestHandleAisles(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Common data mocks
+	chatID := int64(123)
+	newMessageMock := func(args string) *tgbotapi.Message {
+		messageMock := mock_telegram.MessageCommandMockSetup(commandAisles, args)
+		messageMock.Chat = &tgbotapi.Chat{ID: chatID}
+		messageMock.From = &tgbotapi.User{ID: 321}
+		return messageMock
+	}
+
+	expectText := func(t *testing.T, expectedText string) {
+		clientMock.EXPECT().Send(
+			gomock.Any(),
+		).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+	}
+
+	t.Run("Without arguments", func(t *testing.T) {
+		settingsMock := models.NewChatSettings(chatID)
+		settingsMock.CategoryOrder = []string{category.Dairy}
+		stMock.EXPECT().GetChatSettings(chatID).Return(settingsMock, nil)
+		expectText(t, "\n1. Dairy\n2. Produce\n")
+
+		err := handleAisles(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Unknown category", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+		expectText(t, `don't know the "aisle7" category`)
+
+		err := handleAisles(clientMock, stMock, newMessageMock("dairy, aisle7"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+		stMock.EXPECT().UpdateChatSettings(gomock.Any()).Do(func(settings models.ChatSettings) {
+			expectedOrder := category.Order([]string{category.Household, category.Dairy})
+			if !reflect.DeepEqual(settings.CategoryOrder, expectedOrder) {
+				t.Errorf("Expected order %#v, got %#v",
+					expectedOrder, settings.CategoryOrder)
+			}
+			if settings.SortOrder != models.SortOrderCategory {
+				t.Errorf("Expected sort order %#v, got %#v",
+					models.SortOrderCategory, settings.SortOrder)
+			}
+		}).Return(nil)
+		expectText(t, "\n1. Household\n2. Dairy\n3. Produce\n")
+
+		err := handleAisles(clientMock, stMock, newMessageMock("household,  Dairy"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("List is grouped after aisles", func(t *testing.T) {
+		// The list uses settings which /aisles saved
+		savedSettings := &models.ChatSettings{}
+		gomock.InOrder(
+			stMock.EXPECT().GetChatSettings(chatID).Return(
+				models.NewChatSettings(chatID), nil),
+			stMock.EXPECT().UpdateChatSettings(gomock.Any()).Do(func(settings models.ChatSettings) {
+				*savedSettings = settings
+			}).Return(nil),
+			stMock.EXPECT().GetChatSettings(chatID).Return(savedSettings, nil),
+		)
+		stMock.EXPECT().GetShoppingItems(chatID).Return([]*models.ShoppingItem{
+			{Name: "Milk", Category: category.Dairy},
+			{Name: "Apples", Category: category.Produce},
+		}, nil)
+		expectText(t, "Done!")
+		expectText(t, "<pre>Produce\n1. Apples\n\nDairy\n2. Milk\n</pre>")
+
+		err := handleAisles(clientMock, stMock, newMessageMock("produce, dairy"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+
+		err = handleList(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
+
+func TestFormatShoppingList(t *testing.T) {
+	tr := i18n.NewTranslator("en")
+	items := []*models.ShoppingItem{
+		{Name: "Milk", Category: category.Dairy},
+		{Name: "Bread"},
+		{Name: "Soap", Category: category.Household},
+		{Name: "Cheese", Category: category.Dairy},
+		{Name: "Thing", Category: "unsupported"},
+	}
+
+	t.Run("Grouped items", func(t *testing.T) {
+		text := formatShoppingList(tr, items,
+			category.Order([]string{category.Household}), money.EUR)
+
+		expectedText := "Household\n1. Soap\n\n" +
+			"Bakery\n2. Bread\n\n" +
+			"Dairy\n3. Milk\n4. Cheese\n\n" +
+			"Other\n5. Thing\n"
+		if text != expectedText {
+			t.Errorf("Expected %#v, got %#v", expectedText, text)
+		}
+	})
+
+	t.Run("Without an order", func(t *testing.T) {
+		text := formatShoppingList(tr, items, nil, money.EUR)
+
+		expectedText := "1. Milk\n2. Bread\n3. Soap\n4. Cheese\n5. Thing\n"
+		if text != expectedText {
+			t.Errorf("Expected %#v, got %#v", expectedText, text)
+		}
+	})
+
+	t.Run("Items without categories", func(t *testing.T) {
+		text := formatShoppingList(tr, []*models.ShoppingItem{
+			{Name: "Thing"}, {Name: "Widget"},
+		}, category.DefaultOrder, money.EUR)
+
+		expectedText := "1. Thing\n2. Widget\n"
+		if text != expectedText {
+			t.Errorf("Expected %#v, got %#v", expectedText, text)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// chatMemberCacheTTL defines how long we trust information about chat
+// members. Admins can be promoted or demoted at any time,
+// so we can't cache it for too long
+const chatMemberCacheTTL = 5 * time.Minute
+
+// maxChatMemberCacheEntries is number of cached chat members after which
+// we start removing expired entries
+const maxChatMemberCacheEntries = 10000
+
+type chatMemberCacheKey struct {
+	chatID int64
+	userID int
+}
+
+type chatMemberCacheEntry struct {
+	member    tgbotapi.ChatMember
+	expiresAt time.Time
+}
+
+// chatMemberCache is a bot client decorator that caches
+// responses of the getChatMember method. We check permissions
+// on every destructive command and don't want to hit the API each time
+type chatMemberCache struct {
+	botClientInterface
+	ttl time.Duration
+
+	mu      sync.Mutex
+	members map[chatMemberCacheKey]chatMemberCacheEntry
+
+	now func() time.Time
+}
+
+func newChatMemberCache(client botClientInterface, ttl time.Duration) *chatMemberCache {
+	return &chatMemberCache{
+		botClientInterface: client,
+		ttl:                ttl,
+		members:            map[chatMemberCacheKey]chatMemberCacheEntry{},
+		now:                time.Now,
+	}
+}
+
+// GetChatMember returns a cached chat member or requests it from the API
+func (c *chatMemberCache) GetChatMember(config tgbotapi.ChatConfigWithUser) (tgbotapi.ChatMember, error) {
+	key := chatMemberCacheKey{chatID: config.ChatID, userID: config.UserID}
+
+	c.mu.Lock()
+	entry, ok := c.members[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expiresAt) {
+		return entry.member, nil
+	}
+
+	member, err := c.botClientInterface.GetChatMember(config)
+	if err != nil {
+		return member, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.members) >= maxChatMemberCacheEntries {
+		c.removeExpired(now)
+	}
+	c.members[key] = chatMemberCacheEntry{
+		member:    member,
+		expiresAt: now.Add(c.ttl),
+	}
+
+	return member, nil
+}
+
+func (c *chatMemberCache) removeExpired(now time.Time) {
+	for key, entry := range c.members {
+		if !now.Before(entry.expiresAt) {
+			delete(c.members, key)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package telegram
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+)
+
+func TestChatMemberCache(t *testing.T) {
+	// Interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+
+	// Data mocks
+	errMock := errors.New("fake error")
+	configMock := tgbotapi.ChatConfigWithUser{ChatID: 123, UserID: 321}
+	memberMock := tgbotapi.ChatMember{
+		User:   &tgbotapi.User{ID: configMock.UserID},
+		Status: "administrator",
+	}
+
+	nowMock := time.Date(2018, time.May, 1, 12, 0, 0, 0, time.UTC)
+	cache := newChatMemberCache(clientMock, time.Minute)
+	cache.now = func() time.Time { return nowMock }
+
+	getChatMember := func(t *testing.T, expectedErr error) {
+		member, err := cache.GetChatMember(configMock)
+		if err != expectedErr {
+			t.Fatalf("Expected err %#v, got %#v", expectedErr, err)
+		}
+		if err == nil && member.Status != memberMock.Status {
+			t.Errorf("Expected member %#v, got %#v", memberMock, member)
+		}
+	}
+
+	t.Run("Errors are not cached", func(t *testing.T) {
+		clientMock.EXPECT().GetChatMember(configMock).Return(
+			tgbotapi.ChatMember{}, errMock)
+
+		getChatMember(t, errMock)
+	})
+
+	t.Run("Cache miss", func(t *testing.T) {
+		clientMock.EXPECT().GetChatMember(configMock).Return(memberMock, nil)
+
+		getChatMember(t, nil)
+	})
+
+	t.Run("Cache hit", func(t *testing.T) {
+		nowMock = nowMock.Add(59 * time.Second)
+
+		getChatMember(t, nil)
+	})
+
+	t.Run("Expired entry", func(t *testing.T) {
+		nowMock = nowMock.Add(time.Second)
+		clientMock.EXPECT().GetChatMember(configMock).Return(memberMock, nil)
+
+		getChatMember(t, nil)
+	})
+}
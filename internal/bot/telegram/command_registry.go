@@ -0,0 +1,214 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+)
+
+// Sections of the help message
+const (
+	helpSectionShoppingList = "shopping_list"
+	helpSectionSettings     = "settings"
+)
+
+// helpSections defines order of sections in the help message.
+// Commands without a section are displayed before all sections
+var helpSections = []string{
+	helpSectionShoppingList,
+	helpSectionSettings,
+}
+
+// helpCommandsOrder defines order of commands in the help message
+// and in command suggestions of Telegram clients. Commands register
+// in init functions in order of file names, which means nothing to users.
+// Commands which are missing here go last in order of registration
+var helpCommandsOrder = []string{
+	commandStart,
+	commandHelp,
+	commandAdd,
+	commandList,
+	commandDel,
+	commandClear,
+	commandDedupe,
+	commandCategory,
+	commandAisles,
+	commandSort,
+	commandAssign,
+	commandMine,
+	commandBudget,
+	commandRecipe,
+	commandCook,
+	commandStaples,
+	commandRemind,
+	commandLog,
+	commandStats,
+	commandExport,
+	commandImport,
+	commandLanguage,
+	commandSettings,
+}
+
+// commandArg describes an argument of a command
+type commandArg struct {
+	// name is a key of the argument name
+	// in the "command.arg.<name>" translation
+	name     string
+	optional bool
+}
+
+// commandRegistry keeps all commands the bot supports.
+//
+// Handler modules register their commands in init functions,
+// so we don't need to maintain a list of all commands in one place
+type commandRegistry struct {
+	mu       sync.RWMutex
+	commands []botCommand
+	// names maps command names and aliases to commands
+	names map[string]int
+}
+
+func newCommandRegistry() *commandRegistry {
+	return &commandRegistry{
+		names: map[string]int{},
+	}
+}
+
+// botCommands is the registry used by the bot
+var botCommands = newCommandRegistry()
+
+// registerCommands adds commands to the registry of the bot
+func registerCommands(commands ...botCommand) {
+	botCommands.register(commands...)
+}
+
+// register adds commands to the registry.
+// It panics if a name or an alias is already taken,
+// because it's a programming error
+func (r *commandRegistry) register(commands ...botCommand) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, command := range commands {
+		if command.name == "" {
+			panic("telegram: command without a name")
+		}
+		if command.commandHandler == nil {
+			panic(fmt.Sprintf(
+				"telegram: command %#v without a handler", command.name))
+		}
+
+		index := len(r.commands)
+		for _, name := range append([]string{command.name}, command.aliases...) {
+			if _, ok := r.names[name]; ok {
+				panic(fmt.Sprintf(
+					"telegram: command %#v is already registered", name))
+			}
+			r.names[name] = index
+		}
+		r.commands = append(r.commands, command)
+	}
+}
+
+// mapping returns all commands by their names and aliases.
+// Handlers are wrapped with global middlewares
+// and with middlewares of commands
+func (r *commandRegistry) mapping(global ...middleware) map[string]botCommand {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	mapping := make(map[string]botCommand, len(r.names))
+	for name, index := range r.names {
+		mapping[name] = r.commands[index]
+	}
+	return applyMiddlewares(mapping, global...)
+}
+
+// helpCommands returns commands that should be displayed to users
+// in order of helpCommandsOrder
+func (r *commandRegistry) helpCommands() []botCommand {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var commands []botCommand
+	for _, command := range r.commands {
+		if command.showInHelpMessage {
+			commands = append(commands, command)
+		}
+	}
+
+	sort.SliceStable(commands, func(i, j int) bool {
+		return helpCommandWeight(commands[i].name) < helpCommandWeight(commands[j].name)
+	})
+	return commands
+}
+
+// helpCommandWeight returns a position of a command in helpCommandsOrder
+func helpCommandWeight(name string) int {
+	for i, n := range helpCommandsOrder {
+		if n == name {
+			return i
+		}
+	}
+	return len(helpCommandsOrder)
+}
+
+// usage returns the command with its arguments. For example: "/add [item]"
+func (c botCommand) usage(tr *i18n.Translator) string {
+	usage := "/" + c.name
+	for _, arg := range c.args {
+		name := tr.T("command.arg." + arg.name)
+		if arg.optional {
+			usage += " [" + name + "]"
+		} else {
+			usage += " <" + name + ">"
+		}
+	}
+	return usage
+}
+
+// apiBotCommand is a command in the format of the setMyCommands method
+type apiBotCommand struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+}
+
+// publishCommands pushes commands to Telegram, so clients can suggest
+// them to users. Commands are published for every supported language
+var publishCommands = func(client requestMaker, registry *commandRegistry) error {
+	for _, locale := range i18n.Locales() {
+		tr := i18n.NewTranslator(locale)
+
+		var commands []apiBotCommand
+		for _, command := range registry.helpCommands() {
+			commands = append(commands, apiBotCommand{
+				Command:     command.name,
+				Description: tr.T(command.description),
+			})
+		}
+
+		data, err := json.Marshal(commands)
+		if err != nil {
+			return err
+		}
+
+		params := url.Values{}
+		params.Set("commands", string(data))
+		// Commands in the default language are displayed
+		// to users whose language we don't support
+		if locale != i18n.DefaultLocale {
+			params.Set("language_code", locale)
+		}
+
+		if _, err := client.MakeRequest("setMyCommands", params); err != nil {
+			return fmt.Errorf("Unable to publish commands (locale=%s): %v",
+				locale, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package telegram
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+func TestCommandRegistry(t *testing.T) {
+	// Common function mocks
+	handlerMock := func(
+		_ botClientInterface, _ storage.DataStorageInterface, _ *tgbotapi.Message,
+	) error {
+		return nil
+	}
+
+	// expectPanic fails the test if register doesn't panic
+	expectPanic := func(t *testing.T, registry *commandRegistry, command botCommand) {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected register to panic")
+			}
+		}()
+		registry.register(command)
+	}
+
+	registry := newCommandRegistry()
+	registry.register(
+		botCommand{
+			name:              commandHelp,
+			aliases:           []string{commandStart},
+			showInHelpMessage: true,
+			commandHandler:    handlerMock,
+		},
+		botCommand{
+			name:           "hidden",
+			commandHandler: handlerMock,
+		},
+		botCommand{
+			name:              commandAdd,
+			showInHelpMessage: true,
+			commandHandler:    handlerMock,
+		},
+	)
+
+	t.Run("Mapping", func(t *testing.T) {
+		mapping := registry.mapping()
+
+		for _, name := range []string{commandHelp, commandStart, "hidden", commandAdd} {
+			if _, ok := mapping[name]; !ok {
+				t.Errorf("Command %#v wasn't found in the mapping", name)
+			}
+		}
+
+		if mapping[commandStart].name != commandHelp {
+			t.Errorf("Expected the %#v alias to point to %#v, got %#v",
+				commandStart, commandHelp, mapping[commandStart].name)
+		}
+	})
+
+	t.Run("Help commands", func(t *testing.T) {
+		var names []string
+		for _, command := range registry.helpCommands() {
+			names = append(names, command.name)
+		}
+
+		expected := []string{commandHelp, commandAdd}
+		if strings.Join(names, " ") != strings.Join(expected, " ") {
+			t.Errorf("Expected commands %v, got %v", expected, names)
+		}
+	})
+
+	t.Run("Help commands in order", func(t *testing.T) {
+		registry := newCommandRegistry()
+		registry.register(
+			botCommand{name: "unordered", showInHelpMessage: true, commandHandler: handlerMock},
+			botCommand{name: commandSettings, showInHelpMessage: true, commandHandler: handlerMock},
+			botCommand{name: commandAdd, showInHelpMessage: true, commandHandler: handlerMock},
+			botCommand{name: commandHelp, showInHelpMessage: true, commandHandler: handlerMock},
+		)
+
+		var names []string
+		for _, command := range registry.helpCommands() {
+			names = append(names, command.name)
+		}
+
+		expected := []string{commandHelp, commandAdd, commandSettings, "unordered"}
+		if strings.Join(names, " ") != strings.Join(expected, " ") {
+			t.Errorf("Expected commands %v, got %v", expected, names)
+		}
+	})
+
+	t.Run("Invalid commands", func(t *testing.T) {
+		testCases := []struct {
+			testName string
+			command  botCommand
+		}{
+			{
+				testName: "Without name",
+				command:  botCommand{commandHandler: handlerMock},
+			},
+			{
+				testName: "Without handler",
+				command:  botCommand{name: "new"},
+			},
+			{
+				testName: "Taken name",
+				command:  botCommand{name: commandAdd, commandHandler: handlerMock},
+			},
+			{
+				testName: "Taken alias",
+				command: botCommand{
+					name:           "new",
+					aliases:        []string{commandStart},
+					commandHandler: handlerMock,
+				},
+			},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.testName, func(t *testing.T) {
+				expectPanic(t, registry, testCase.command)
+			})
+		}
+	})
+}
+
+func TestBotCommandUsage(t *testing.T) {
+	tr := i18n.NewTranslator("en")
+
+	testCases := []struct {
+		command  botCommand
+		expected string
+	}{
+		{
+			command:  botCommand{name: commandList},
+			expected: "/list",
+		},
+		{
+			command: botCommand{
+				name: commandAdd,
+				args: []commandArg{{name: "item", optional: true}},
+			},
+			expected: "/add [item]",
+		},
+		{
+			command: botCommand{
+				name: commandLanguage,
+				args: []commandArg{{name: "language"}},
+			},
+			expected: "/language <language>",
+		},
+	}
+
+	for _, testCase := range testCases {
+		if usage := testCase.command.usage(tr); usage != testCase.expected {
+			t.Errorf("Expected %#v, got %#v", testCase.expected, usage)
+		}
+	}
+}
+
+func TestRegisteredCommands(t *testing.T) {
+	for _, command := range botCommands.helpCommands() {
+		if helpCommandWeight(command.name) == len(helpCommandsOrder) {
+			t.Errorf("Command %#v is missing in helpCommandsOrder", command.name)
+		}
+
+		for _, locale := range i18n.Locales() {
+			tr := i18n.NewTranslator(locale)
+
+			// Untranslated keys are rendered as is
+			if description := tr.T(command.description); description == command.description {
+				t.Errorf("%s: command %#v has no description", locale, command.name)
+			}
+
+			for _, arg := range command.args {
+				key := "command.arg." + arg.name
+				if tr.T(key) == key {
+					t.Errorf("%s: argument %#v of the command %#v has no name",
+						locale, arg.name, command.name)
+				}
+			}
+		}
+	}
+}
+
+func TestPublishCommands(t *testing.T) {
+	// Interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockrequestMaker(mockCtrl)
+
+	// Data mocks
+	errMock := errors.New("fake error")
+	registry := newCommandRegistry()
+	registry.register(
+		botCommand{
+			name:              commandAdd,
+			description:       "command.add.description",
+			showInHelpMessage: true,
+			commandHandler:    handleAdd,
+		},
+		botCommand{
+			name:           "hidden",
+			commandHandler: handleAdd,
+		},
+	)
+
+	t.Run("Success", func(t *testing.T) {
+		var languageCodes []string
+		clientMock.EXPECT().MakeRequest("setMyCommands", gomock.Any()).Do(
+			func(_ string, params url.Values) {
+				languageCodes = append(languageCodes, params.Get("language_code"))
+
+				tr := i18n.NewTranslator(params.Get("language_code"))
+				expected, _ := json.Marshal([]apiBotCommand{
+					{Command: commandAdd, Description: tr.T("command.add.description")},
+				})
+				if params.Get("commands") != string(expected) {
+					t.Errorf("Expected commands %s, got %s",
+						expected, params.Get("commands"))
+				}
+			}).Times(len(i18n.Locales()))
+
+		err := publishCommands(clientMock, registry)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+
+		// The default locale is published without a language code
+		if len(languageCodes) == 0 || languageCodes[0] != "" {
+			t.Errorf("Expected default commands to be published first, got %v",
+				languageCodes)
+		}
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		clientMock.EXPECT().MakeRequest("setMyCommands", gomock.Any()).Return(
+			tgbotapi.APIResponse{}, errMock)
+
+		err := publishCommands(clientMock, registry)
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+}
@@ -16,7 +16,10 @@
 //
 //    For example, the `/del` command handler sends a message with an inline
 //    keybaord that asks an users to confirm what item they want to delete
-//    from their shopping list.
+//    from their shopping list;
+//
+//  * Chosen inline result handlers - To handle results of inline queries
+//    which users chose. IDs of results are names of commands.
 
 package telegram
 
@@ -34,58 +37,63 @@ const (
 	commandList  = "list"
 	commandDel   = "del"
 	commandClear = "clear"
+
+	commandDedupe   = "dedupe"
+	commandCategory = "category"
+	commandAisles   = "aisles"
+	commandSort     = "sort"
+	commandStaples  = "staples"
+	commandRemind   = "remind"
+	commandLog      = "log"
+	commandStats    = "stats"
+	commandExport   = "export"
+	commandImport   = "import"
+	commandRecipe   = "recipe"
+	commandCook     = "cook"
+	commandBudget   = "budget"
+	commandAssign   = "assign"
+	commandMine     = "mine"
+
+	commandLanguage = "language"
+	commandSettings = "settings"
 )
 
 // botCommand defines command and it's handlers
 type botCommand struct {
-	description              string
-	showInHelpMessage        bool
-	commandHandler           commandHandlerFunc
-	unfinishedCommandHandler commandHandlerFunc
-	callbackQueryHandler     callbackQueryHandlerFunc
+	name    string
+	aliases []string
+	// description is a translation key of the command description
+	description string
+	// args are displayed in the help message
+	args              []commandArg
+	helpSection       string
+	showInHelpMessage bool
+	// permission is checked before calling command
+	// and callback query handlers
+	permission permission
+	// middlewares wrap all handlers of the command.
+	// They are called after global middlewares
+	middlewares               []middleware
+	commandHandler            commandHandlerFunc
+	unfinishedCommandHandler  commandHandlerFunc
+	callbackQueryHandler      callbackQueryHandlerFunc
+	chosenInlineResultHandler chosenInlineResultHandlerFunc
+}
+
+// globalMiddlewares wrap handlers of all commands.
+// recoverMiddleware must go first to recover from panics
+// in other middlewares too
+var globalMiddlewares = []middleware{
+	recoverMiddleware,
 }
 
 var getBotCommandsMapping = func() map[string]botCommand {
-	return map[string]botCommand{
-		// The `/start` command is implicit: Telegram sends on user's behalf
-		// when they start the bot.
-		commandStart: {
-			commandHandler: handleStart,
-		},
-		commandHelp: {
-			description:       "Show the list of available commands and short descriptions",
-			showInHelpMessage: true,
-			commandHandler:    handleStart,
-		},
-		commandAdd: {
-			description:              "Add an item into your shopping list",
-			showInHelpMessage:        true,
-			commandHandler:           handleAdd,
-			unfinishedCommandHandler: handleAddSession,
-		},
-		commandList: {
-			description:       "Display items the shopping list",
-			showInHelpMessage: true,
-			commandHandler:    handleList,
-		},
-		commandDel: {
-			description:          "Delete an item from your shopping list",
-			showInHelpMessage:    true,
-			commandHandler:       handleDel,
-			callbackQueryHandler: handleDelCallbackQuery,
-		},
-		commandClear: {
-			description:          "Delete all items from the shopping list",
-			showInHelpMessage:    true,
-			commandHandler:       handleClear,
-			callbackQueryHandler: handleClearCallbackQuery,
-		},
-	}
+	return botCommands.mapping(globalMiddlewares...)
 }
 
 // commandHandlerFunc defines required signature for a command handler func
 type commandHandlerFunc func(
-	client sender, st storage.DataStorageInterface, message *tgbotapi.Message,
+	client botClientInterface, st storage.DataStorageInterface, message *tgbotapi.Message,
 ) error
 
 // callbackQueryHandlerFunc defines required signature for a callback query func
@@ -95,3 +103,19 @@ type callbackQueryHandlerFunc func(
 	callbackQuery *tgbotapi.CallbackQuery,
 	data string,
 ) error
+
+// chosenInlineResultHandlerFunc defines required signature
+// for a chosen inline result handler func
+type chosenInlineResultHandlerFunc func(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	chosenInlineResult *tgbotapi.ChosenInlineResult,
+) error
+
+// inlineQueryHandlerFunc defines required signature
+// for an inline query handler func
+type inlineQueryHandlerFunc func(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	inlineQuery *tgbotapi.InlineQuery,
+) error
@@ -8,10 +8,18 @@ import (
 func TestGetBotCommandsMapping(t *testing.T) {
 	allExpectedCommands := []string{
 		commandStart, commandHelp, commandAdd, commandList,
-		commandDel, commandClear,
+		commandDel, commandDedupe, commandClear, commandCategory, commandAisles,
+		commandSort, commandStaples, commandRemind, commandLog, commandStats,
+		commandExport, commandImport, commandRecipe, commandCook,
+		commandBudget, commandAssign, commandMine, commandLanguage,
+		commandSettings,
+	}
+	commandsWithUnfinishedCommandHandler := []string{commandAdd, commandImport}
+	commandsWithCallbackQueryHandler := []string{
+		commandAdd, commandDel, commandClear, commandSort, commandStaples,
+		commandRemind, commandImport, commandRecipe, commandAssign,
+		commandLanguage, commandSettings,
 	}
-	commandsWithUnfinishedCommandHandler := []string{commandAdd}
-	commandsWithCallbackQueryHandler := []string{commandDel, commandClear}
 
 	mapping := getBotCommandsMapping()
 
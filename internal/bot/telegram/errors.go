@@ -1,6 +1,11 @@
 package telegram
 
-import "errors"
+import (
+	"errors"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
 
 // updateRoutingError represents errors that can happenen during update routing
 type updateRoutingError struct {
@@ -9,3 +14,81 @@ type updateRoutingError struct {
 
 var errCommandIsNotSupported = updateRoutingError{
 	errors.New("Unable to find a handler for a command")}
+
+// keyboardError means that a handler was unable to hide an inline keyboard.
+// Handlers return it after they have done their job and replied,
+// so routers only log it
+type keyboardError struct {
+	error
+}
+
+// apiErrorKind classifies errors returned by the Telegram Bot API
+type apiErrorKind int
+
+const (
+	// apiErrorUnknown is any other error, including network errors
+	apiErrorUnknown apiErrorKind = iota
+
+	// apiErrorBlockedByUser means that the bot can't write into a chat anymore:
+	// an user blocked it or the bot was kicked from a group
+	apiErrorBlockedByUser
+
+	// apiErrorChatNotFound means that a chat doesn't exist anymore
+	apiErrorChatNotFound
+
+	// apiErrorBadRequest means that we've sent an invalid request.
+	// For example, a message with broken markup
+	apiErrorBadRequest
+
+	// apiErrorFloodWait means that we've exceeded the rate limit
+	apiErrorFloodWait
+)
+
+func (kind apiErrorKind) String() string {
+	switch kind {
+	case apiErrorBlockedByUser:
+		return "blocked_by_user"
+	case apiErrorChatNotFound:
+		return "chat_not_found"
+	case apiErrorBadRequest:
+		return "bad_request"
+	case apiErrorFloodWait:
+		return "flood_wait"
+	}
+	return "unknown"
+}
+
+// classifyAPIError returns a kind of an error returned by the Telegram Bot API.
+//
+// The API client doesn't expose error codes, so we have to rely on
+// the error description which starts with a HTTP status text.
+// For example: "Forbidden: bot was blocked by the user"
+func classifyAPIError(err error) apiErrorKind {
+	apiErr, ok := err.(tgbotapi.Error)
+	if !ok {
+		return apiErrorUnknown
+	}
+
+	if apiErr.RetryAfter > 0 {
+		return apiErrorFloodWait
+	}
+
+	switch {
+	case strings.HasPrefix(apiErr.Message, "Too Many Requests"):
+		return apiErrorFloodWait
+	case strings.HasPrefix(apiErr.Message, "Forbidden"):
+		return apiErrorBlockedByUser
+	case strings.Contains(apiErr.Message, "chat not found"):
+		return apiErrorChatNotFound
+	case strings.HasPrefix(apiErr.Message, "Bad Request"):
+		return apiErrorBadRequest
+	}
+	return apiErrorUnknown
+}
+
+// isMessageNotModifiedError checks if an error was returned, because we've
+// tried to edit a message without changing it. It's safe to ignore this error.
+func isMessageNotModifiedError(err error) bool {
+	return classifyAPIError(err) == apiErrorBadRequest &&
+		strings.Contains(err.Error(), "message is not modified")
+}
@@ -0,0 +1,92 @@
+package telegram
+
+import (
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestClassifyAPIError(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected apiErrorKind
+	}{
+		{
+			err:      errors.New("Network error"),
+			expected: apiErrorUnknown,
+		},
+		{
+			err:      tgbotapi.Error{Message: "Internal Server Error"},
+			expected: apiErrorUnknown,
+		},
+		{
+			err:      tgbotapi.Error{Message: "Forbidden: bot was blocked by the user"},
+			expected: apiErrorBlockedByUser,
+		},
+		{
+			err:      tgbotapi.Error{Message: "Forbidden: bot was kicked from the group chat"},
+			expected: apiErrorBlockedByUser,
+		},
+		{
+			err:      tgbotapi.Error{Message: "Bad Request: chat not found"},
+			expected: apiErrorChatNotFound,
+		},
+		{
+			err:      tgbotapi.Error{Message: "Bad Request: can't parse entities"},
+			expected: apiErrorBadRequest,
+		},
+		{
+			err:      tgbotapi.Error{Message: "Too Many Requests: retry after 10"},
+			expected: apiErrorFloodWait,
+		},
+		{
+			err: tgbotapi.Error{
+				Message:            "Some error",
+				ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 10},
+			},
+			expected: apiErrorFloodWait,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.err.Error(), func(t *testing.T) {
+			actual := classifyAPIError(testCase.err)
+			if actual != testCase.expected {
+				t.Errorf("Expected %s, got %s", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestIsMessageNotModifiedError(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected bool
+	}{
+		{
+			err:      tgbotapi.Error{Message: "Bad Request: message is not modified"},
+			expected: true,
+		},
+		{
+			err:      tgbotapi.Error{Message: "Bad Request: chat not found"},
+			expected: false,
+		},
+		{
+			err:      errors.New("message is not modified"),
+			expected: false,
+		},
+		{
+			err:      nil,
+			expected: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual := isMessageNotModifiedError(testCase.err)
+		if actual != testCase.expected {
+			t.Errorf("Expected %v for %#v, got %v",
+				testCase.expected, testCase.err, actual)
+		}
+	}
+}
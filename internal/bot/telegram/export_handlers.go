@@ -0,0 +1,79 @@
+package telegram
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/export"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+func init() {
+	registerCommands(botCommand{
+		name:        commandExport,
+		description: "command.export.description",
+		args: []commandArg{
+			{name: "format", optional: true},
+		},
+		helpSection:       helpSectionShoppingList,
+		showInHelpMessage: true,
+		commandHandler:    handleExport,
+	})
+}
+
+// handleExport sends the shopping list as a file:
+// `/export`, `/export json`, `/export md`
+func handleExport(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	chatID := message.Chat.ID
+
+	tr, err := userTranslator(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	format, err := export.ParseFormat(strings.TrimSpace(message.CommandArguments()))
+	if err != nil {
+		names := make([]string, len(export.Formats))
+		for i, format := range export.Formats {
+			names[i] = string(format)
+		}
+
+		text := tr.T("export.invalid", message.CommandArguments(), strings.Join(names, ", "))
+		msg := tgbotapi.NewMessage(chatID, text)
+		_, err = client.Send(msg)
+		return err
+	}
+
+	items, err := export.Load(st, chatID)
+	if err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		msg := tgbotapi.NewMessage(chatID, tr.T("list.empty"))
+		_, err = client.Send(msg)
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format, items); err != nil {
+		return fmt.Errorf(
+			"Unable to export the shopping list (ChatID=%d, Format=%s): %v",
+			chatID, format, err)
+	}
+
+	doc := tgbotapi.NewDocumentUpload(chatID, tgbotapi.FileBytes{
+		Name:  format.FileName(),
+		Bytes: buf.Bytes(),
+	})
+	doc.Caption = tr.N("export.done", len(items), len(items))
+	_, err = client.Send(doc)
+	return err
+}
@@ -0,0 +1,93 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+func TestHandleExport(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Common data mocks
+	chatID := int64(123)
+	createdAt := time.Date(2018, 10, 22, 7, 0, 0, 0, time.UTC)
+	newMessageMock := func(args string) *tgbotapi.Message {
+		messageMock := mock_telegram.MessageCommandMockSetup(commandExport, args)
+		messageMock.Chat = &tgbotapi.Chat{ID: chatID}
+		messageMock.From = &tgbotapi.User{ID: 321}
+		return messageMock
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(chatID).Return([]*models.ShoppingItem{
+			{ID: 1, ChatID: chatID, Name: "milk", CreatedBy: 321, CreatedAt: &createdAt},
+		}, nil)
+		stMock.EXPECT().GetActorNames(chatID).Return(map[int]string{321: "Alice"}, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(doc tgbotapi.DocumentConfig) {
+			file, ok := doc.File.(tgbotapi.FileBytes)
+			if !ok {
+				t.Fatalf("Expected file bytes, got %#v", doc.File)
+			}
+
+			if file.Name != "shopping-list.json" {
+				t.Errorf("Expected shopping-list.json, got %#v", file.Name)
+			}
+
+			if !strings.Contains(string(file.Bytes), `"author": "Alice"`) {
+				t.Errorf("Expected the author in the file, got %s", file.Bytes)
+			}
+
+			expectedCaption := "Your shopping list with 1 item"
+			if doc.Caption != expectedCaption {
+				t.Errorf("Expected caption %#v, got %#v", expectedCaption, doc.Caption)
+			}
+		})
+
+		err := handleExport(clientMock, stMock, newMessageMock("JSON"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Empty list", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(chatID).Return(nil, nil)
+		stMock.EXPECT().GetActorNames(chatID).Return(nil, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.HasPrefix(msgCfg.Text, "Your shopping list is empty") {
+				t.Errorf("Unexpected text %#v", msgCfg.Text)
+			}
+		})
+
+		err := handleExport(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			expectedText := "Sorry, I can't export the list as \"xlsx\". Supported formats: csv, json, md."
+			if msgCfg.Text != expectedText {
+				t.Errorf("Expected %#v, got %#v", expectedText, msgCfg.Text)
+			}
+		})
+
+		err := handleExport(clientMock, stMock, newMessageMock("xlsx"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
@@ -0,0 +1,371 @@
+package telegram
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/category"
+	"github.com/m1kola/shipsterbot/internal/pkg/export"
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// Callback query data of the import preview
+const (
+	importCallbackDataConfirm = "ok"
+	importCallbackDataCancel  = "cancel"
+)
+
+// Limits of imports
+const (
+	importMaxFileSize  = 1 << 20
+	importMaxItems     = 200
+	importPreviewItems = 20
+	// importTTL is how long users can confirm an import.
+	// Older previews can be far from the current list
+	importTTL = 15 * time.Minute
+)
+
+// importHTTPClient downloads imported files from Telegram servers
+var importHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+func init() {
+	registerCommands(botCommand{
+		name:        commandImport,
+		description: "command.import.description",
+		args: []commandArg{
+			{name: "items", optional: true},
+		},
+		helpSection:              helpSectionShoppingList,
+		showInHelpMessage:        true,
+		commandHandler:           handleImport,
+		unfinishedCommandHandler: handleImportSession,
+		callbackQueryHandler:     handleImportCallbackQuery,
+	})
+}
+
+// handleImport imports items pasted after the command
+// or asks users to send a file or a list of items
+func handleImport(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	if message.CommandArguments() != "" {
+		return handleImportSession(client, st, message)
+	}
+
+	tr, err := userTranslator(st, message.Chat.ID, message.From)
+	if err != nil {
+		return err
+	}
+
+	err = st.AddUnfinishedCommand(models.UnfinishedCommand{
+		Command:   commandImport,
+		ChatID:    message.Chat.ID,
+		CreatedBy: message.From.ID,
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to create an unfinished comamnd (%v): %v",
+			commandImport, err)
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, tr.T("import.prompt"))
+
+	// We can't listen all messages in groups,
+	// so users have to reply to the prompt
+	if !message.Chat.IsPrivate() {
+		msg.ReplyMarkup = tgbotapi.ForceReply{
+			ForceReply: true,
+			Selective:  true,
+		}
+	}
+
+	_, err = client.Send(msg)
+	return err
+}
+
+// handleImportSession reads items from a document or
+// from text of the message and asks users to confirm the import
+func handleImportSession(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	chatID := message.Chat.ID
+
+	tr, err := userTranslator(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	var items []export.Item
+	if message.Document != nil {
+		if message.Document.FileSize > importMaxFileSize {
+			msg := tgbotapi.NewMessage(chatID,
+				tr.T("import.too_large", importMaxFileSize>>10))
+			_, err = client.Send(msg)
+			return err
+		}
+
+		data, err := downloadImportFile(client, message.Document.FileID)
+		if err != nil {
+			return err
+		}
+
+		format := export.DetectFormat(message.Document.FileName)
+		items, err = export.Read(bytes.NewReader(data), format)
+		if err != nil {
+			return sendImportInvalid(client, tr, chatID)
+		}
+	} else {
+		text := message.Text
+		if message.IsCommand() {
+			text = message.CommandArguments()
+		}
+
+		items, err = export.Read(strings.NewReader(text), export.FormatText)
+		if err != nil {
+			return sendImportInvalid(client, tr, chatID)
+		}
+	}
+
+	if len(items) == 0 {
+		return sendImportInvalid(client, tr, chatID)
+	}
+
+	if len(items) > importMaxItems {
+		msg := tgbotapi.NewMessage(chatID,
+			tr.N("import.too_many", importMaxItems, importMaxItems))
+		_, err = client.Send(msg)
+		return err
+	}
+
+	states := make([]models.ItemState, len(items))
+	for i, item := range items {
+		states[i] = models.ItemState{
+			Name:     item.Name,
+			Quantity: item.Quantity,
+			Category: item.Category,
+		}
+	}
+
+	err = st.SetPendingImport(models.PendingImport{
+		ChatID: chatID,
+		UserID: message.From.ID,
+		Items:  states,
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to save a pending import (ChatID=%d, UserID=%d): %v",
+			chatID, message.From.ID, err)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, importPreview(tr, states))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				tr.T("import.confirm"),
+				joinCallbackQueryData(commandImport, importCallbackDataConfirm)),
+			tgbotapi.NewInlineKeyboardButtonData(
+				tr.T("import.cancel"),
+				joinCallbackQueryData(commandImport, importCallbackDataCancel)),
+		),
+	)
+	_, err = client.Send(msg)
+	return err
+}
+
+// handleImportCallbackQuery adds pending items into the shopping list
+// or cancels the import. Only the user who imports items can confirm them
+func handleImportCallbackQuery(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	callbackQuery *tgbotapi.CallbackQuery,
+	data string,
+) error {
+	client.AnswerCallbackQuery(tgbotapi.NewCallback(
+		callbackQuery.ID, ""))
+
+	chatID := callbackQuery.Message.Chat.ID
+	messageID := callbackQuery.Message.MessageID
+	userID := callbackQuery.From.ID
+
+	if data != importCallbackDataConfirm && data != importCallbackDataCancel {
+		return fmt.Errorf(
+			"Unable to parse confirmation from the CallbackQuery data %#v",
+			data)
+	}
+
+	tr, err := userTranslator(st, chatID, callbackQuery.From)
+	if err != nil {
+		return err
+	}
+
+	pendingImport, err := st.GetPendingImport(chatID, userID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get a pending import (ChatID=%d, UserID=%d): %v",
+			chatID, userID, err)
+	}
+
+	// Someone else tapped the button, or the import is finished already
+	if pendingImport == nil {
+		return nil
+	}
+
+	if err := st.DeletePendingImport(chatID, userID); err != nil {
+		return fmt.Errorf(
+			"Unable to delete a pending import (ChatID=%d, UserID=%d): %v",
+			chatID, userID, err)
+	}
+
+	text := tr.T("import.canceled")
+	if data == importCallbackDataConfirm && isImportExpired(pendingImport) {
+		text = tr.T("import.expired")
+	} else if data == importCallbackDataConfirm {
+		added, skipped, err := addImportedItems(st, chatID, userID, pendingImport.Items)
+		if err != nil {
+			return err
+		}
+
+		text = tr.N("import.done", added, added)
+		if skipped > 0 {
+			text += " " + tr.N("import.skipped", skipped, skipped)
+		}
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	return sendAfterHidingKeyboard(client, chatID, messageID, msg)
+}
+
+// isImportExpired returns true, if the import
+// was not confirmed within importTTL
+func isImportExpired(pendingImport *models.PendingImport) bool {
+	if pendingImport.CreatedAt == nil {
+		return false
+	}
+	return timeNow().Sub(*pendingImport.CreatedAt) > importTTL
+}
+
+// addImportedItems adds items into the shopping list.
+// Items which are already in the list are skipped
+func addImportedItems(
+	st storage.DataStorageInterface,
+	chatID int64,
+	userID int,
+	states []models.ItemState,
+) (added, skipped int, err error) {
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return 0, 0, fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	for _, state := range states {
+		if findDuplicateItem(chatItems, state.Name) != nil {
+			skipped++
+			continue
+		}
+
+		item, err := newShoppingItem(st, chatID, state.Name, userID)
+		if err != nil {
+			return added, skipped, err
+		}
+
+		// Single items are stored without quantity
+		if state.Quantity > 1 {
+			item.Quantity = state.Quantity
+		}
+		if category.IsSupported(state.Category) {
+			item.Category = state.Category
+		}
+
+		if err := st.AddShoppingItemIntoShoppingList(item); err != nil {
+			return added, skipped, fmt.Errorf(
+				"Unable to add an imported item into the shopping list (ChatID=%d): %v",
+				chatID, err)
+		}
+
+		chatItems = append(chatItems, &item)
+		added++
+	}
+	return added, skipped, nil
+}
+
+// downloadImportFile downloads a file which an user sent to the bot
+func downloadImportFile(client fileURLGetter, fileID string) ([]byte, error) {
+	url, err := client.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Unable to get an URL of the file (FileID=%s): %v",
+			fileID, err)
+	}
+
+	data, err := downloadFile(url, importMaxFileSize)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Unable to download the file (FileID=%s): %v",
+			fileID, err)
+	}
+	return data, nil
+}
+
+// downloadFile downloads a file which is not larger than maxSize.
+//
+// URLs of files contain the bot token, so errors must not contain the URL
+var downloadFile = func(url string, maxSize int64) ([]byte, error) {
+	resp, err := importHTTPClient.Get(url)
+	if err != nil {
+		if urlErr, ok := err.(*neturl.Error); ok {
+			return nil, urlErr.Err
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected status %s", resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("The file is larger than %d bytes", maxSize)
+	}
+	return data, nil
+}
+
+// importPreview returns text which shows items
+// that will be added into the list after confirmation
+func importPreview(tr *i18n.Translator, states []models.ItemState) string {
+	lines := []string{tr.N("import.preview", len(states), len(states))}
+	for i, state := range states {
+		if i == importPreviewItems {
+			rest := len(states) - importPreviewItems
+			lines = append(lines, tr.N("import.more", rest, rest))
+			break
+		}
+		lines = append(lines, "• "+formatItemStates([]models.ItemState{state}))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func sendImportInvalid(client sender, tr *i18n.Translator, chatID int64) error {
+	msg := tgbotapi.NewMessage(chatID, tr.T("import.invalid"))
+	_, err := client.Send(msg)
+	return err
+}
@@ -0,0 +1,334 @@
+package telegram
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+func TestHandleImport(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Common data mocks
+	chatID := int64(123)
+	userID := 321
+	newMessageMock := func(args string) *tgbotapi.Message {
+		messageMock := mock_telegram.MessageCommandMockSetup(commandImport, args)
+		messageMock.Chat = &tgbotapi.Chat{ID: chatID, Type: "private"}
+		messageMock.From = &tgbotapi.User{ID: userID}
+		return messageMock
+	}
+
+	expectPreview := func(t *testing.T, expectedText string) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if msgCfg.Text != expectedText {
+				t.Errorf("Expected %#v, got %#v", expectedText, msgCfg.Text)
+			}
+
+			keyboard, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+			if !ok {
+				t.Fatalf("Expected an inline keyboard, got %#v", msgCfg.ReplyMarkup)
+			}
+
+			data := keyboardCallbackData(keyboard)
+			expectedData := []string{"import:ok", "import:cancel"}
+			if !reflect.DeepEqual(data, expectedData) {
+				t.Errorf("Expected callback data %v, got %v", expectedData, data)
+			}
+		})
+	}
+
+	t.Run("Without arguments", func(t *testing.T) {
+		stMock.EXPECT().AddUnfinishedCommand(models.UnfinishedCommand{
+			Command:   commandImport,
+			ChatID:    chatID,
+			CreatedBy: userID,
+		}).Return(nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.HasPrefix(msgCfg.Text, "Send me a file") {
+				t.Errorf("Unexpected text %#v", msgCfg.Text)
+			}
+		})
+
+		err := handleImport(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Pasted items", func(t *testing.T) {
+		stMock.EXPECT().SetPendingImport(models.PendingImport{
+			ChatID: chatID,
+			UserID: userID,
+			Items: []models.ItemState{
+				{Name: "milk", Quantity: 1},
+				{Name: "eggs", Quantity: 2},
+			},
+		}).Return(nil)
+		expectPreview(t, "I'll add 2 items into your shopping list:\n• milk\n• 2 × eggs")
+
+		err := handleImport(clientMock, stMock, newMessageMock("milk\n- 2 x eggs"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("File", func(t *testing.T) {
+		// Mock: downloadFile
+		oldDownloadFile := downloadFile
+		defer func() { downloadFile = oldDownloadFile }()
+		downloadFile = func(url string, maxSize int64) ([]byte, error) {
+			if url != "https://example.com/file.csv" {
+				t.Errorf("Unexpected URL %#v", url)
+			}
+			return []byte("name,quantity,category\nmilk,3,dairy\n"), nil
+		}
+
+		messageMock := newMessageMock("")
+		messageMock.Text = ""
+		messageMock.Entities = nil
+		messageMock.Document = &tgbotapi.Document{
+			FileID:   "file-id",
+			FileName: "list.csv",
+			FileSize: 100,
+		}
+
+		clientMock.EXPECT().GetFileDirectURL("file-id").Return(
+			"https://example.com/file.csv", nil)
+		stMock.EXPECT().SetPendingImport(models.PendingImport{
+			ChatID: chatID,
+			UserID: userID,
+			Items:  []models.ItemState{{Name: "milk", Quantity: 3, Category: "dairy"}},
+		}).Return(nil)
+		expectPreview(t, "I'll add 1 item into your shopping list:\n• 3 × milk")
+
+		err := handleImportSession(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Too large file", func(t *testing.T) {
+		messageMock := newMessageMock("")
+		messageMock.Text = ""
+		messageMock.Entities = nil
+		messageMock.Document = &tgbotapi.Document{
+			FileID:   "file-id",
+			FileName: "list.csv",
+			FileSize: importMaxFileSize + 1,
+		}
+
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			expectedText := "Sorry, the file is too large. I can import files up to 1024 KB."
+			if msgCfg.Text != expectedText {
+				t.Errorf("Expected %#v, got %#v", expectedText, msgCfg.Text)
+			}
+		})
+
+		err := handleImportSession(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Invalid file", func(t *testing.T) {
+		// Mock: downloadFile
+		oldDownloadFile := downloadFile
+		defer func() { downloadFile = oldDownloadFile }()
+		downloadFile = func(url string, maxSize int64) ([]byte, error) {
+			return []byte(`["milk"]`), nil
+		}
+
+		messageMock := newMessageMock("")
+		messageMock.Text = ""
+		messageMock.Entities = nil
+		messageMock.Document = &tgbotapi.Document{FileID: "file-id", FileName: "list.json"}
+
+		clientMock.EXPECT().GetFileDirectURL("file-id").Return(
+			"https://example.com/file.json", nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.HasPrefix(msgCfg.Text, "Sorry, I can't find items there.") {
+				t.Errorf("Unexpected text %#v", msgCfg.Text)
+			}
+		})
+
+		err := handleImportSession(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
+
+func TestHandleImportCallbackQuery(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+	clientMock.EXPECT().AnswerCallbackQuery(gomock.Any()).AnyTimes()
+
+	// Common data mocks
+	chatID := int64(123)
+	userID := 321
+	callbackQueryMock := &tgbotapi.CallbackQuery{
+		ID:   "some-callback-id",
+		From: &tgbotapi.User{ID: userID},
+		Message: &tgbotapi.Message{
+			MessageID: 456,
+			Chat:      &tgbotapi.Chat{ID: chatID},
+		},
+	}
+	createdAtMock := time.Now()
+	pendingImportMock := &models.PendingImport{
+		ChatID:    chatID,
+		UserID:    userID,
+		CreatedAt: &createdAtMock,
+		Items: []models.ItemState{
+			{Name: "Milk", Quantity: 1},
+			{Name: "eggs", Quantity: 2, Category: "dairy"},
+			{Name: "bread", Quantity: 1, Category: "unknown"},
+		},
+	}
+
+	expectText := func(t *testing.T, expectedText string) {
+		gomock.InOrder(
+			clientMock.EXPECT().Send(gomock.Any()).Do(
+				generateSendHideKeybaordCallChecker(t, callbackQueryMock)),
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				if msgCfg.Text != expectedText {
+					t.Errorf("Expected %#v, got %#v", expectedText, msgCfg.Text)
+				}
+			}),
+		)
+	}
+
+	t.Run("Confirm", func(t *testing.T) {
+		stMock.EXPECT().GetPendingImport(chatID, userID).Return(pendingImportMock, nil)
+		stMock.EXPECT().DeletePendingImport(chatID, userID).Return(nil)
+		stMock.EXPECT().GetShoppingItems(chatID).Return([]*models.ShoppingItem{
+			{ID: 1, ChatID: chatID, Name: "milk"},
+		}, nil)
+		stMock.EXPECT().GetCategoryKeywords(chatID).Return(nil, nil).Times(2)
+
+		var added []models.ShoppingItem
+		stMock.EXPECT().AddShoppingItemIntoShoppingList(gomock.Any()).Do(
+			func(item models.ShoppingItem) {
+				added = append(added, item)
+			}).Return(nil).Times(2)
+		expectText(t, "Done! I've added 2 items into your shopping list. 1 item was already in the list.")
+
+		err := handleImportCallbackQuery(clientMock, stMock, callbackQueryMock, importCallbackDataConfirm)
+		if err != nil {
+			t.Fatalf("Unexpected err: got %#v", err)
+		}
+
+		expectedAdded := []models.ShoppingItem{
+			{Name: "eggs", ChatID: chatID, Quantity: 2, Category: "dairy", CreatedBy: userID},
+			{Name: "bread", ChatID: chatID, Category: "bakery", CreatedBy: userID},
+		}
+		if !reflect.DeepEqual(added, expectedAdded) {
+			t.Errorf("Expected %#v, got %#v", expectedAdded, added)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		stMock.EXPECT().GetPendingImport(chatID, userID).Return(pendingImportMock, nil)
+		stMock.EXPECT().DeletePendingImport(chatID, userID).Return(nil)
+		expectText(t, "Canceling. I haven't added anything.")
+
+		err := handleImportCallbackQuery(clientMock, stMock, callbackQueryMock, importCallbackDataCancel)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		expiredAtMock := time.Now().Add(-importTTL - time.Minute)
+		expiredImportMock := *pendingImportMock
+		expiredImportMock.CreatedAt = &expiredAtMock
+
+		stMock.EXPECT().GetPendingImport(chatID, userID).Return(&expiredImportMock, nil)
+		stMock.EXPECT().DeletePendingImport(chatID, userID).Return(nil)
+		expectText(t, "Sorry, this preview has expired. Please, send the items again with /import.")
+
+		err := handleImportCallbackQuery(clientMock, stMock, callbackQueryMock, importCallbackDataConfirm)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("No pending import", func(t *testing.T) {
+		// Someone else taps the button: nothing happens
+		stMock.EXPECT().GetPendingImport(chatID, userID).Return(nil, nil)
+
+		err := handleImportCallbackQuery(clientMock, stMock, callbackQueryMock, importCallbackDataConfirm)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		err := handleImportCallbackQuery(clientMock, stMock, callbackQueryMock, "invalid")
+		if err == nil || !strings.Contains(err.Error(), "Unable to parse confirmation") {
+			t.Errorf("Expected a parsing error, got %#v", err)
+		}
+	})
+}
+
+func TestDownloadFile(t *testing.T) {
+	token := "123456:secret-token"
+
+	t.Run("Connection error", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		_, err := downloadFile(server.URL+"/file/bot"+token+"/list.txt", importMaxFileSize)
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		if strings.Contains(err.Error(), token) {
+			t.Errorf("Expected the error not to contain the token, got %#v", err.Error())
+		}
+	})
+
+	t.Run("Unexpected status", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		_, err := downloadFile(server.URL+"/file/bot"+token+"/list.txt", importMaxFileSize)
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		if strings.Contains(err.Error(), token) {
+			t.Errorf("Expected the error not to contain the token, got %#v", err.Error())
+		}
+	})
+
+	t.Run("Too large file", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("milk\neggs\n"))
+		}))
+		defer server.Close()
+
+		_, err := downloadFile(server.URL+"/file/bot"+token+"/list.txt", 5)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
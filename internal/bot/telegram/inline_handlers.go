@@ -0,0 +1,135 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/bot/telegram/markup"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// inlineQueryCacheTime is number of seconds for which Telegram caches
+// our answers to inline queries. Answers contain shopping lists
+// which change often, so we can't cache them for long
+const inlineQueryCacheTime = 10
+
+// inlineResultShare is an ID of the inline query result that shares
+// the shopping list. Other results have IDs of commands which handle them
+const inlineResultShare = "share"
+
+// handleInlineQuery offers to add an item into the shopping list
+// and to share the shopping list.
+//
+// Inline queries can be sent from any chat, so we use the shopping list
+// of the private chat with the user: its ID is the ID of the user
+func handleInlineQuery(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	inlineQuery *tgbotapi.InlineQuery,
+) error {
+	chatID := int64(inlineQuery.From.ID)
+
+	tr, err := userTranslator(st, chatID, inlineQuery.From)
+	if err != nil {
+		return err
+	}
+
+	var results []interface{}
+
+	itemName := strings.TrimSpace(inlineQuery.Query)
+	if itemName != "" {
+		add := tgbotapi.NewInlineQueryResultArticle(
+			commandAdd,
+			tr.T("inline.add.title", itemName),
+			tr.T("inline.add.message", itemName))
+		add.Description = tr.T("inline.add.description")
+		results = append(results, add)
+	}
+
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	text := markup.NewBuilder(markup.ModeHTML)
+	var description string
+	if len(chatItems) == 0 {
+		description = tr.T("inline.share.empty")
+		text.Text(description)
+	} else {
+		description = tr.N("inline.share.header", len(chatItems), len(chatItems))
+		text.Text(description + "\n\n")
+		text.Pre(formatShoppingItems(chatItems))
+	}
+
+	share := tgbotapi.NewInlineQueryResultArticle(
+		inlineResultShare, tr.T("inline.share.title"), "")
+	share.Description = description
+	share.InputMessageContent = tgbotapi.InputTextMessageContent{
+		Text:      text.String(),
+		ParseMode: text.ParseMode(),
+	}
+	results = append(results, share)
+
+	_, err = client.AnswerInlineQuery(tgbotapi.InlineConfig{
+		InlineQueryID: inlineQuery.ID,
+		Results:       results,
+		CacheTime:     inlineQueryCacheTime,
+		// Every user has own shopping list
+		IsPersonal: true,
+	})
+	return err
+}
+
+// handleAddChosenInlineResult adds an item into the shopping list
+// of the private chat with the user who chose the "add" result
+func handleAddChosenInlineResult(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	chosenInlineResult *tgbotapi.ChosenInlineResult,
+) error {
+	itemName := strings.TrimSpace(chosenInlineResult.Query)
+	if itemName == "" {
+		// We don't offer this result for empty queries
+		return errors.New("Unable to add an item without a name")
+	}
+
+	chatID := int64(chosenInlineResult.From.ID)
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	// We can't ask the user about a duplicate here:
+	// there is no chat with the user, so we just buy more
+	if duplicate := findDuplicateItem(chatItems, itemName); duplicate != nil {
+		err := st.IncreaseShoppingItemQuantity(duplicate.ID, 1)
+		if err != nil {
+			return fmt.Errorf(
+				"Unable to increase quantity of a shopping item (ItemID=%d): %v",
+				duplicate.ID, err)
+		}
+		return nil
+	}
+
+	item, err := newShoppingItem(st, chatID, itemName, chosenInlineResult.From.ID)
+	if err != nil {
+		return err
+	}
+
+	err = st.AddShoppingItemIntoShoppingList(item)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to add a new shopping item (ChatID=%d, UserId=%d): %v",
+			chatID, chosenInlineResult.From.ID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package telegram
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/category"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+func TestHandleInlineQuery(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
+
+	// Common data mocks
+	errMock := errors.New("fake error")
+	userID := 321
+	newInlineQueryMock := func(query string) *tgbotapi.InlineQuery {
+		return &tgbotapi.InlineQuery{
+			ID:    "some-inline-query-id",
+			From:  &tgbotapi.User{ID: userID},
+			Query: query,
+		}
+	}
+
+	// expectResults checks IDs of results and caching of the answer
+	expectResults := func(t *testing.T, check func(tgbotapi.InlineConfig), expectedIDs ...string) {
+		clientMock.EXPECT().AnswerInlineQuery(gomock.Any()).Do(func(config tgbotapi.InlineConfig) {
+			if config.InlineQueryID != "some-inline-query-id" {
+				t.Errorf("Expected to answer the inline query %#v, got %#v",
+					"some-inline-query-id", config.InlineQueryID)
+			}
+
+			if !config.IsPersonal || config.CacheTime != inlineQueryCacheTime {
+				t.Errorf("Expected personal answer cached for %d seconds, got %#v",
+					inlineQueryCacheTime, config)
+			}
+
+			var ids []string
+			for _, result := range config.Results {
+				ids = append(ids, result.(tgbotapi.InlineQueryResultArticle).ID)
+			}
+			if strings.Join(ids, " ") != strings.Join(expectedIDs, " ") {
+				t.Fatalf("Expected results %v, got %v", expectedIDs, ids)
+			}
+
+			if check != nil {
+				check(config)
+			}
+		})
+	}
+
+	t.Run("Query with an item", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(int64(userID)).Return(
+			[]*models.ShoppingItem{{ID: 1, Name: "Bread"}}, nil)
+		expectResults(t, func(config tgbotapi.InlineConfig) {
+			add := config.Results[0].(tgbotapi.InlineQueryResultArticle)
+			if !strings.Contains(add.Title, "milk") {
+				t.Errorf("Expected the title to contain the item name, got %#v", add.Title)
+			}
+
+			share := config.Results[1].(tgbotapi.InlineQueryResultArticle)
+			content := share.InputMessageContent.(tgbotapi.InputTextMessageContent)
+			if !strings.Contains(content.Text, "Bread") || content.ParseMode != tgbotapi.ModeHTML {
+				t.Errorf("Expected the shopping list in HTML, got %#v", content)
+			}
+		}, commandAdd, inlineResultShare)
+
+		err := handleInlineQuery(clientMock, stMock, newInlineQueryMock(" milk "))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Empty query", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(int64(userID)).Return(
+			[]*models.ShoppingItem{}, nil)
+		expectResults(t, nil, inlineResultShare)
+
+		err := handleInlineQuery(clientMock, stMock, newInlineQueryMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(int64(userID)).Return(nil, errMock)
+
+		err := handleInlineQuery(clientMock, stMock, newInlineQueryMock("milk"))
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+}
+
+func TestHandleAddChosenInlineResult(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+	// Common data mocks
+	errMock := errors.New("fake error")
+	newChosenInlineResultMock := func(query string) *tgbotapi.ChosenInlineResult {
+		return &tgbotapi.ChosenInlineResult{
+			ResultID: commandAdd,
+			From:     &tgbotapi.User{ID: 321},
+			Query:    query,
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(int64(321)).Return(nil, nil)
+		stMock.EXPECT().GetCategoryKeywords(int64(321)).Return(nil, nil)
+		stMock.EXPECT().AddShoppingItemIntoShoppingList(models.ShoppingItem{
+			Name:      "milk",
+			ChatID:    321,
+			Category:  category.Dairy,
+			CreatedBy: 321,
+		}).Return(nil)
+
+		err := handleAddChosenInlineResult(
+			clientMock, stMock, newChosenInlineResultMock("milk "))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(int64(321)).Return(
+			[]*models.ShoppingItem{{ID: 1, Name: "Milk", ChatID: 321}}, nil)
+		stMock.EXPECT().IncreaseShoppingItemQuantity(int64(1), 1).Return(nil)
+
+		err := handleAddChosenInlineResult(
+			clientMock, stMock, newChosenInlineResultMock("milk"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Empty query", func(t *testing.T) {
+		err := handleAddChosenInlineResult(
+			clientMock, stMock, newChosenInlineResultMock(" "))
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(int64(321)).Return(nil, nil)
+		stMock.EXPECT().GetCategoryKeywords(int64(321)).Return(nil, nil)
+		stMock.EXPECT().AddShoppingItemIntoShoppingList(gomock.Any()).Return(errMock)
+
+		err := handleAddChosenInlineResult(
+			clientMock, stMock, newChosenInlineResultMock("milk"))
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+}
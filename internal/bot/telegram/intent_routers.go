@@ -0,0 +1,105 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/intent"
+	"github.com/m1kola/shipsterbot/internal/pkg/logging"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// routeMessageIntent routes plain messages like "add milk and eggs"
+// to command handlers, so users can talk to the bot without commands.
+//
+// It only makes sense in private chats: in groups people talk to each other
+var routeMessageIntent = func(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	logger *logging.Logger,
+	message *tgbotapi.Message,
+) error {
+	if message.Text == "" {
+		return updateRoutingError{
+			errors.New("Message doesn't have text to recognise an intent")}
+	}
+
+	chatID := message.Chat.ID
+	settings, err := st.GetChatSettings(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get settings of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	if !settings.NaturalLanguage {
+		return updateRoutingError{
+			errors.New("Natural language is disabled in the chat")}
+	}
+
+	in := intent.Parse(message.Text)
+	commandMessages := intentCommandMessages(message, in)
+	if len(commandMessages) == 0 {
+		return updateRoutingError{
+			errors.New("Unable to recognise an intent of the message")}
+	}
+
+	logger.Info("Intent recognised", "intent", in.Kind)
+	for _, commandMessage := range commandMessages {
+		// Commands go through the usual routing, so intents
+		// respect permissions and middlewares of commands
+		if err := routeMessageEntities(client, st, commandMessage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// intentCommandMessages returns command messages which
+// have the same effect as an intent
+func intentCommandMessages(message *tgbotapi.Message, in intent.Intent) []*tgbotapi.Message {
+	var command string
+	switch in.Kind {
+	case intent.Add:
+		command = commandAdd
+	case intent.Remove:
+		command = commandDel
+	case intent.List:
+		return []*tgbotapi.Message{newCommandMessage(message, commandList, "")}
+	case intent.Clear:
+		return []*tgbotapi.Message{newCommandMessage(message, commandClear, "")}
+	default:
+		return nil
+	}
+
+	// Commands take one item at a time
+	var commandMessages []*tgbotapi.Message
+	for _, item := range in.Items {
+		commandMessages = append(commandMessages,
+			newCommandMessage(message, command, item))
+	}
+	return commandMessages
+}
+
+// newCommandMessage returns a copy of a message, which looks
+// like the user sent a command with arguments
+func newCommandMessage(message *tgbotapi.Message, command, args string) *tgbotapi.Message {
+	commandMessage := *message
+
+	commandWithSlash := "/" + command
+	commandMessage.Text = commandWithSlash
+	if args != "" {
+		commandMessage.Text += " " + args
+	}
+	commandMessage.Entities = &[]tgbotapi.MessageEntity{
+		{
+			Type:   "bot_command",
+			Offset: 0,
+			Length: len(commandWithSlash),
+		},
+	}
+
+	return &commandMessage
+}
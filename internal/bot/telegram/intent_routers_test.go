@@ -0,0 +1,166 @@
+package telegram
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+func TestRouteMessageIntent(t *testing.T) {
+	// Common data mocks
+	errMock := errors.New("Fake error")
+	chatID := int64(123)
+	newMessageMock := func(text string) *tgbotapi.Message {
+		return &tgbotapi.Message{
+			Text: text,
+			Chat: &tgbotapi.Chat{ID: chatID, Type: "private"},
+			From: &tgbotapi.User{ID: 321},
+		}
+	}
+
+	// Common function mocks
+	var routedCommands []string
+	routeMessageEntitiesOld := routeMessageEntities
+	defer func() { routeMessageEntities = routeMessageEntitiesOld }()
+	routeMessageEntities = func(
+		_ botClientInterface,
+		_ storage.DataStorageInterface,
+		message *tgbotapi.Message,
+	) error {
+		routedCommands = append(routedCommands, message.Text)
+
+		if message.Command() == commandClear {
+			return errMock
+		}
+		return nil
+	}
+
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+	t.Run("Recognised intents", func(t *testing.T) {
+		testCases := []struct {
+			text     string
+			expected []string
+		}{
+			{"add milk and eggs", []string{"/add milk", "/add eggs"}},
+			{"remove bread", []string{"/del bread"}},
+			{"what's on my list?", []string{"/list"}},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.text, func(t *testing.T) {
+				routedCommands = nil
+				stMock.EXPECT().GetChatSettings(chatID).Return(
+					models.NewChatSettings(chatID), nil)
+
+				err := routeMessageIntent(
+					clientMock, stMock, discardLogger, newMessageMock(testCase.text))
+				if err != nil {
+					t.Errorf("Unexpected err: got %#v", err)
+				}
+
+				if strings.Join(routedCommands, "|") != strings.Join(testCase.expected, "|") {
+					t.Errorf("Expected commands %#v, got %#v",
+						testCase.expected, routedCommands)
+				}
+			})
+		}
+	})
+
+	t.Run("Errors from handlers", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+
+		err := routeMessageIntent(
+			clientMock, stMock, discardLogger, newMessageMock("clear everything"))
+		if err != errMock {
+			t.Errorf("Expected %#v, got %#v", errMock, err)
+		}
+	})
+
+	t.Run("Unknown intent", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+
+		err := routeMessageIntent(
+			clientMock, stMock, discardLogger, newMessageMock("hello"))
+		if _, ok := err.(updateRoutingError); !ok {
+			t.Errorf("Expected error of type %T, got %#v", updateRoutingError{}, err)
+		}
+	})
+
+	t.Run("Disabled natural language", func(t *testing.T) {
+		routedCommands = nil
+		settingsMock := models.NewChatSettings(chatID)
+		settingsMock.NaturalLanguage = false
+		stMock.EXPECT().GetChatSettings(chatID).Return(settingsMock, nil)
+
+		err := routeMessageIntent(
+			clientMock, stMock, discardLogger, newMessageMock("add milk"))
+		if _, ok := err.(updateRoutingError); !ok {
+			t.Errorf("Expected error of type %T, got %#v", updateRoutingError{}, err)
+		}
+
+		if len(routedCommands) != 0 {
+			t.Errorf("Unexpected commands %#v", routedCommands)
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(nil, errMock)
+
+		err := routeMessageIntent(
+			clientMock, stMock, discardLogger, newMessageMock("add milk"))
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+
+	t.Run("Message without text", func(t *testing.T) {
+		err := routeMessageIntent(
+			clientMock, stMock, discardLogger, newMessageMock(""))
+		if _, ok := err.(updateRoutingError); !ok {
+			t.Errorf("Expected error of type %T, got %#v", updateRoutingError{}, err)
+		}
+	})
+}
+
+func TestNewCommandMessage(t *testing.T) {
+	messageMock := &tgbotapi.Message{
+		MessageID: 456,
+		Text:      "add milk",
+		Chat:      &tgbotapi.Chat{ID: 123},
+	}
+
+	commandMessage := newCommandMessage(messageMock, commandAdd, "milk")
+
+	if commandMessage.Command() != commandAdd {
+		t.Errorf("Expected the %#v command, got %#v",
+			commandAdd, commandMessage.Command())
+	}
+
+	if commandMessage.CommandArguments() != "milk" {
+		t.Errorf("Expected the %#v arguments, got %#v",
+			"milk", commandMessage.CommandArguments())
+	}
+
+	if commandMessage.MessageID != messageMock.MessageID || commandMessage.Chat != messageMock.Chat {
+		t.Error("Expected the command message to be a copy of the original message")
+	}
+
+	if messageMock.Text != "add milk" {
+		t.Error("The original message must not be changed")
+	}
+}
@@ -0,0 +1,116 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// logLimit is how many recent changes the `/log` command shows
+const logLimit = 20
+
+func init() {
+	registerCommands(botCommand{
+		name:              commandLog,
+		description:       "command.log.description",
+		helpSection:       helpSectionShoppingList,
+		showInHelpMessage: true,
+		commandHandler:    handleLog,
+	})
+}
+
+// handleLog shows recent changes of the shopping list:
+// who changed the list, when and how
+func handleLog(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	chatID := message.Chat.ID
+
+	tr, err := userTranslator(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	entries, err := st.GetAuditLog(chatID, logLimit)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get the audit log of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	if len(entries) == 0 {
+		msg := tgbotapi.NewMessage(chatID, tr.T("log.empty"))
+		_, err = client.Send(msg)
+		return err
+	}
+
+	settings, err := st.GetChatSettings(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get chat settings (ChatID=%d): %v",
+			chatID, err)
+	}
+	loc, err := reminderLocation(settings.Timezone)
+	if err != nil {
+		return err
+	}
+
+	lines := []string{tr.T("log.header")}
+	for _, entry := range entries {
+		createdAt := ""
+		if entry.CreatedAt != nil {
+			createdAt = entry.CreatedAt.In(loc).Format(reminderTimeLayout)
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", createdAt, formatAuditLogEntry(tr, entry)))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, strings.Join(lines, "\n"))
+	_, err = client.Send(msg)
+	return err
+}
+
+// formatAuditLogEntry returns a human readable change of a shopping list
+func formatAuditLogEntry(tr *i18n.Translator, entry *models.AuditLogEntry) string {
+	actor := entry.Actor.Name
+	if entry.Actor.ID == 0 {
+		actor = tr.T("log.bot")
+	}
+
+	before := formatItemStates(entry.Before)
+	after := formatItemStates(entry.After)
+
+	switch entry.Action {
+	case models.AuditActionAdd:
+		return tr.T("log.add", actor, after)
+	case models.AuditActionDelete:
+		return tr.T("log.delete", actor, before)
+	case models.AuditActionClear:
+		return tr.T("log.clear", actor, before)
+	case models.AuditActionEdit:
+		return tr.T("log.edit", actor, before, after)
+	case models.AuditActionReorder:
+		return tr.T("log.reorder", actor)
+	}
+	return fmt.Sprintf("%s: %s", actor, entry.Action)
+}
+
+// formatItemStates returns a comma separated list of items.
+// Quantity is shown only for multiple items
+func formatItemStates(states []models.ItemState) string {
+	names := make([]string, 0, len(states))
+	for _, state := range states {
+		if state.Quantity > 1 {
+			names = append(names, fmt.Sprintf("%d × %s", state.Quantity, state.Name))
+		} else {
+			names = append(names, state.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,101 @@
+package telegram
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+func TestHandleLog(t *testing.T) {
+	if _, err := time.LoadLocation("Europe/Kiev"); err != nil {
+		t.Skipf("Timezone database is not available: %v", err)
+	}
+
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Common data mocks
+	chatID := int64(123)
+	settingsMock := models.NewChatSettings(chatID)
+	settingsMock.Timezone = "Europe/Kiev"
+	stMock.EXPECT().GetChatSettings(chatID).Return(settingsMock, nil).AnyTimes()
+
+	messageMock := mock_telegram.MessageCommandMockSetup(commandLog, "")
+	messageMock.Chat = &tgbotapi.Chat{ID: chatID}
+	messageMock.From = &tgbotapi.User{ID: 321}
+
+	t.Run("Empty log", func(t *testing.T) {
+		stMock.EXPECT().GetAuditLog(chatID, logLimit).Return(nil, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			expectedText := "Nobody has changed your shopping list recently."
+			if msgCfg.Text != expectedText {
+				t.Errorf("Expected %#v, got %#v", expectedText, msgCfg.Text)
+			}
+		})
+
+		err := handleLog(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Recent changes", func(t *testing.T) {
+		createdAt := time.Date(2018, 10, 22, 7, 0, 0, 0, time.UTC)
+		alice := models.Actor{ID: 1, Name: "Alice"}
+		stMock.EXPECT().GetAuditLog(chatID, logLimit).Return([]*models.AuditLogEntry{
+			{
+				Actor:     alice,
+				Action:    models.AuditActionClear,
+				Before:    []models.ItemState{{Name: "milk", Quantity: 2}, {Name: "bread"}},
+				CreatedAt: &createdAt,
+			},
+			{
+				Actor:     alice,
+				Action:    models.AuditActionEdit,
+				Before:    []models.ItemState{{Name: "milk"}},
+				After:     []models.ItemState{{Name: "milk", Quantity: 2}},
+				CreatedAt: &createdAt,
+			},
+			{
+				Action:    models.AuditActionAdd,
+				After:     []models.ItemState{{Name: "bread"}},
+				CreatedAt: &createdAt,
+			},
+		}, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			expectedText := "Recent changes of your shopping list:\n" +
+				"2018-10-22 10:00 Alice cleared the list: 2 × milk, bread\n" +
+				"2018-10-22 10:00 Alice changed milk → 2 × milk\n" +
+				"2018-10-22 10:00 I added bread"
+			if msgCfg.Text != expectedText {
+				t.Errorf("Expected %#v, got %#v", expectedText, msgCfg.Text)
+			}
+		})
+
+		err := handleLog(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		errMock := errors.New("fake error")
+		stMock.EXPECT().GetAuditLog(chatID, logLimit).Return(nil, errMock)
+
+		err := handleLog(clientMock, stMock, messageMock)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
@@ -0,0 +1,244 @@
+// Package markup helps to build formatted Telegram messages
+// that contain user-controlled content: item names, user names, etc.
+//
+// Every piece of text added to a message is escaped according to
+// the parse mode of the message, so users can't break message rendering
+// or inject their own formatting and links.
+//
+// See: https://core.telegram.org/bots/api#formatting-options
+package markup
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Mode represents a parse mode supported by the Telegram Bot API
+type Mode string
+
+// All supported parse modes
+const (
+	// ModePlain is plain text without any formatting
+	ModePlain      Mode = ""
+	ModeMarkdown   Mode = "Markdown"
+	ModeMarkdownV2 Mode = "MarkdownV2"
+	ModeHTML       Mode = "HTML"
+)
+
+// Characters which have to be escaped in the legacy Markdown mode
+var markdownReplacer = strings.NewReplacer(
+	`_`, `\_`, `*`, `\*`, "`", "\\`", `[`, `\[`,
+)
+
+// Characters which have to be escaped in the MarkdownV2 mode
+var markdownV2Replacer = strings.NewReplacer(
+	`\`, `\\`, `_`, `\_`, `*`, `\*`, `[`, `\[`, `]`, `\]`, `(`, `\(`,
+	`)`, `\)`, `~`, `\~`, "`", "\\`", `>`, `\>`, `#`, `\#`, `+`, `\+`,
+	`-`, `\-`, `=`, `\=`, `|`, `\|`, `{`, `\{`, `}`, `\}`, `.`, `\.`,
+	`!`, `\!`,
+)
+
+// Characters which have to be escaped in the HTML mode.
+// Note that Telegram supports only a few named HTML entities
+var htmlReplacer = strings.NewReplacer(
+	`&`, `&amp;`, `<`, `&lt;`, `>`, `&gt;`, `"`, `&quot;`,
+)
+
+// Characters which have to be escaped inside code entities
+// and link URLs in the MarkdownV2 mode
+var markdownV2CodeReplacer = strings.NewReplacer("\\", "\\\\", "`", "\\`")
+var markdownV2URLReplacer = strings.NewReplacer(`\`, `\\`, `)`, `\)`)
+
+// Escape escapes text, so it can be safely used
+// in a message with the `mode` parse mode
+func Escape(mode Mode, text string) string {
+	switch mode {
+	case ModeMarkdown:
+		return markdownReplacer.Replace(text)
+	case ModeMarkdownV2:
+		return markdownV2Replacer.Replace(text)
+	case ModeHTML:
+		return htmlReplacer.Replace(text)
+	}
+	return text
+}
+
+// Builder builds a message text in a specific parse mode.
+// The zero value is not usable: use NewBuilder to create a builder.
+type Builder struct {
+	mode Mode
+	buf  strings.Builder
+}
+
+// NewBuilder creates a message text builder for the `mode` parse mode
+func NewBuilder(mode Mode) *Builder {
+	return &Builder{mode: mode}
+}
+
+// ParseMode returns a parse mode of the message
+func (b *Builder) ParseMode() string {
+	return string(b.mode)
+}
+
+// String returns the message text
+func (b *Builder) String() string {
+	return b.buf.String()
+}
+
+// Text adds plain text to the message
+func (b *Builder) Text(text string) *Builder {
+	b.buf.WriteString(Escape(b.mode, text))
+	return b
+}
+
+// Textf formats according to a format specifier and adds
+// the result as plain text to the message
+func (b *Builder) Textf(format string, args ...interface{}) *Builder {
+	return b.Text(fmt.Sprintf(format, args...))
+}
+
+// Fragment is a formatted piece of a message
+// which can be used as an argument of Builder.Format
+type Fragment func(b *Builder)
+
+// Bold returns a fragment with bold text
+func Bold(text string) Fragment {
+	return func(b *Builder) { b.Bold(text) }
+}
+
+// Mention returns a fragment with a mention of an user by their ID
+func Mention(name string, userID int) Fragment {
+	return func(b *Builder) { b.Mention(name, userID) }
+}
+
+// formatVerbRegexp matches verbs supported by Builder.Format
+var formatVerbRegexp = regexp.MustCompile(`%%|%(?:\[(\d+)\])?s`)
+
+// Format adds text from a template to the message. It is useful
+// for translated messages where formatting is a part of a sentence.
+//
+// The template supports only the %s verb, including explicit
+// argument indexes like %[2]s, and %% for a percent sign.
+// Fragment arguments are added as they are,
+// the template and other arguments are added as plain text.
+func (b *Builder) Format(template string, args ...interface{}) *Builder {
+	argIndex := 0
+	lastEnd := 0
+	for _, match := range formatVerbRegexp.FindAllStringSubmatchIndex(template, -1) {
+		b.Text(template[lastEnd:match[0]])
+		lastEnd = match[1]
+
+		if template[match[0]:match[1]] == "%%" {
+			b.Text("%")
+			continue
+		}
+
+		if match[2] >= 0 {
+			// Explicit argument indexes are 1-based
+			n, _ := strconv.Atoi(template[match[2]:match[3]])
+			argIndex = n - 1
+		}
+
+		if argIndex < 0 || argIndex >= len(args) {
+			b.Text("%!s(MISSING)")
+		} else if fragment, ok := args[argIndex].(Fragment); ok {
+			fragment(b)
+		} else {
+			b.Text(fmt.Sprint(args[argIndex]))
+		}
+		argIndex++
+	}
+	b.Text(template[lastEnd:])
+
+	return b
+}
+
+// Bold adds bold text to the message
+func (b *Builder) Bold(text string) *Builder {
+	return b.entity("*", "*", "<b>", "</b>", text)
+}
+
+// Italic adds italic text to the message
+func (b *Builder) Italic(text string) *Builder {
+	return b.entity("_", "_", "<i>", "</i>", text)
+}
+
+// Code adds inline fixed-width text to the message
+func (b *Builder) Code(text string) *Builder {
+	return b.code("`", "`", "<code>", "</code>", text)
+}
+
+// Pre adds a pre-formatted fixed-width text block to the message
+func (b *Builder) Pre(text string) *Builder {
+	return b.code("```\n", "```", "<pre>", "</pre>", text)
+}
+
+// Link adds a link to the message
+func (b *Builder) Link(text, url string) *Builder {
+	switch b.mode {
+	case ModeMarkdown:
+		// Legacy Markdown doesn't support escaping inside entities
+		text = strings.NewReplacer("[", "", "]", "").Replace(text)
+		url = strings.Replace(url, ")", "%29", -1)
+		fmt.Fprintf(&b.buf, "[%s](%s)", text, url)
+	case ModeMarkdownV2:
+		fmt.Fprintf(&b.buf, "[%s](%s)",
+			Escape(b.mode, text), markdownV2URLReplacer.Replace(url))
+	case ModeHTML:
+		fmt.Fprintf(&b.buf, `<a href="%s">%s</a>`,
+			Escape(b.mode, url), Escape(b.mode, text))
+	default:
+		b.buf.WriteString(text)
+	}
+	return b
+}
+
+// Mention adds a mention of an user by their ID to the message.
+// It works even for users without usernames.
+func (b *Builder) Mention(name string, userID int) *Builder {
+	return b.Link(name, fmt.Sprintf("tg://user?id=%d", userID))
+}
+
+// entity adds a text entity that can contain
+// other entities in MarkdownV2 and HTML modes
+func (b *Builder) entity(
+	markdownStart, markdownEnd, HTMLStart, HTMLEnd, text string,
+) *Builder {
+	switch b.mode {
+	case ModeMarkdown:
+		// Legacy Markdown doesn't support escaping inside entities,
+		// so we have to remove entity delimiters from the text
+		text = strings.Replace(text, markdownEnd, "", -1)
+		b.buf.WriteString(markdownStart + text + markdownEnd)
+	case ModeMarkdownV2:
+		b.buf.WriteString(markdownStart + Escape(b.mode, text) + markdownEnd)
+	case ModeHTML:
+		b.buf.WriteString(HTMLStart + Escape(b.mode, text) + HTMLEnd)
+	default:
+		b.buf.WriteString(text)
+	}
+	return b
+}
+
+// code adds a code entity which content is not parsed
+func (b *Builder) code(
+	markdownStart, markdownEnd, HTMLStart, HTMLEnd, text string,
+) *Builder {
+	switch b.mode {
+	case ModeMarkdown:
+		// Legacy Markdown doesn't support escaping inside entities,
+		// so we replace backticks with similar looking quotes
+		text = strings.Replace(text, "`", "'", -1)
+		b.buf.WriteString(markdownStart + text + markdownEnd)
+	case ModeMarkdownV2:
+		b.buf.WriteString(
+			markdownStart + markdownV2CodeReplacer.Replace(text) + markdownEnd)
+	case ModeHTML:
+		b.buf.WriteString(HTMLStart + Escape(b.mode, text) + HTMLEnd)
+	default:
+		b.buf.WriteString(text)
+	}
+	return b
+}
@@ -0,0 +1,180 @@
+package markup
+
+import (
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	hostileText := "*bold* _italic_ `code` [link](http://example.com) <b>&</b>"
+
+	testCases := []struct {
+		mode     Mode
+		expected string
+	}{
+		{
+			mode:     ModePlain,
+			expected: hostileText,
+		},
+		{
+			mode:     ModeMarkdown,
+			expected: "\\*bold\\* \\_italic\\_ \\`code\\` \\[link](http://example.com) <b>&</b>",
+		},
+		{
+			mode:     ModeMarkdownV2,
+			expected: "\\*bold\\* \\_italic\\_ \\`code\\` \\[link\\]\\(http://example\\.com\\) <b\\>&</b\\>",
+		},
+		{
+			mode:     ModeHTML,
+			expected: "*bold* _italic_ `code` [link](http://example.com) &lt;b&gt;&amp;&lt;/b&gt;",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.mode), func(t *testing.T) {
+			actual := Escape(testCase.mode, hostileText)
+			if actual != testCase.expected {
+				t.Errorf("Expected %#v, got %#v", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	hostileName := "*Evil_`[Name]`*<i>"
+
+	testCases := []struct {
+		mode     Mode
+		expected string
+	}{
+		{
+			mode: ModePlain,
+			expected: "Hi *Evil_`[Name]`*<i>! *Evil_`[Name]`*<i>" +
+				" *Evil_`[Name]`*<i> *Evil_`[Name]`*<i>" +
+				" *Evil_`[Name]`*<i> *Evil_`[Name]`*<i>",
+		},
+		{
+			mode: ModeMarkdown,
+			expected: "Hi \\*Evil\\_\\`\\[Name]\\`\\*<i>! *Evil_`[Name]`<i>*" +
+				" _*Evil`[Name]`*<i>_ `*Evil_'[Name]'*<i>`" +
+				" ```\n*Evil_'[Name]'*<i>```" +
+				" [*Evil_`Name`*<i>](tg://user?id=123)",
+		},
+		{
+			mode: ModeMarkdownV2,
+			expected: "Hi \\*Evil\\_\\`\\[Name\\]\\`\\*<i\\>\\! *\\*Evil\\_\\`\\[Name\\]\\`\\*<i\\>*" +
+				" _\\*Evil\\_\\`\\[Name\\]\\`\\*<i\\>_ `*Evil_\\`[Name]\\`*<i>`" +
+				" ```\n*Evil_\\`[Name]\\`*<i>```" +
+				" [\\*Evil\\_\\`\\[Name\\]\\`\\*<i\\>](tg://user?id=123)",
+		},
+		{
+			mode: ModeHTML,
+			expected: "Hi *Evil_`[Name]`*&lt;i&gt;! <b>*Evil_`[Name]`*&lt;i&gt;</b>" +
+				" <i>*Evil_`[Name]`*&lt;i&gt;</i> <code>*Evil_`[Name]`*&lt;i&gt;</code>" +
+				" <pre>*Evil_`[Name]`*&lt;i&gt;</pre>" +
+				` <a href="tg://user?id=123">*Evil_` + "`[Name]`" + `*&lt;i&gt;</a>`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.mode), func(t *testing.T) {
+			b := NewBuilder(testCase.mode)
+			b.Textf("Hi %s!", hostileName).Text(" ").
+				Bold(hostileName).Text(" ").
+				Italic(hostileName).Text(" ").
+				Code(hostileName).Text(" ").
+				Pre(hostileName).Text(" ").
+				Mention(hostileName, 123)
+
+			if b.String() != testCase.expected {
+				t.Errorf("Expected %#v, got %#v", testCase.expected, b.String())
+			}
+
+			if b.ParseMode() != string(testCase.mode) {
+				t.Errorf("Expected parse mode %#v, got %#v",
+					string(testCase.mode), b.ParseMode())
+			}
+		})
+	}
+}
+
+func TestBuilderLinkURL(t *testing.T) {
+	hostileURL := `http://example.com/a_(b)"<>`
+
+	testCases := []struct {
+		mode     Mode
+		expected string
+	}{
+		{
+			mode:     ModeMarkdown,
+			expected: `[link](http://example.com/a_(b%29"<>)`,
+		},
+		{
+			mode:     ModeMarkdownV2,
+			expected: `[link](http://example.com/a_(b\)"<>)`,
+		},
+		{
+			mode:     ModeHTML,
+			expected: `<a href="http://example.com/a_(b)&quot;&lt;&gt;">link</a>`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.mode), func(t *testing.T) {
+			b := NewBuilder(testCase.mode).Link("link", hostileURL)
+
+			if b.String() != testCase.expected {
+				t.Errorf("Expected %#v, got %#v", testCase.expected, b.String())
+			}
+		})
+	}
+}
+
+func TestBuilderFormat(t *testing.T) {
+	testCases := []struct {
+		testName string
+		template string
+		args     []interface{}
+		expected string
+	}{
+		{
+			testName: "Plain arguments are escaped",
+			template: "Hi %s <3",
+			args:     []interface{}{"<b>m1kola</b>"},
+			expected: "Hi &lt;b&gt;m1kola&lt;/b&gt; &lt;3",
+		},
+		{
+			testName: "Fragments",
+			template: "Ok %s, %s?",
+			args:     []interface{}{Mention("m1kola", 123), Bold("<really>")},
+			expected: `Ok <a href="tg://user?id=123">m1kola</a>, <b>&lt;really&gt;</b>?`,
+		},
+		{
+			testName: "Explicit argument indexes",
+			template: "%[2]s %[1]s %s",
+			args:     []interface{}{"a", "b"},
+			expected: "b a b",
+		},
+		{
+			testName: "Percent sign",
+			template: "100%% %s",
+			args:     []interface{}{1},
+			expected: "100% 1",
+		},
+		{
+			testName: "Missing argument",
+			template: "%s %s",
+			args:     []interface{}{"a"},
+			expected: "a %!s(MISSING)",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			b := NewBuilder(ModeHTML).Format(testCase.template, testCase.args...)
+
+			if b.String() != testCase.expected {
+				t.Errorf("Expected %#v, got %#v", testCase.expected, b.String())
+			}
+		})
+	}
+}
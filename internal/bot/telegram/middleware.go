@@ -0,0 +1,148 @@
+package telegram
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// middleware wraps handlers of commands to add cross-cutting
+// behaviour: logging, metrics, panic recovery, etc.
+//
+// Any of the fields can be nil, if a middleware doesn't need
+// to wrap handlers of that kind
+type middleware struct {
+	command            func(next commandHandlerFunc) commandHandlerFunc
+	callbackQuery      func(next callbackQueryHandlerFunc) callbackQueryHandlerFunc
+	chosenInlineResult func(next chosenInlineResultHandlerFunc) chosenInlineResultHandlerFunc
+	// inlineQuery wraps the inline query handler. Inline queries
+	// don't belong to commands, so only global middlewares wrap it
+	inlineQuery func(next inlineQueryHandlerFunc) inlineQueryHandlerFunc
+}
+
+func (m middleware) wrapCommandHandler(next commandHandlerFunc) commandHandlerFunc {
+	if m.command == nil || next == nil {
+		return next
+	}
+	return m.command(next)
+}
+
+func (m middleware) wrapCallbackQueryHandler(next callbackQueryHandlerFunc) callbackQueryHandlerFunc {
+	if m.callbackQuery == nil || next == nil {
+		return next
+	}
+	return m.callbackQuery(next)
+}
+
+func (m middleware) wrapChosenInlineResultHandler(next chosenInlineResultHandlerFunc) chosenInlineResultHandlerFunc {
+	if m.chosenInlineResult == nil || next == nil {
+		return next
+	}
+	return m.chosenInlineResult(next)
+}
+
+func (m middleware) wrapInlineQueryHandler(next inlineQueryHandlerFunc) inlineQueryHandlerFunc {
+	if m.inlineQuery == nil || next == nil {
+		return next
+	}
+	return m.inlineQuery(next)
+}
+
+// applyMiddlewares wraps handlers of all commands with global
+// middlewares and then with middlewares of each command.
+//
+// The first middleware is the outermost one: it's called first
+// and it sees results of all other middlewares
+func applyMiddlewares(
+	commands map[string]botCommand,
+	global ...middleware,
+) map[string]botCommand {
+	for name, command := range commands {
+		middlewares := append(append([]middleware{}, global...), command.middlewares...)
+
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			m := middlewares[i]
+
+			command.commandHandler = m.wrapCommandHandler(command.commandHandler)
+			command.unfinishedCommandHandler = m.wrapCommandHandler(command.unfinishedCommandHandler)
+			command.callbackQueryHandler = m.wrapCallbackQueryHandler(command.callbackQueryHandler)
+			command.chosenInlineResultHandler = m.wrapChosenInlineResultHandler(command.chosenInlineResultHandler)
+		}
+
+		// Handlers are wrapped now, so we don't want
+		// to wrap them again by accident
+		command.middlewares = nil
+		commands[name] = command
+	}
+
+	return commands
+}
+
+// panicError is an error that we return, when a handler panics
+type panicError struct {
+	value interface{}
+	stack []byte
+}
+
+func (e panicError) Error() string {
+	return fmt.Sprintf("Handler panicked: %v", e.value)
+}
+
+// recoverMiddleware turns panics in handlers into errors,
+// so one broken handler doesn't crash the whole bot.
+// These errors are routed to handleUnrecoverableError
+// just like any other errors returned by handlers
+var recoverMiddleware = middleware{
+	command: func(next commandHandlerFunc) commandHandlerFunc {
+		return func(
+			client botClientInterface,
+			st storage.DataStorageInterface,
+			message *tgbotapi.Message,
+		) (err error) {
+			defer recoverPanic(&err)
+			return next(client, st, message)
+		}
+	},
+	callbackQuery: func(next callbackQueryHandlerFunc) callbackQueryHandlerFunc {
+		return func(
+			client botClientInterface,
+			st storage.DataStorageInterface,
+			callbackQuery *tgbotapi.CallbackQuery,
+			data string,
+		) (err error) {
+			defer recoverPanic(&err)
+			return next(client, st, callbackQuery, data)
+		}
+	},
+	chosenInlineResult: func(next chosenInlineResultHandlerFunc) chosenInlineResultHandlerFunc {
+		return func(
+			client botClientInterface,
+			st storage.DataStorageInterface,
+			chosenInlineResult *tgbotapi.ChosenInlineResult,
+		) (err error) {
+			defer recoverPanic(&err)
+			return next(client, st, chosenInlineResult)
+		}
+	},
+	inlineQuery: func(next inlineQueryHandlerFunc) inlineQueryHandlerFunc {
+		return func(
+			client botClientInterface,
+			st storage.DataStorageInterface,
+			inlineQuery *tgbotapi.InlineQuery,
+		) (err error) {
+			defer recoverPanic(&err)
+			return next(client, st, inlineQuery)
+		}
+	},
+}
+
+// recoverPanic must be deferred: it stops panicking
+// and replaces err with panicError
+func recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		*err = panicError{value: r, stack: debug.Stack()}
+	}
+}
@@ -0,0 +1,186 @@
+package telegram
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+func TestApplyMiddlewares(t *testing.T) {
+	var calls []string
+
+	// tracingMiddleware records calls of wrapped handlers
+	tracingMiddleware := func(name string) middleware {
+		return middleware{
+			command: func(next commandHandlerFunc) commandHandlerFunc {
+				return func(
+					client botClientInterface,
+					st storage.DataStorageInterface,
+					message *tgbotapi.Message,
+				) error {
+					calls = append(calls, name)
+					return next(client, st, message)
+				}
+			},
+			callbackQuery: func(next callbackQueryHandlerFunc) callbackQueryHandlerFunc {
+				return func(
+					client botClientInterface,
+					st storage.DataStorageInterface,
+					callbackQuery *tgbotapi.CallbackQuery,
+					data string,
+				) error {
+					calls = append(calls, name)
+					return next(client, st, callbackQuery, data)
+				}
+			},
+		}
+	}
+
+	commandHandlerMock := func(
+		_ botClientInterface, _ storage.DataStorageInterface, _ *tgbotapi.Message,
+	) error {
+		calls = append(calls, "handler")
+		return nil
+	}
+	callbackQueryHandlerMock := func(
+		_ botClientInterface,
+		_ storage.DataStorageInterface,
+		_ *tgbotapi.CallbackQuery,
+		_ string,
+	) error {
+		calls = append(calls, "handler")
+		return nil
+	}
+
+	commands := applyMiddlewares(map[string]botCommand{
+		commandAdd: {
+			middlewares: []middleware{
+				tracingMiddleware("command1"),
+				// Middlewares that don't wrap some kinds of handlers are skipped
+				{},
+				tracingMiddleware("command2"),
+			},
+			commandHandler:           commandHandlerMock,
+			unfinishedCommandHandler: commandHandlerMock,
+			callbackQueryHandler:     callbackQueryHandlerMock,
+		},
+		commandList: {
+			commandHandler: commandHandlerMock,
+		},
+	}, tracingMiddleware("global1"), tracingMiddleware("global2"))
+
+	t.Run("Order", func(t *testing.T) {
+		expected := "global1 global2 command1 command2 handler"
+		command := commands[commandAdd]
+
+		for name, call := range map[string]func(){
+			"commandHandler": func() {
+				command.commandHandler(nil, nil, nil)
+			},
+			"unfinishedCommandHandler": func() {
+				command.unfinishedCommandHandler(nil, nil, nil)
+			},
+			"callbackQueryHandler": func() {
+				command.callbackQueryHandler(nil, nil, nil, "")
+			},
+		} {
+			calls = nil
+			call()
+
+			if actual := strings.Join(calls, " "); actual != expected {
+				t.Errorf("%s: expected calls %#v, got %#v", name, expected, actual)
+			}
+		}
+
+		if command.middlewares != nil {
+			t.Error("Expected middlewares to be removed after wrapping")
+		}
+	})
+
+	t.Run("Only global middlewares", func(t *testing.T) {
+		calls = nil
+		commands[commandList].commandHandler(nil, nil, nil)
+
+		expected := "global1 global2 handler"
+		if actual := strings.Join(calls, " "); actual != expected {
+			t.Errorf("Expected calls %#v, got %#v", expected, actual)
+		}
+	})
+
+	t.Run("Missing handlers stay missing", func(t *testing.T) {
+		command := commands[commandList]
+
+		if command.unfinishedCommandHandler != nil {
+			t.Error("Unexpected unfinishedCommandHandler")
+		}
+		if command.callbackQueryHandler != nil {
+			t.Error("Unexpected callbackQueryHandler")
+		}
+	})
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	errMock := errors.New("fake error")
+
+	t.Run("Command handler", func(t *testing.T) {
+		handler := recoverMiddleware.wrapCommandHandler(func(
+			_ botClientInterface, _ storage.DataStorageInterface, _ *tgbotapi.Message,
+		) error {
+			panic("fake panic")
+		})
+
+		err := handler(nil, nil, nil)
+		pErr, ok := err.(panicError)
+		if !ok {
+			t.Fatalf("Expected error of type %T, got %#v", panicError{}, err)
+		}
+		if pErr.value != "fake panic" || len(pErr.stack) == 0 {
+			t.Errorf("Expected the panic value and a stack trace, got %#v", pErr)
+		}
+	})
+
+	t.Run("Callback query handler", func(t *testing.T) {
+		handler := recoverMiddleware.wrapCallbackQueryHandler(func(
+			_ botClientInterface,
+			_ storage.DataStorageInterface,
+			_ *tgbotapi.CallbackQuery,
+			_ string,
+		) error {
+			panic("fake panic")
+		})
+
+		err := handler(nil, nil, nil, "")
+		if _, ok := err.(panicError); !ok {
+			t.Fatalf("Expected error of type %T, got %#v", panicError{}, err)
+		}
+	})
+
+	t.Run("Inline query handler", func(t *testing.T) {
+		handler := recoverMiddleware.wrapInlineQueryHandler(func(
+			_ botClientInterface, _ storage.DataStorageInterface, _ *tgbotapi.InlineQuery,
+		) error {
+			panic("fake panic")
+		})
+
+		err := handler(nil, nil, nil)
+		if _, ok := err.(panicError); !ok {
+			t.Fatalf("Expected error of type %T, got %#v", panicError{}, err)
+		}
+	})
+
+	t.Run("Errors are passed through", func(t *testing.T) {
+		handler := recoverMiddleware.wrapCommandHandler(func(
+			_ botClientInterface, _ storage.DataStorageInterface, _ *tgbotapi.Message,
+		) error {
+			return errMock
+		})
+
+		if err := handler(nil, nil, nil); err != errMock {
+			t.Errorf("Expected %#v, got %#v", errMock, err)
+		}
+	})
+}
@@ -2,16 +2,21 @@ package telegram
 
 import (
 	"fmt"
-	"log"
 	"net/http"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/logging"
 )
 
 // newServerWithIncommingRequstLogger creates a new server struct
 // with an incomming request logger
-func newServerWithIncommingRequstLogger(port string, handler http.Handler) *http.Server {
-	newHandler := incommingRequstLogger(handler)
+func newServerWithIncommingRequstLogger(
+	port string,
+	handler http.Handler,
+	logger *logging.Logger,
+) *http.Server {
+	newHandler := incommingRequstLogger(handler, logger)
 	addr := fmt.Sprintf(":%s", port)
 
 	return &http.Server{Addr: addr, Handler: newHandler}
@@ -28,9 +33,18 @@ var listenAndServe = func(server listenerAndServer, TLSCertPath, TLSKeyPath stri
 	return server.ListenAndServe()
 }
 
-func incommingRequstLogger(handler http.Handler) http.Handler {
+// incommingRequstLogger logs incomming requests.
+//
+// Note that the webhook URL contains the bot token,
+// so we only log the URL in the debug mode
+func incommingRequstLogger(handler http.Handler, logger *logging.Logger) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("%s %s %s", r.RemoteAddr, r.Method, r.URL)
+		keyvals := []interface{}{"remote_addr", r.RemoteAddr, "method", r.Method}
+		if logger.Enabled(logging.LevelDebug) {
+			keyvals = append(keyvals, "url", r.URL)
+		}
+
+		logger.Info("Incomming request", keyvals...)
 		handler.ServeHTTP(w, r)
 	})
 }
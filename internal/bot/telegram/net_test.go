@@ -2,21 +2,23 @@ package telegram
 
 import (
 	"bytes"
-	"log"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/logging"
 	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
 )
 
 func TestNewServerWithIncommingRequstLogger(t *testing.T) {
 	expectedAddr := ":8443"
 
-	server := newServerWithIncommingRequstLogger("8443", http.DefaultServeMux)
+	server := newServerWithIncommingRequstLogger(
+		"8443", http.DefaultServeMux, logging.New(ioutil.Discard, logging.LevelInfo))
 	defer server.Close()
 
 	if server.Addr != expectedAddr {
@@ -65,29 +67,54 @@ func TestGetUpdatesChan(t *testing.T) {
 }
 
 func TestIncommingRequstLogger(t *testing.T) {
-	// Setup capturing buffer and restoer previous output
-	var buf bytes.Buffer
-	log.SetOutput(&buf)
-	defer func() { log.SetOutput(os.Stderr) }()
-
-	originalIsCalled := false
-	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		originalIsCalled = true
-	})
+	testCases := []struct {
+		testName       string
+		level          logging.Level
+		expectedLog    string
+		notExpectedLog string
+	}{
+		{
+			testName:    "Debug level",
+			level:       logging.LevelDebug,
+			expectedLog: "remote_addr=192.0.2.1:1234 method=GET url=/traget-url",
+		},
+		{
+			testName:       "Info level",
+			level:          logging.LevelInfo,
+			expectedLog:    "remote_addr=192.0.2.1:1234 method=GET",
+			notExpectedLog: "/traget-url",
+		},
+	}
 
-	newHandler := incommingRequstLogger(mockHandler)
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			// Setup capturing buffer
+			var buf bytes.Buffer
+			logger := logging.New(&buf, testCase.level)
 
-	mockRequest := httptest.NewRequest("GET", "/traget-url", nil)
-	w := httptest.NewRecorder()
-	newHandler.ServeHTTP(w, mockRequest)
+			originalIsCalled := false
+			mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				originalIsCalled = true
+			})
 
-	if !originalIsCalled {
-		t.Error("Original handler expected to be called")
-	}
+			newHandler := incommingRequstLogger(mockHandler, logger)
+
+			mockRequest := httptest.NewRequest("GET", "/traget-url", nil)
+			w := httptest.NewRecorder()
+			newHandler.ServeHTTP(w, mockRequest)
+
+			if !originalIsCalled {
+				t.Error("Original handler expected to be called")
+			}
+
+			bufString := buf.String()
+			if !strings.Contains(bufString, testCase.expectedLog) {
+				t.Errorf("%s expected to contain %s", bufString, testCase.expectedLog)
+			}
 
-	expectedLog := "192.0.2.1:1234 GET /traget-url"
-	bufString := buf.String()
-	if !strings.Contains(bufString, expectedLog) {
-		t.Errorf("%s expected to contain %s", bufString, expectedLog)
+			if testCase.notExpectedLog != "" && strings.Contains(bufString, testCase.notExpectedLog) {
+				t.Errorf("%s expected not to contain %s", bufString, testCase.notExpectedLog)
+			}
+		})
 	}
 }
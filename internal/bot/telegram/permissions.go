@@ -0,0 +1,105 @@
+package telegram
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// permission defines who can use a command
+type permission int
+
+const (
+	// permissionEveryone allows everyone to use a command
+	permissionEveryone permission = iota
+
+	// permissionAdmin restricts a command to chat administrators,
+	// if a group enabled the "admins only" policy in the chat settings
+	permissionAdmin
+)
+
+// hasPermission checks if an user has a permission in a chat
+var hasPermission = func(
+	client chatMemberGetter,
+	st storage.DataStorageInterface,
+	chat *tgbotapi.Chat,
+	user *tgbotapi.User,
+	p permission,
+) (bool, error) {
+	if p == permissionEveryone {
+		return true, nil
+	}
+
+	// Everyone is an administrator in their private chat with the bot
+	// and only administrators can post into channels
+	if chat.IsPrivate() || chat.IsChannel() || chat.AllMembersAreAdmins {
+		return true, nil
+	}
+
+	if user == nil {
+		return false, nil
+	}
+
+	settings, err := st.GetChatSettings(chat.ID)
+	if err != nil {
+		return false, fmt.Errorf(
+			"Unable to get settings of the chat (ChatID=%d): %v",
+			chat.ID, err)
+	}
+
+	if !settings.AdminsOnly {
+		return true, nil
+	}
+
+	member, err := client.GetChatMember(tgbotapi.ChatConfigWithUser{
+		ChatID: chat.ID,
+		UserID: user.ID,
+	})
+	if err != nil {
+		return false, fmt.Errorf(
+			"Unable to get a chat member (ChatID=%d, UserID=%d): %v",
+			chat.ID, user.ID, err)
+	}
+
+	return member.IsCreator() || member.IsAdministrator(), nil
+}
+
+// denyCommand tells an user that they are not allowed to use a command
+func denyCommand(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	tr, err := userTranslator(st, message.Chat.ID, message.From)
+	if err != nil {
+		return err
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, tr.T("permission.denied"))
+	msg.ReplyToMessageID = message.MessageID
+	_, err = client.Send(msg)
+	return err
+}
+
+// denyCallbackQuery tells an user that they are not allowed to use
+// an inline keyboard. The keyboard stays, so admins still can use it
+func denyCallbackQuery(
+	client callbackQueryAnswerer,
+	st storage.DataStorageInterface,
+	callbackQuery *tgbotapi.CallbackQuery,
+) error {
+	tr, err := userTranslator(
+		st, callbackQuery.Message.Chat.ID, callbackQuery.From)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.AnswerCallbackQuery(tgbotapi.CallbackConfig{
+		CallbackQueryID: callbackQuery.ID,
+		Text:            tr.T("permission.denied"),
+		ShowAlert:       true,
+	})
+	return err
+}
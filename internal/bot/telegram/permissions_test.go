@@ -0,0 +1,246 @@
+package telegram
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+func TestHasPermission(t *testing.T) {
+	// Common data mocks
+	errMock := errors.New("fake error")
+	groupMock := &tgbotapi.Chat{ID: -123, Type: "supergroup"}
+	userMock := &tgbotapi.User{ID: 321}
+	adminsOnlySettings := models.NewChatSettings(groupMock.ID)
+	adminsOnlySettings.AdminsOnly = true
+	memberConfig := tgbotapi.ChatConfigWithUser{
+		ChatID: groupMock.ID,
+		UserID: userMock.ID,
+	}
+
+	testCases := []struct {
+		testName   string
+		chat       *tgbotapi.Chat
+		user       *tgbotapi.User
+		permission permission
+		setupMocks func(
+			clientMock *mock_telegram.MockchatMemberGetter,
+			stMock *mock_storage.MockDataStorageInterface,
+		)
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			testName:   "Everyone",
+			chat:       groupMock,
+			user:       userMock,
+			permission: permissionEveryone,
+			expected:   true,
+		},
+		{
+			testName:   "Private chat",
+			chat:       &tgbotapi.Chat{ID: 321, Type: "private"},
+			user:       userMock,
+			permission: permissionAdmin,
+			expected:   true,
+		},
+		{
+			testName:   "All members are admins",
+			chat:       &tgbotapi.Chat{ID: -123, Type: "group", AllMembersAreAdmins: true},
+			user:       userMock,
+			permission: permissionAdmin,
+			expected:   true,
+		},
+		{
+			testName:   "Policy is disabled",
+			chat:       groupMock,
+			user:       userMock,
+			permission: permissionAdmin,
+			setupMocks: func(
+				clientMock *mock_telegram.MockchatMemberGetter,
+				stMock *mock_storage.MockDataStorageInterface,
+			) {
+				stMock.EXPECT().GetChatSettings(groupMock.ID).Return(
+					models.NewChatSettings(groupMock.ID), nil)
+			},
+			expected: true,
+		},
+		{
+			testName:   "Admin",
+			chat:       groupMock,
+			user:       userMock,
+			permission: permissionAdmin,
+			setupMocks: func(
+				clientMock *mock_telegram.MockchatMemberGetter,
+				stMock *mock_storage.MockDataStorageInterface,
+			) {
+				stMock.EXPECT().GetChatSettings(groupMock.ID).Return(adminsOnlySettings, nil)
+				clientMock.EXPECT().GetChatMember(memberConfig).Return(
+					tgbotapi.ChatMember{Status: "administrator"}, nil)
+			},
+			expected: true,
+		},
+		{
+			testName:   "Creator",
+			chat:       groupMock,
+			user:       userMock,
+			permission: permissionAdmin,
+			setupMocks: func(
+				clientMock *mock_telegram.MockchatMemberGetter,
+				stMock *mock_storage.MockDataStorageInterface,
+			) {
+				stMock.EXPECT().GetChatSettings(groupMock.ID).Return(adminsOnlySettings, nil)
+				clientMock.EXPECT().GetChatMember(memberConfig).Return(
+					tgbotapi.ChatMember{Status: "creator"}, nil)
+			},
+			expected: true,
+		},
+		{
+			testName:   "Member",
+			chat:       groupMock,
+			user:       userMock,
+			permission: permissionAdmin,
+			setupMocks: func(
+				clientMock *mock_telegram.MockchatMemberGetter,
+				stMock *mock_storage.MockDataStorageInterface,
+			) {
+				stMock.EXPECT().GetChatSettings(groupMock.ID).Return(adminsOnlySettings, nil)
+				clientMock.EXPECT().GetChatMember(memberConfig).Return(
+					tgbotapi.ChatMember{Status: "member"}, nil)
+			},
+			expected: false,
+		},
+		{
+			testName:   "No user",
+			chat:       groupMock,
+			permission: permissionAdmin,
+			expected:   false,
+		},
+		{
+			testName:   "Storage error",
+			chat:       groupMock,
+			user:       userMock,
+			permission: permissionAdmin,
+			setupMocks: func(
+				clientMock *mock_telegram.MockchatMemberGetter,
+				stMock *mock_storage.MockDataStorageInterface,
+			) {
+				stMock.EXPECT().GetChatSettings(groupMock.ID).Return(nil, errMock)
+			},
+			expectedErr: true,
+		},
+		{
+			testName:   "API error",
+			chat:       groupMock,
+			user:       userMock,
+			permission: permissionAdmin,
+			setupMocks: func(
+				clientMock *mock_telegram.MockchatMemberGetter,
+				stMock *mock_storage.MockDataStorageInterface,
+			) {
+				stMock.EXPECT().GetChatSettings(groupMock.ID).Return(adminsOnlySettings, nil)
+				clientMock.EXPECT().GetChatMember(memberConfig).Return(
+					tgbotapi.ChatMember{}, errMock)
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			clientMock := mock_telegram.NewMockchatMemberGetter(mockCtrl)
+			stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+			if testCase.setupMocks != nil {
+				testCase.setupMocks(clientMock, stMock)
+			}
+
+			allowed, err := hasPermission(clientMock, stMock,
+				testCase.chat, testCase.user, testCase.permission)
+
+			if (err != nil) != testCase.expectedErr {
+				t.Errorf("Expected error: %v, got %#v", testCase.expectedErr, err)
+			}
+			if allowed != testCase.expected {
+				t.Errorf("Expected %v, got %v", testCase.expected, allowed)
+			}
+		})
+	}
+}
+
+func TestDenyCommand(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
+
+	messageMock := &tgbotapi.Message{
+		MessageID: 456,
+		From:      &tgbotapi.User{ID: 321},
+		Chat:      &tgbotapi.Chat{ID: -123},
+	}
+
+	clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+		if msgCfg.ChatID != messageMock.Chat.ID {
+			t.Errorf(
+				"Expected to reply to the chat with ID %d, but reply sent to %d",
+				messageMock.Chat.ID, msgCfg.ChatID,
+			)
+		}
+
+		if msgCfg.ReplyToMessageID != messageMock.MessageID {
+			t.Errorf("Expected a reply to the message %d, got %d",
+				messageMock.MessageID, msgCfg.ReplyToMessageID)
+		}
+
+		expectedText := "only administrators"
+		if !strings.Contains(msgCfg.Text, expectedText) {
+			t.Errorf("Expected message to contain %#v, got %#v",
+				expectedText, msgCfg.Text)
+		}
+	})
+
+	if err := denyCommand(clientMock, stMock, messageMock); err != nil {
+		t.Errorf("Unexpected err: got %#v", err)
+	}
+}
+
+func TestDenyCallbackQuery(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockcallbackQueryAnswerer(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
+
+	callbackQueryMock := &tgbotapi.CallbackQuery{
+		ID:   "some-callback-id",
+		From: &tgbotapi.User{ID: 321},
+		Message: &tgbotapi.Message{
+			Chat: &tgbotapi.Chat{ID: -123},
+		},
+	}
+
+	clientMock.EXPECT().AnswerCallbackQuery(gomock.Any()).Do(func(config tgbotapi.CallbackConfig) {
+		if config.CallbackQueryID != callbackQueryMock.ID {
+			t.Errorf("Expected callback query ID %#v, got %#v",
+				callbackQueryMock.ID, config.CallbackQueryID)
+		}
+
+		if !config.ShowAlert || !strings.Contains(config.Text, "only administrators") {
+			t.Errorf("Expected an alert about permissions, got %#v", config)
+		}
+	})
+
+	if err := denyCallbackQuery(clientMock, stMock, callbackQueryMock); err != nil {
+		t.Errorf("Unexpected err: got %#v", err)
+	}
+}
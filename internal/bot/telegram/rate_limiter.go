@@ -0,0 +1,237 @@
+package telegram
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// bucketConfig defines capacity of a token bucket
+// and a rate at which tokens are added into the bucket
+type bucketConfig struct {
+	capacity  float64
+	perSecond float64
+}
+
+// rateLimits defines limits for outgoing messages
+type rateLimits struct {
+	global      bucketConfig
+	privateChat bucketConfig
+	groupChat   bucketConfig
+
+	// maxRetries is number of attempts to resend a message,
+	// after Telegram asked us to wait
+	maxRetries int
+}
+
+// defaultRateLimits are based on the Telegram Bot API limits:
+// ~30 messages per second globally, ~1 message per second in a chat
+// and ~20 messages per minute in a group
+//
+// See: https://core.telegram.org/bots/faq#my-bot-is-hitting-limits-how-do-i-avoid-this
+var defaultRateLimits = rateLimits{
+	global:      bucketConfig{capacity: 30, perSecond: 30},
+	privateChat: bucketConfig{capacity: 3, perSecond: 1},
+	groupChat:   bucketConfig{capacity: 20, perSecond: 20.0 / 60},
+	maxRetries:  3,
+}
+
+// maxIdleChatBuckets is number of per chat buckets after which
+// we start removing buckets of chats which were idle for a while
+const maxIdleChatBuckets = 10000
+
+// tokenBucket is a token bucket rate limiter.
+//
+// Tokens are reserved in advance: the number of tokens can become
+// negative, so callers are served in the order they made reservations
+type tokenBucket struct {
+	mu        sync.Mutex
+	config    bucketConfig
+	tokens    float64
+	updatedAt time.Time
+}
+
+func newTokenBucket(config bucketConfig, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		config:    config,
+		tokens:    config.capacity,
+		updatedAt: now,
+	}
+}
+
+// reserve takes a token from the bucket and returns
+// how long a caller must wait before using it
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	if elapsed > 0 {
+		b.tokens += elapsed * b.config.perSecond
+		if b.tokens > b.config.capacity {
+			b.tokens = b.config.capacity
+		}
+		b.updatedAt = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	seconds := -b.tokens / b.config.perSecond
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// isFull checks if the bucket is refilled up to its capacity
+func (b *tokenBucket) isFull(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	return b.tokens+elapsed*b.config.perSecond >= b.config.capacity
+}
+
+// rateLimitedSender is a sender decorator that throttles outgoing messages
+// to stay within the Telegram Bot API limits. It also resends messages,
+// when Telegram responds with "Too Many Requests" and asks us to wait.
+type rateLimitedSender struct {
+	next   sender
+	limits rateLimits
+
+	global *tokenBucket
+
+	chatsMu sync.Mutex
+	chats   map[int64]*tokenBucket
+
+	// queueDepth is number of messages that are waiting to be sent
+	queueDepth int64
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func newRateLimitedSender(next sender, limits rateLimits) *rateLimitedSender {
+	s := &rateLimitedSender{
+		next:   next,
+		limits: limits,
+		chats:  map[int64]*tokenBucket{},
+		now:    time.Now,
+		sleep:  time.Sleep,
+	}
+	s.global = newTokenBucket(limits.global, s.now())
+
+	return s
+}
+
+// Send waits for the rate limiters and sends a message
+func (s *rateLimitedSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	atomic.AddInt64(&s.queueDepth, 1)
+	defer atomic.AddInt64(&s.queueDepth, -1)
+
+	chatID, hasChatID := chattableChatID(c)
+
+	var message tgbotapi.Message
+	var err error
+	for attempt := 0; attempt <= s.limits.maxRetries; attempt++ {
+		s.wait(chatID, hasChatID)
+
+		message, err = s.next.Send(c)
+		if classifyAPIError(err) != apiErrorFloodWait {
+			break
+		}
+
+		retryAfter := err.(tgbotapi.Error).RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = 1
+		}
+		s.sleep(time.Duration(retryAfter) * time.Second)
+	}
+
+	return message, err
+}
+
+// QueueDepth returns number of messages that are waiting to be sent
+func (s *rateLimitedSender) QueueDepth() int {
+	return int(atomic.LoadInt64(&s.queueDepth))
+}
+
+// wait blocks until both the global and the chat rate limiters allow
+// to send a message
+func (s *rateLimitedSender) wait(chatID int64, hasChatID bool) {
+	now := s.now()
+
+	delay := s.global.reserve(now)
+	if hasChatID {
+		if chatDelay := s.chatBucket(chatID, now).reserve(now); chatDelay > delay {
+			delay = chatDelay
+		}
+	}
+
+	if delay > 0 {
+		s.sleep(delay)
+	}
+}
+
+func (s *rateLimitedSender) chatBucket(chatID int64, now time.Time) *tokenBucket {
+	s.chatsMu.Lock()
+	defer s.chatsMu.Unlock()
+
+	bucket, ok := s.chats[chatID]
+	if !ok {
+		if len(s.chats) >= maxIdleChatBuckets {
+			s.removeIdleChatBuckets(now)
+		}
+
+		// Group and channel IDs are negative
+		config := s.limits.privateChat
+		if chatID < 0 {
+			config = s.limits.groupChat
+		}
+
+		bucket = newTokenBucket(config, now)
+		s.chats[chatID] = bucket
+	}
+
+	return bucket
+}
+
+// removeIdleChatBuckets removes buckets which are full again,
+// so they are equivalent to new buckets
+func (s *rateLimitedSender) removeIdleChatBuckets(now time.Time) {
+	for chatID, bucket := range s.chats {
+		if bucket.isFull(now) {
+			delete(s.chats, chatID)
+		}
+	}
+}
+
+// chattableChatID returns ID of a chat where a Chattable will be sent to
+func chattableChatID(c tgbotapi.Chattable) (int64, bool) {
+	switch config := c.(type) {
+	case tgbotapi.MessageConfig:
+		return config.ChatID, config.ChatID != 0
+	case tgbotapi.EditMessageTextConfig:
+		return config.ChatID, config.ChatID != 0
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		return config.ChatID, config.ChatID != 0
+	case tgbotapi.DocumentConfig:
+		return config.ChatID, config.ChatID != 0
+	case tgbotapi.ChatActionConfig:
+		return config.ChatID, config.ChatID != 0
+	}
+	return 0, false
+}
+
+// botClientWithSender replaces the Send method of a bot client
+// with a custom sender. For example, with a rate limited one
+type botClientWithSender struct {
+	botClientInterface
+	sender sender
+}
+
+func (c *botClientWithSender) Send(chattable tgbotapi.Chattable) (tgbotapi.Message, error) {
+	return c.sender.Send(chattable)
+}
@@ -0,0 +1,244 @@
+package telegram
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+	bucket := newTokenBucket(bucketConfig{capacity: 2, perSecond: 1}, now)
+
+	testCases := []struct {
+		testName      string
+		elapsed       time.Duration
+		expectedDelay time.Duration
+	}{
+		{testName: "First token", expectedDelay: 0},
+		{testName: "Second token", expectedDelay: 0},
+		{testName: "Empty bucket", expectedDelay: time.Second},
+		{testName: "Reserved in advance", expectedDelay: 2 * time.Second},
+		{testName: "Refilled", elapsed: 10 * time.Second, expectedDelay: 0},
+		{testName: "Refilled up to capacity", expectedDelay: 0},
+		{testName: "Empty again", expectedDelay: time.Second},
+	}
+
+	for _, testCase := range testCases {
+		now = now.Add(testCase.elapsed)
+
+		delay := bucket.reserve(now)
+		if delay != testCase.expectedDelay {
+			t.Errorf("%s: expected delay %s, got %s",
+				testCase.testName, testCase.expectedDelay, delay)
+		}
+	}
+}
+
+func TestTokenBucketIsFull(t *testing.T) {
+	now := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+	bucket := newTokenBucket(bucketConfig{capacity: 2, perSecond: 1}, now)
+
+	if !bucket.isFull(now) {
+		t.Error("Expected a new bucket to be full")
+	}
+
+	bucket.reserve(now)
+	if bucket.isFull(now) {
+		t.Error("Expected the bucket not to be full after reservation")
+	}
+
+	if !bucket.isFull(now.Add(time.Second)) {
+		t.Error("Expected the bucket to be refilled")
+	}
+}
+
+func TestRateLimitedSender(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	senderMock := mock_telegram.NewMocksender(mockCtrl)
+
+	// Common data mocks
+	limits := rateLimits{
+		global:      bucketConfig{capacity: 10, perSecond: 10},
+		privateChat: bucketConfig{capacity: 1, perSecond: 1},
+		groupChat:   bucketConfig{capacity: 1, perSecond: 0.5},
+		maxRetries:  2,
+	}
+	floodErr := tgbotapi.Error{
+		Message:            "Too Many Requests: retry after 5",
+		ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 5},
+	}
+
+	// newSender creates a sender with a fake clock
+	// that records all delays
+	newSender := func(delays *[]time.Duration) *rateLimitedSender {
+		now := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+
+		s := newRateLimitedSender(senderMock, limits)
+		s.now = func() time.Time { return now }
+		s.sleep = func(d time.Duration) { *delays = append(*delays, d) }
+		s.global = newTokenBucket(limits.global, now)
+
+		return s
+	}
+
+	t.Run("Per chat limits", func(t *testing.T) {
+		var delays []time.Duration
+		s := newSender(&delays)
+
+		senderMock.EXPECT().Send(gomock.Any()).Times(4)
+
+		s.Send(tgbotapi.NewMessage(123, "private"))
+		s.Send(tgbotapi.NewMessage(123, "private"))
+		s.Send(tgbotapi.NewMessage(-123, "group"))
+		s.Send(tgbotapi.NewMessage(-123, "group"))
+
+		expectedDelays := []time.Duration{time.Second, 2 * time.Second}
+		if len(delays) != len(expectedDelays) {
+			t.Fatalf("Expected delays %v, got %v", expectedDelays, delays)
+		}
+		for i, expectedDelay := range expectedDelays {
+			if delays[i] != expectedDelay {
+				t.Errorf("Expected delays %v, got %v", expectedDelays, delays)
+			}
+		}
+	})
+
+	t.Run("Flood wait", func(t *testing.T) {
+		t.Run("Retry succeeds", func(t *testing.T) {
+			var delays []time.Duration
+			s := newSender(&delays)
+
+			gomock.InOrder(
+				senderMock.EXPECT().Send(gomock.Any()).Return(tgbotapi.Message{}, floodErr),
+				senderMock.EXPECT().Send(gomock.Any()).Return(tgbotapi.Message{MessageID: 1}, nil),
+			)
+
+			message, err := s.Send(tgbotapi.NewMessage(123, "text"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if message.MessageID != 1 {
+				t.Errorf("Expected message from the second attempt, got %#v", message)
+			}
+
+			expectedDelay := 5 * time.Second
+			if len(delays) == 0 || delays[0] != expectedDelay {
+				t.Errorf("Expected the first delay to be %s, got %v",
+					expectedDelay, delays)
+			}
+		})
+
+		t.Run("Retries exceeded", func(t *testing.T) {
+			var delays []time.Duration
+			s := newSender(&delays)
+
+			senderMock.EXPECT().Send(gomock.Any()).Return(
+				tgbotapi.Message{}, floodErr,
+			).Times(limits.maxRetries + 1)
+
+			_, err := s.Send(tgbotapi.NewMessage(123, "text"))
+			if err != floodErr {
+				t.Errorf("Expected err %#v, got %#v", floodErr, err)
+			}
+		})
+	})
+
+	t.Run("Other errors are not retried", func(t *testing.T) {
+		var delays []time.Duration
+		s := newSender(&delays)
+		errMock := errors.New("fake error")
+
+		senderMock.EXPECT().Send(gomock.Any()).Return(tgbotapi.Message{}, errMock)
+
+		_, err := s.Send(tgbotapi.NewMessage(123, "text"))
+		if err != errMock {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+
+	t.Run("Queue depth", func(t *testing.T) {
+		var delays []time.Duration
+		s := newSender(&delays)
+
+		senderMock.EXPECT().Send(gomock.Any()).Do(func(_ tgbotapi.Chattable) {
+			if s.QueueDepth() != 1 {
+				t.Errorf("Expected queue depth 1, got %d", s.QueueDepth())
+			}
+		})
+
+		s.Send(tgbotapi.NewMessage(123, "text"))
+
+		if s.QueueDepth() != 0 {
+			t.Errorf("Expected queue depth 0, got %d", s.QueueDepth())
+		}
+	})
+}
+
+func TestChattableChatID(t *testing.T) {
+	testCases := []struct {
+		testName        string
+		chattable       tgbotapi.Chattable
+		expectedChatID  int64
+		expectedHasChat bool
+	}{
+		{
+			testName:        "Message",
+			chattable:       tgbotapi.NewMessage(123, "text"),
+			expectedChatID:  123,
+			expectedHasChat: true,
+		},
+		{
+			testName: "Edit reply markup",
+			chattable: tgbotapi.NewEditMessageReplyMarkup(
+				-123, 1, tgbotapi.InlineKeyboardMarkup{}),
+			expectedChatID:  -123,
+			expectedHasChat: true,
+		},
+		{
+			testName: "Inline message",
+			chattable: tgbotapi.EditMessageTextConfig{
+				BaseEdit: tgbotapi.BaseEdit{InlineMessageID: "123"},
+			},
+			expectedHasChat: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			chatID, hasChat := chattableChatID(testCase.chattable)
+
+			if chatID != testCase.expectedChatID || hasChat != testCase.expectedHasChat {
+				t.Errorf("Expected (%d, %v), got (%d, %v)",
+					testCase.expectedChatID, testCase.expectedHasChat,
+					chatID, hasChat)
+			}
+		})
+	}
+}
+
+func TestBotClientWithSender(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	senderMock := mock_telegram.NewMocksender(mockCtrl)
+
+	msg := tgbotapi.NewMessage(123, "text")
+	senderMock.EXPECT().Send(msg)
+	clientMock.EXPECT().Token().Return("token")
+
+	client := &botClientWithSender{clientMock, senderMock}
+	client.Send(msg)
+
+	if client.Token() != "token" {
+		t.Error("Expected other methods to be delegated to the client")
+	}
+}
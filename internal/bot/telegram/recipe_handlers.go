@@ -0,0 +1,432 @@
+package telegram
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/category"
+	"github.com/m1kola/shipsterbot/internal/pkg/export"
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/itemname"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// Actions of the recipes menu.
+//
+// Callback query data of the menu is "recipe:del=<recipe id>"
+// for buttons that delete recipes and "recipe:close"
+// for the button that closes the menu.
+const (
+	recipeActionDelete = "del"
+	recipeActionClose  = "close"
+)
+
+const recipeValueSeparator = "="
+
+// Limits of recipes
+const (
+	recipeMaxIngredients = 50
+	recipeMaxServings    = 20
+)
+
+// recipeNameSeparator separates a name of a recipe from ingredients:
+// `/recipe pancakes: flour, 2 eggs, milk`
+const recipeNameSeparator = ":"
+
+// ingredientQuantityRegexp matches ingredients like
+// "2 eggs", "2 x eggs" or "2 × eggs", but not "7up"
+var ingredientQuantityRegexp = regexp.MustCompile(`^(\d+)(?:\s*[x×*])?\s+(.+)$`)
+
+func init() {
+	registerCommands(
+		botCommand{
+			name:        commandRecipe,
+			description: "command.recipe.description",
+			args: []commandArg{
+				{name: "recipe", optional: true},
+			},
+			helpSection:          helpSectionShoppingList,
+			showInHelpMessage:    true,
+			permission:           permissionAdmin,
+			commandHandler:       handleRecipe,
+			callbackQueryHandler: handleRecipeCallbackQuery,
+		},
+		botCommand{
+			name:        commandCook,
+			description: "command.cook.description",
+			args: []commandArg{
+				{name: "recipe"},
+				{name: "servings", optional: true},
+			},
+			helpSection:       helpSectionShoppingList,
+			showInHelpMessage: true,
+			commandHandler:    handleCook,
+		},
+	)
+}
+
+// handleRecipe shows recipes of the chat, shows a recipe
+// or saves a recipe: `/recipe pancakes: flour, 2 eggs, milk`
+func handleRecipe(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	chatID := message.Chat.ID
+
+	tr, err := userTranslator(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		recipes, err := st.GetRecipes(chatID)
+		if err != nil {
+			return fmt.Errorf(
+				"Unable to get recipes of the chat (ChatID=%d): %v",
+				chatID, err)
+		}
+
+		text, keyboard := recipesMenu(tr, recipes)
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.BaseChat.ReplyMarkup = keyboard
+		_, err = client.Send(msg)
+		return err
+	}
+
+	pieces := strings.SplitN(args, recipeNameSeparator, 2)
+	name := strings.TrimSpace(pieces[0])
+	if len(pieces) == 1 {
+		recipe, err := st.GetRecipe(chatID, name)
+		if err != nil {
+			return fmt.Errorf(
+				"Unable to get a recipe (ChatID=%d): %v",
+				chatID, err)
+		}
+
+		text := tr.T("recipe.not_found", name) + tr.T("recipe.help")
+		if recipe != nil {
+			text = tr.T("recipe.show", recipe.Name, formatItemStates(recipe.Ingredients))
+		}
+		msg := tgbotapi.NewMessage(chatID, text)
+		_, err = client.Send(msg)
+		return err
+	}
+
+	ingredients, ok := parseIngredients(pieces[1])
+	if !ok || name == "" || utf8.RuneCountInString(name) > export.MaxNameLength {
+		text := tr.T("recipe.invalid", recipeMaxIngredients) + tr.T("recipe.help")
+		msg := tgbotapi.NewMessage(chatID, text)
+		_, err = client.Send(msg)
+		return err
+	}
+
+	recipe := models.Recipe{
+		ChatID:      chatID,
+		Name:        name,
+		Ingredients: ingredients,
+		CreatedBy:   message.From.ID,
+	}
+	if err := st.SetRecipe(recipe); err != nil {
+		return fmt.Errorf(
+			"Unable to save a recipe (ChatID=%d, UserId=%d): %v",
+			chatID, message.From.ID, err)
+	}
+
+	text := tr.T("recipe.done", name, formatItemStates(ingredients), name)
+	msg := tgbotapi.NewMessage(chatID, text)
+	_, err = client.Send(msg)
+	return err
+}
+
+// handleRecipeCallbackQuery deletes recipes
+// by editing the message with the menu in place
+func handleRecipeCallbackQuery(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	callbackQuery *tgbotapi.CallbackQuery,
+	data string,
+) error {
+	client.AnswerCallbackQuery(tgbotapi.NewCallback(
+		callbackQuery.ID, ""))
+
+	chatID := callbackQuery.Message.Chat.ID
+	messageID := callbackQuery.Message.MessageID
+
+	if data == recipeActionClose {
+		return hideInlineKeyboard(client, chatID, messageID)
+	}
+
+	pieces := strings.SplitN(data, recipeValueSeparator, 2)
+	if len(pieces) != 2 || pieces[0] != recipeActionDelete {
+		return fmt.Errorf(
+			"Unable to parse an action from the CallbackQuery data %#v",
+			data)
+	}
+
+	recipeID, err := strconv.ParseInt(pieces[1], 10, 64)
+	if err != nil {
+		// User can't amend CallBackData, so most likely it's our fault
+		return fmt.Errorf(
+			"Unable to parse RecipeID from the CallbackQuery data %s: %v",
+			data, err)
+	}
+
+	tr, err := userTranslator(st, chatID, callbackQuery.From)
+	if err != nil {
+		return err
+	}
+
+	// Recipes are deleted only from the chat where the menu is
+	if err := st.DeleteRecipe(chatID, recipeID); err != nil {
+		return fmt.Errorf(
+			"Unable to delete a recipe (RecipeID=%d, ChatID=%d): %v",
+			recipeID, chatID, err)
+	}
+
+	recipes, err := st.GetRecipes(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get recipes of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	text, keyboard := recipesMenu(tr, recipes)
+	msg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	msg.ReplyMarkup = &keyboard
+	_, err = client.Send(msg)
+	if isMessageNotModifiedError(err) {
+		return nil
+	}
+	return err
+}
+
+// handleCook adds ingredients of a recipe into the shopping list.
+// The last number multiplies quantities: `/cook pancakes 2`
+func handleCook(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	chatID := message.Chat.ID
+
+	tr, err := userTranslator(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	words := strings.Fields(message.CommandArguments())
+	servings := 1
+	if len(words) > 1 {
+		n, err := strconv.Atoi(words[len(words)-1])
+		if err == nil && n >= 1 && n <= recipeMaxServings {
+			servings = n
+			words = words[:len(words)-1]
+		}
+	}
+	name := strings.Join(words, " ")
+
+	var recipe *models.Recipe
+	if name != "" {
+		recipe, err = st.GetRecipe(chatID, name)
+		if err != nil {
+			return fmt.Errorf(
+				"Unable to get a recipe (ChatID=%d): %v",
+				chatID, err)
+		}
+	}
+
+	if recipe == nil {
+		recipes, err := st.GetRecipes(chatID)
+		if err != nil {
+			return fmt.Errorf(
+				"Unable to get recipes of the chat (ChatID=%d): %v",
+				chatID, err)
+		}
+
+		var text string
+		switch {
+		case len(recipes) == 0:
+			text = tr.T("recipe.empty") + tr.T("recipe.help")
+		case name == "":
+			text = tr.T("cook.prompt", recipeNames(recipes))
+		default:
+			text = tr.T("cook.not_found", name, recipeNames(recipes))
+		}
+
+		msg := tgbotapi.NewMessage(chatID, text)
+		_, err = client.Send(msg)
+		return err
+	}
+
+	added, increased, err := cookRecipe(st, chatID, message.From.ID, recipe, servings)
+	if err != nil {
+		return err
+	}
+
+	text := tr.T("cook.done", recipe.Name, formatItemStates(append(added, increased...)))
+	if len(increased) > 0 {
+		text += " " + tr.N("cook.merged", len(increased), len(increased))
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	_, err = client.Send(msg)
+	return err
+}
+
+// cookRecipe adds ingredients of a recipe into the shopping list.
+// Quantities of ingredients which are already in the list are increased.
+// It returns added and increased ingredients with quantities for servings
+func cookRecipe(
+	st storage.DataStorageInterface,
+	chatID int64,
+	userID int,
+	recipe *models.Recipe,
+	servings int,
+) (added, increased []models.ItemState, err error) {
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	for _, ingredient := range recipe.Ingredients {
+		quantity := ingredient.Quantity
+		if quantity < 1 {
+			quantity = 1
+		}
+		quantity *= servings
+
+		if duplicate := findDuplicateItem(chatItems, ingredient.Name); duplicate != nil {
+			if err := st.IncreaseShoppingItemQuantity(duplicate.ID, quantity); err != nil {
+				return nil, nil, fmt.Errorf(
+					"Unable to increase quantity of a shopping item (ItemID=%d): %v",
+					duplicate.ID, err)
+			}
+
+			increased = append(increased, models.ItemState{
+				Name: duplicate.Name, Quantity: quantity})
+			continue
+		}
+
+		item, err := newShoppingItem(st, chatID, ingredient.Name, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Single items are stored without quantity
+		if quantity > 1 {
+			item.Quantity = quantity
+		}
+		if category.IsSupported(ingredient.Category) {
+			item.Category = ingredient.Category
+		}
+
+		if err := st.AddShoppingItemIntoShoppingList(item); err != nil {
+			return nil, nil, fmt.Errorf(
+				"Unable to add an ingredient into the shopping list (RecipeID=%d, ChatID=%d): %v",
+				recipe.ID, chatID, err)
+		}
+
+		chatItems = append(chatItems, &item)
+		added = append(added, models.ItemState{Name: item.Name, Quantity: quantity})
+	}
+	return added, increased, nil
+}
+
+// parseIngredients parses comma or new line separated
+// ingredients with optional quantities: "flour, 2 eggs, milk".
+//
+// Quantities of repeated ingredients are summed up, so cooking
+// the recipe doesn't add the same item twice
+func parseIngredients(text string) ([]models.ItemState, bool) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '\n'
+	})
+
+	var ingredients []models.ItemState
+	indexes := map[string]int{}
+	parsed := 0
+	for _, field := range fields {
+		ingredient := models.ItemState{Name: strings.TrimSpace(field), Quantity: 1}
+		if match := ingredientQuantityRegexp.FindStringSubmatch(ingredient.Name); match != nil {
+			quantity, err := strconv.Atoi(match[1])
+			if err != nil || quantity < 1 || quantity > export.MaxQuantity {
+				return nil, false
+			}
+
+			ingredient.Name = strings.TrimSpace(match[2])
+			ingredient.Quantity = quantity
+		}
+
+		if ingredient.Name == "" {
+			continue
+		}
+		if utf8.RuneCountInString(ingredient.Name) > export.MaxNameLength {
+			return nil, false
+		}
+
+		parsed++
+
+		name := itemname.Normalize(ingredient.Name)
+		if i, ok := indexes[name]; ok {
+			ingredients[i].Quantity += ingredient.Quantity
+			if ingredients[i].Quantity > export.MaxQuantity {
+				return nil, false
+			}
+			continue
+		}
+
+		indexes[name] = len(ingredients)
+		ingredients = append(ingredients, ingredient)
+	}
+
+	if len(ingredients) == 0 || parsed > recipeMaxIngredients {
+		return nil, false
+	}
+	return ingredients, true
+}
+
+// recipeNames returns a comma separated list of names of recipes
+func recipeNames(recipes []*models.Recipe) string {
+	names := make([]string, len(recipes))
+	for i, recipe := range recipes {
+		names[i] = recipe.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// recipesMenu returns text and an inline keyboard to delete recipes
+func recipesMenu(
+	tr *i18n.Translator,
+	recipes []*models.Recipe,
+) (string, tgbotapi.InlineKeyboardMarkup) {
+	closeRow := tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+		tr.T("settings.close"),
+		joinCallbackQueryData(commandRecipe, recipeActionClose)))
+
+	if len(recipes) == 0 {
+		text := tr.T("recipe.empty") + tr.T("recipe.help")
+		return text, tgbotapi.NewInlineKeyboardMarkup(closeRow)
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, recipe := range recipes {
+		text := tr.T("recipe.button",
+			recipe.Name, formatItemStates(recipe.Ingredients))
+		data := joinCallbackQueryData(commandRecipe,
+			recipeActionDelete+recipeValueSeparator+strconv.FormatInt(recipe.ID, 10))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(text, data)))
+	}
+	rows = append(rows, closeRow)
+
+	return tr.T("recipe.prompt"), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
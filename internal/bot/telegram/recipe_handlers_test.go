@@ -0,0 +1,319 @@
+package telegram
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+func TestHandleRecipe(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Common data mocks
+	chatID := int64(123)
+	newMessageMock := func(args string) *tgbotapi.Message {
+		messageMock := mock_telegram.MessageCommandMockSetup(commandRecipe, args)
+		messageMock.Chat = &tgbotapi.Chat{ID: chatID}
+		messageMock.From = &tgbotapi.User{ID: 321}
+		return messageMock
+	}
+
+	expectText := func(t *testing.T, expectedText string) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.HasPrefix(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to start with %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+	}
+
+	t.Run("Without arguments", func(t *testing.T) {
+		stMock.EXPECT().GetRecipes(chatID).Return([]*models.Recipe{
+			{ID: 1, Name: "omelette", Ingredients: []models.ItemState{
+				{Name: "eggs", Quantity: 3}}},
+			{ID: 2, Name: "pancakes", Ingredients: []models.ItemState{
+				{Name: "flour", Quantity: 1}, {Name: "milk", Quantity: 1}}},
+		}, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			keyboard, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+			if !ok {
+				t.Fatalf("Expected an inline keyboard, got %#v", msgCfg.ReplyMarkup)
+			}
+
+			data := keyboardCallbackData(keyboard)
+			expectedData := []string{"recipe:del=1", "recipe:del=2", "recipe:close"}
+			if !reflect.DeepEqual(data, expectedData) {
+				t.Errorf("Expected callback data %v, got %v", expectedData, data)
+			}
+
+			expectedText := "✕ pancakes: flour, milk"
+			if keyboard.InlineKeyboard[1][0].Text != expectedText {
+				t.Errorf("Expected button %#v, got %#v",
+					expectedText, keyboard.InlineKeyboard[1][0].Text)
+			}
+		})
+
+		err := handleRecipe(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Save", func(t *testing.T) {
+		stMock.EXPECT().SetRecipe(models.Recipe{
+			ChatID: chatID,
+			Name:   "Pancakes",
+			Ingredients: []models.ItemState{
+				{Name: "flour", Quantity: 1},
+				{Name: "eggs", Quantity: 2},
+				{Name: "milk", Quantity: 1},
+			},
+			CreatedBy: 321,
+		}).Return(nil)
+		expectText(t, `Done! I've saved the recipe "Pancakes": flour, 2 × eggs, milk.`)
+
+		err := handleRecipe(clientMock, stMock, newMessageMock("Pancakes: flour, 2 eggs,\nmilk"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Show", func(t *testing.T) {
+		stMock.EXPECT().GetRecipe(chatID, "pancakes").Return(&models.Recipe{
+			ID: 2, Name: "Pancakes", Ingredients: []models.ItemState{
+				{Name: "flour", Quantity: 1}, {Name: "eggs", Quantity: 2}},
+		}, nil)
+		expectText(t, "Pancakes: flour, 2 × eggs.")
+
+		err := handleRecipe(clientMock, stMock, newMessageMock("pancakes"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Unknown recipe", func(t *testing.T) {
+		stMock.EXPECT().GetRecipe(chatID, "waffles").Return(nil, nil)
+		expectText(t, `I don't know the recipe "waffles" yet.`)
+
+		err := handleRecipe(clientMock, stMock, newMessageMock("waffles"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Invalid ingredients", func(t *testing.T) {
+		expectText(t, "Sorry, I don't understand the recipe.")
+
+		err := handleRecipe(clientMock, stMock, newMessageMock("pancakes: , ,"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
+
+func TestHandleRecipeCallbackQuery(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+	clientMock.EXPECT().AnswerCallbackQuery(gomock.Any()).AnyTimes()
+
+	// Common data mocks
+	callbackQueryMock := &tgbotapi.CallbackQuery{
+		ID:   "some-callback-id",
+		From: &tgbotapi.User{ID: 321},
+		Message: &tgbotapi.Message{
+			MessageID: 456,
+			Chat:      &tgbotapi.Chat{ID: 123},
+		},
+	}
+	chatID := callbackQueryMock.Message.Chat.ID
+
+	t.Run("Delete a recipe", func(t *testing.T) {
+		stMock.EXPECT().DeleteRecipe(chatID, int64(1)).Return(nil)
+		stMock.EXPECT().GetRecipes(chatID).Return([]*models.Recipe{
+			{ID: 2, ChatID: chatID, Name: "pancakes"},
+		}, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.EditMessageTextConfig) {
+			if msgCfg.MessageID != callbackQueryMock.Message.MessageID {
+				t.Errorf("Expected to edit the message %d, got %d",
+					callbackQueryMock.Message.MessageID, msgCfg.MessageID)
+			}
+
+			data := keyboardCallbackData(*msgCfg.ReplyMarkup)
+			expectedData := []string{"recipe:del=2", "recipe:close"}
+			if !reflect.DeepEqual(data, expectedData) {
+				t.Errorf("Expected callback data %v, got %v", expectedData, data)
+			}
+		})
+
+		err := handleRecipeCallbackQuery(clientMock, stMock, callbackQueryMock, "del=1")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Close the menu", func(t *testing.T) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(
+			generateSendHideKeybaordCallChecker(t, callbackQueryMock))
+
+		err := handleRecipeCallbackQuery(clientMock, stMock, callbackQueryMock, "close")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		for _, data := range []string{"unknown", "del=not int", "add=1"} {
+			err := handleRecipeCallbackQuery(clientMock, stMock, callbackQueryMock, data)
+			if err == nil {
+				t.Errorf("Expected an error for %#v", data)
+			}
+		}
+	})
+}
+
+func TestHandleCook(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Common data mocks
+	chatID := int64(123)
+	userID := 321
+	newMessageMock := func(args string) *tgbotapi.Message {
+		messageMock := mock_telegram.MessageCommandMockSetup(commandCook, args)
+		messageMock.Chat = &tgbotapi.Chat{ID: chatID}
+		messageMock.From = &tgbotapi.User{ID: userID}
+		return messageMock
+	}
+	recipeMock := &models.Recipe{
+		ID:     1,
+		ChatID: chatID,
+		Name:   "Pancakes",
+		Ingredients: []models.ItemState{
+			{Name: "flour", Quantity: 1},
+			{Name: "eggs", Quantity: 2, Category: "dairy"},
+			{Name: "Milk", Quantity: 1},
+		},
+	}
+
+	expectText := func(t *testing.T, expectedText string) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if msgCfg.Text != expectedText {
+				t.Errorf("Expected %#v, got %#v", expectedText, msgCfg.Text)
+			}
+		})
+	}
+
+	t.Run("With servings", func(t *testing.T) {
+		stMock.EXPECT().GetRecipe(chatID, "pancakes").Return(recipeMock, nil)
+		stMock.EXPECT().GetShoppingItems(chatID).Return([]*models.ShoppingItem{
+			{ID: 7, ChatID: chatID, Name: "milk"},
+		}, nil)
+		stMock.EXPECT().GetCategoryKeywords(chatID).Return(nil, nil).Times(2)
+		stMock.EXPECT().IncreaseShoppingItemQuantity(int64(7), 2).Return(nil)
+
+		var added []models.ShoppingItem
+		stMock.EXPECT().AddShoppingItemIntoShoppingList(gomock.Any()).Do(
+			func(item models.ShoppingItem) {
+				added = append(added, item)
+			}).Return(nil).Times(2)
+		expectText(t, `Done! I've added ingredients of "Pancakes" into your shopping list: `+
+			`2 × flour, 4 × eggs, 2 × milk. `+
+			`1 of them was already in the list, so I've increased its quantity.`)
+
+		err := handleCook(clientMock, stMock, newMessageMock("pancakes 2"))
+		if err != nil {
+			t.Fatalf("Unexpected err: got %#v", err)
+		}
+
+		if len(added) != 2 {
+			t.Fatalf("Expected 2 added items, got %#v", added)
+		}
+		if added[0].Name != "flour" || added[0].Quantity != 2 {
+			t.Errorf("Expected 2 × flour, got %#v", added[0])
+		}
+		if added[1].Name != "eggs" || added[1].Quantity != 4 || added[1].Category != "dairy" {
+			t.Errorf("Expected 4 × eggs in dairy, got %#v", added[1])
+		}
+	})
+
+	t.Run("Number in the name", func(t *testing.T) {
+		// A single word is always a name of a recipe
+		stMock.EXPECT().GetRecipe(chatID, "42").Return(nil, nil)
+		stMock.EXPECT().GetRecipes(chatID).Return([]*models.Recipe{recipeMock}, nil)
+		expectText(t, `I don't know the recipe "42". Recipes of this chat: Pancakes.`)
+
+		err := handleCook(clientMock, stMock, newMessageMock("42"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Without arguments", func(t *testing.T) {
+		stMock.EXPECT().GetRecipes(chatID).Return([]*models.Recipe{recipeMock}, nil)
+		expectText(t, "Which recipe do you want to cook? Recipes of this chat: Pancakes.")
+
+		err := handleCook(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
+
+func TestParseIngredients(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected []models.ItemState
+		ok       bool
+	}{
+		{
+			text: "flour, 2 eggs, 3 x apples\n7up",
+			expected: []models.ItemState{
+				{Name: "flour", Quantity: 1},
+				{Name: "eggs", Quantity: 2},
+				{Name: "apples", Quantity: 3},
+				{Name: "7up", Quantity: 1},
+			},
+			ok: true,
+		},
+		{
+			text: "2 eggs, milk, Eggs",
+			expected: []models.ItemState{
+				{Name: "eggs", Quantity: 3},
+				{Name: "milk", Quantity: 1},
+			},
+			ok: true,
+		},
+		{text: " , ", ok: false},
+		{text: "999 eggs, 999 eggs", ok: false},
+		{text: "1000 eggs", ok: false},
+		{text: strings.Repeat("egg,", recipeMaxIngredients+1), ok: false},
+	}
+
+	for _, tc := range testCases {
+		actual, ok := parseIngredients(tc.text)
+		if ok != tc.ok || !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("Expected %#v (%v) for %#v, got %#v (%v)",
+				tc.expected, tc.ok, tc.text, actual, ok)
+		}
+	}
+}
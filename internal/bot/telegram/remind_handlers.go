@@ -0,0 +1,468 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/bot/telegram/markup"
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/logging"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/schedule"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// Actions of the reminders menu.
+//
+// Callback query data of the menu is "remind:del=<reminder id>"
+// for buttons that delete reminders and "remind:close"
+// for the button that closes the menu.
+const (
+	remindActionDelete = "del"
+	remindActionClose  = "close"
+)
+
+const remindValueSeparator = "="
+
+// reminderTimeLayout is how we show times of reminders to users
+const reminderTimeLayout = "2006-01-02 15:04"
+
+// reminderCronPrefix is the first word of schedules with cron expressions:
+// `/remind cron 0 10 * * 6`
+const reminderCronPrefix = "cron"
+
+// Words which users use for days in schedules of reminders
+var (
+	reminderDailyWords    = []string{"daily", "everyday", "щодня"}
+	reminderWorkdayWords  = []string{"weekdays", "будні"}
+	reminderTodayWords    = []string{"today", "сьогодні"}
+	reminderTomorrowWords = []string{"tomorrow", "завтра"}
+
+	reminderWeekdays = map[string]time.Weekday{
+		"monday": time.Monday, "mon": time.Monday,
+		"понеділок": time.Monday, "пн": time.Monday,
+		"tuesday": time.Tuesday, "tue": time.Tuesday,
+		"вівторок": time.Tuesday, "вт": time.Tuesday,
+		"wednesday": time.Wednesday, "wed": time.Wednesday,
+		"середа": time.Wednesday, "середу": time.Wednesday, "ср": time.Wednesday,
+		"thursday": time.Thursday, "thu": time.Thursday,
+		"четвер": time.Thursday, "чт": time.Thursday,
+		"friday": time.Friday, "fri": time.Friday,
+		"п'ятниця": time.Friday, "п'ятницю": time.Friday,
+		"пʼятниця": time.Friday, "пʼятницю": time.Friday, "пт": time.Friday,
+		"saturday": time.Saturday, "sat": time.Saturday,
+		"субота": time.Saturday, "суботу": time.Saturday, "сб": time.Saturday,
+		"sunday": time.Sunday, "sun": time.Sunday,
+		"неділя": time.Sunday, "неділю": time.Sunday, "нд": time.Sunday,
+	}
+)
+
+func init() {
+	registerCommands(botCommand{
+		name:                 commandRemind,
+		description:          "command.remind.description",
+		args:                 []commandArg{{name: "schedule", optional: true}},
+		helpSection:          helpSectionShoppingList,
+		showInHelpMessage:    true,
+		permission:           permissionAdmin,
+		commandHandler:       handleRemind,
+		callbackQueryHandler: handleRemindCallbackQuery,
+	})
+}
+
+// handleRemind shows reminders of the chat or adds a new reminder.
+// Times are in the timezone of the chat: `/remind saturday 10:00`
+func handleRemind(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	chatID := message.Chat.ID
+
+	tr, err := userTranslator(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	settings, err := st.GetChatSettings(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get settings of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	description := strings.Join(strings.Fields(message.CommandArguments()), " ")
+	if description == "" {
+		reminders, err := st.GetReminders(chatID)
+		if err != nil {
+			return fmt.Errorf(
+				"Unable to get reminders of the chat (ChatID=%d): %v",
+				chatID, err)
+		}
+
+		text, keyboard, err := remindMenu(tr, settings.Timezone, reminders)
+		if err != nil {
+			return err
+		}
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.BaseChat.ReplyMarkup = keyboard
+		_, err = client.Send(msg)
+		return err
+	}
+
+	loc, err := reminderLocation(settings.Timezone)
+	if err != nil {
+		return err
+	}
+
+	now := timeNow().In(loc)
+	expr, nextAt, ok := parseReminderSchedule(description, now)
+	if !ok {
+		text := tr.T("remind.invalid", description) +
+			tr.T("remind.help", settings.Timezone)
+		msg := tgbotapi.NewMessage(chatID, text)
+		_, err = client.Send(msg)
+		return err
+	}
+
+	reminder := models.Reminder{
+		ChatID:      chatID,
+		Description: description,
+		Schedule:    expr,
+		Timezone:    settings.Timezone,
+		NextAt:      nextAt,
+		CreatedBy:   message.From.ID,
+	}
+	if err := st.AddReminder(reminder); err != nil {
+		return fmt.Errorf(
+			"Unable to add a reminder (ChatID=%d, UserId=%d): %v",
+			chatID, message.From.ID, err)
+	}
+
+	text := tr.T("remind.done", description, nextAt.Format(reminderTimeLayout))
+	msg := tgbotapi.NewMessage(chatID, text)
+	_, err = client.Send(msg)
+	return err
+}
+
+// handleRemindCallbackQuery deletes reminders
+// by editing the message with the menu in place
+func handleRemindCallbackQuery(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	callbackQuery *tgbotapi.CallbackQuery,
+	data string,
+) error {
+	client.AnswerCallbackQuery(tgbotapi.NewCallback(
+		callbackQuery.ID, ""))
+
+	chatID := callbackQuery.Message.Chat.ID
+	messageID := callbackQuery.Message.MessageID
+
+	if data == remindActionClose {
+		return hideInlineKeyboard(client, chatID, messageID)
+	}
+
+	pieces := strings.SplitN(data, remindValueSeparator, 2)
+	if len(pieces) != 2 || pieces[0] != remindActionDelete {
+		return fmt.Errorf(
+			"Unable to parse an action from the CallbackQuery data %#v",
+			data)
+	}
+
+	reminderID, err := strconv.ParseInt(pieces[1], 10, 64)
+	if err != nil {
+		// User can't amend CallBackData, so most likely it's our fault
+		return fmt.Errorf(
+			"Unable to parse ReminderID from the CallbackQuery data %s: %v",
+			data, err)
+	}
+
+	tr, err := userTranslator(st, chatID, callbackQuery.From)
+	if err != nil {
+		return err
+	}
+
+	reminder, err := st.GetReminder(reminderID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get a reminder (ReminderID=%d): %v",
+			reminderID, err)
+	}
+
+	// Someone else could delete the reminder using
+	// another message with the menu
+	if reminder != nil && reminder.ChatID == chatID {
+		if err := st.DeleteReminder(reminderID); err != nil {
+			return fmt.Errorf(
+				"Unable to delete a reminder (ReminderID=%d): %v",
+				reminderID, err)
+		}
+	}
+
+	settings, err := st.GetChatSettings(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get settings of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	reminders, err := st.GetReminders(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get reminders of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	text, keyboard, err := remindMenu(tr, settings.Timezone, reminders)
+	if err != nil {
+		return err
+	}
+	msg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	msg.ReplyMarkup = &keyboard
+	_, err = client.Send(msg)
+	if isMessageNotModifiedError(err) {
+		return nil
+	}
+	return err
+}
+
+// sendDueReminders sends shopping lists into chats with due reminders
+func sendDueReminders(
+	client sender,
+	st storage.DataStorageInterface,
+	logger *logging.Logger,
+	now time.Time,
+) {
+	reminders, err := st.GetDueReminders(now)
+	if err != nil {
+		logger.Error("Unable to get due reminders", "err", err)
+		return
+	}
+
+	for _, reminder := range reminders {
+		reminderLogger := logger.With(
+			"chat_id", reminder.ChatID, "reminder_id", reminder.ID)
+		if err := sendReminder(client, st, reminderLogger, reminder, now); err != nil {
+			reminderLogger.Error("Unable to send a reminder", "err", err)
+		}
+	}
+}
+
+// sendReminder sends a shopping list into a chat and schedules
+// the reminder for the next time. One-off reminders are deleted
+func sendReminder(
+	client sender,
+	st storage.DataStorageInterface,
+	logger *logging.Logger,
+	reminder *models.Reminder,
+	now time.Time,
+) error {
+	// Schedule the reminder before sending, so we don't
+	// send it every minute, if something goes wrong
+	nextAt := nextReminderTime(logger, reminder, now)
+	if nextAt.IsZero() {
+		if err := st.DeleteReminder(reminder.ID); err != nil {
+			return fmt.Errorf(
+				"Unable to delete a reminder (ReminderID=%d): %v",
+				reminder.ID, err)
+		}
+	} else {
+		if err := st.UpdateReminderNextAt(reminder.ID, nextAt); err != nil {
+			return fmt.Errorf(
+				"Unable to schedule a reminder (ReminderID=%d): %v",
+				reminder.ID, err)
+		}
+	}
+
+	// The translator is usable even if there is an error
+	chatID := reminder.ChatID
+	tr, err := chatTranslator(st, chatID)
+	if err != nil {
+		logger.Error("Unable to choose a language of the chat", "err", err)
+	}
+
+	text := markup.NewBuilder(markup.ModeHTML)
+	text.Text(tr.T("remind.fired") + "\n\n")
+	if err := writeShoppingList(st, tr, chatID, text); err != nil {
+		return err
+	}
+
+	msg := newMarkupMessage(chatID, text)
+	if _, err := client.Send(msg); err != nil {
+		// The reminder is scheduled already, so we only
+		// need to stop messaging chats which blocked the bot
+		handleAPIError(st, logger, chatID, err)
+	}
+	return nil
+}
+
+// nextReminderTime returns when a reminder must be sent next time.
+// It returns the zero time for one-off reminders and schedules
+// which never fire again
+func nextReminderTime(
+	logger *logging.Logger,
+	reminder *models.Reminder,
+	now time.Time,
+) time.Time {
+	if reminder.Schedule == "" {
+		return time.Time{}
+	}
+
+	s, err := schedule.Parse(reminder.Schedule)
+	if err != nil {
+		// We validate schedules before saving them,
+		// so the reminder is broken and it's useless
+		logger.Error("Unable to parse a schedule of the reminder", "err", err)
+		return time.Time{}
+	}
+	loc, err := reminderLocation(reminder.Timezone)
+	if err != nil {
+		// Missing the time is worse than sending
+		// the reminder at a wrong hour
+		logger.Error("Unable to load a timezone of the reminder", "err", err)
+		loc = time.UTC
+	}
+	return s.Next(now.In(loc))
+}
+
+// parseReminderSchedule parses a schedule of a reminder like
+// "saturday 10:00", "daily 9:00", "tomorrow 18:30", "2018-10-20 10:00"
+// or "cron 0 10 * * 6". Times are in the location of now.
+//
+// It returns a cron expression and when the reminder must be sent first time.
+// The expression is empty for one-off reminders
+func parseReminderSchedule(text string, now time.Time) (string, time.Time, bool) {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) > 0 && words[0] == reminderCronPrefix {
+		expr := strings.Join(words[1:], " ")
+		s, err := schedule.Parse(expr)
+		if err != nil {
+			return "", time.Time{}, false
+		}
+
+		nextAt := s.Next(now)
+		return expr, nextAt, !nextAt.IsZero()
+	}
+
+	if len(words) != 2 {
+		return "", time.Time{}, false
+	}
+
+	clock, err := time.Parse("15:04", words[1])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	hour, minute := clock.Hour(), clock.Minute()
+
+	day := words[0]
+	var expr string
+	switch {
+	case containsString(reminderDailyWords, day):
+		expr = fmt.Sprintf("%d %d * * *", minute, hour)
+	case containsString(reminderWorkdayWords, day):
+		expr = fmt.Sprintf("%d %d * * 1-5", minute, hour)
+	default:
+		if weekday, ok := reminderWeekdays[day]; ok {
+			expr = fmt.Sprintf("%d %d * * %d", minute, hour, weekday)
+		}
+	}
+
+	if expr != "" {
+		s, err := schedule.Parse(expr)
+		if err != nil {
+			return "", time.Time{}, false
+		}
+		return expr, s.Next(now), true
+	}
+
+	// One-off reminders
+	date := now
+	switch {
+	case containsString(reminderTodayWords, day):
+	case containsString(reminderTomorrowWords, day):
+		date = now.AddDate(0, 0, 1)
+	default:
+		date, err = time.ParseInLocation("2006-01-02", day, now.Location())
+		if err != nil {
+			return "", time.Time{}, false
+		}
+	}
+
+	nextAt := time.Date(
+		date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, now.Location())
+	return "", nextAt, nextAt.After(now)
+}
+
+// reminderLocation returns a location of a timezone.
+// Timezones come from settings, so an error means that
+// the timezone database is missing or out of date
+func reminderLocation(timezone string) (*time.Location, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Unable to load the timezone (Timezone=%s): %v",
+			timezone, err)
+	}
+	return loc, nil
+}
+
+// checkTimezones makes sure that all timezones
+// which chats can choose from can be loaded
+func checkTimezones(timezones []string) error {
+	for _, timezone := range timezones {
+		if _, err := reminderLocation(timezone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remindMenu returns text and an inline keyboard to delete reminders
+func remindMenu(
+	tr *i18n.Translator,
+	timezone string,
+	reminders []*models.Reminder,
+) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	closeRow := tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+		tr.T("settings.close"),
+		joinCallbackQueryData(commandRemind, remindActionClose)))
+
+	if len(reminders) == 0 {
+		text := tr.T("remind.empty") + tr.T("remind.help", timezone)
+		return text, tgbotapi.NewInlineKeyboardMarkup(closeRow), nil
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, reminder := range reminders {
+		loc, err := reminderLocation(reminder.Timezone)
+		if err != nil {
+			return "", tgbotapi.InlineKeyboardMarkup{}, err
+		}
+
+		nextAt := reminder.NextAt.In(loc)
+		text := tr.T("remind.button",
+			reminder.Description, nextAt.Format(reminderTimeLayout))
+		data := joinCallbackQueryData(commandRemind,
+			remindActionDelete+remindValueSeparator+strconv.FormatInt(reminder.ID, 10))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(text, data)))
+	}
+	rows = append(rows, closeRow)
+
+	text := tr.T("remind.prompt") + tr.T("remind.help", timezone)
+	return text, tgbotapi.NewInlineKeyboardMarkup(rows...), nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,354 @@
+package telegram
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+func TestHandleRemind(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kiev")
+	if err != nil {
+		t.Skipf("Timezone database is not available: %v", err)
+	}
+
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Mock: timeNow. It's Monday 10:00 in Kyiv
+	nowMock := time.Date(2018, 10, 22, 7, 0, 0, 0, time.UTC)
+	oldTimeNow := timeNow
+	defer func() { timeNow = oldTimeNow }()
+	timeNow = func() time.Time { return nowMock }
+
+	// Common data mocks
+	errMock := errors.New("fake error")
+	chatID := int64(123)
+	settingsMock := models.NewChatSettings(chatID)
+	settingsMock.Timezone = "Europe/Kiev"
+	stMock.EXPECT().GetChatSettings(chatID).Return(settingsMock, nil).AnyTimes()
+
+	newMessageMock := func(args string) *tgbotapi.Message {
+		messageMock := mock_telegram.MessageCommandMockSetup(commandRemind, args)
+		messageMock.Chat = &tgbotapi.Chat{ID: chatID}
+		messageMock.From = &tgbotapi.User{ID: 321}
+		return messageMock
+	}
+
+	expectText := func(t *testing.T, expectedText string) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+	}
+
+	t.Run("Without arguments", func(t *testing.T) {
+		stMock.EXPECT().GetReminders(chatID).Return([]*models.Reminder{
+			{
+				ID: 1, ChatID: chatID, Description: "saturday 10:00",
+				Timezone: "Europe/Kiev", NextAt: time.Date(2018, 10, 27, 7, 0, 0, 0, time.UTC),
+			},
+		}, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.Contains(msgCfg.Text, "Europe/Kiev timezone") {
+				t.Errorf("Expected the timezone in the message, got %#v", msgCfg.Text)
+			}
+
+			keyboard, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+			if !ok {
+				t.Fatalf("Expected an inline keyboard, got %#v", msgCfg.ReplyMarkup)
+			}
+
+			data := keyboardCallbackData(keyboard)
+			expectedData := []string{"remind:del=1", "remind:close"}
+			if !reflect.DeepEqual(data, expectedData) {
+				t.Errorf("Expected callback data %v, got %v", expectedData, data)
+			}
+
+			expectedText := "✕ saturday 10:00, next: 2018-10-27 10:00"
+			if keyboard.InlineKeyboard[0][0].Text != expectedText {
+				t.Errorf("Expected button %#v, got %#v",
+					expectedText, keyboard.InlineKeyboard[0][0].Text)
+			}
+		})
+
+		err := handleRemind(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Recurring reminder", func(t *testing.T) {
+		stMock.EXPECT().AddReminder(gomock.Any()).Do(func(reminder models.Reminder) {
+			expectedNextAt := time.Date(2018, 10, 27, 10, 0, 0, 0, kyiv)
+			if reminder.Schedule != "0 10 * * 6" ||
+				reminder.Timezone != "Europe/Kiev" ||
+				reminder.Description != "Saturday 10:00" ||
+				!reminder.NextAt.Equal(expectedNextAt) {
+				t.Errorf("Unexpected reminder %#v", reminder)
+			}
+		}).Return(nil)
+		expectText(t, "Next time: 2018-10-27 10:00")
+
+		err := handleRemind(clientMock, stMock, newMessageMock("Saturday  10:00"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("One-off reminder", func(t *testing.T) {
+		stMock.EXPECT().AddReminder(gomock.Any()).Do(func(reminder models.Reminder) {
+			expectedNextAt := time.Date(2018, 10, 23, 18, 30, 0, 0, kyiv)
+			if reminder.Schedule != "" || !reminder.NextAt.Equal(expectedNextAt) {
+				t.Errorf("Unexpected reminder %#v", reminder)
+			}
+		}).Return(nil)
+		expectText(t, "Next time: 2018-10-23 18:30")
+
+		err := handleRemind(clientMock, stMock, newMessageMock("tomorrow 18:30"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Invalid schedule", func(t *testing.T) {
+		expectText(t, `don't understand when to remind you: "someday"`)
+
+		err := handleRemind(clientMock, stMock, newMessageMock("someday"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().AddReminder(gomock.Any()).Return(errMock)
+
+		err := handleRemind(clientMock, stMock, newMessageMock("daily 9:00"))
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+}
+
+func TestHandleRemindCallbackQuery(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
+	clientMock.EXPECT().AnswerCallbackQuery(gomock.Any()).AnyTimes()
+
+	// Common data mocks
+	callbackQueryMock := &tgbotapi.CallbackQuery{
+		ID:   "some-callback-id",
+		From: &tgbotapi.User{ID: 321},
+		Message: &tgbotapi.Message{
+			MessageID: 456,
+			Chat:      &tgbotapi.Chat{ID: 123},
+		},
+	}
+	chatID := callbackQueryMock.Message.Chat.ID
+
+	t.Run("Delete a reminder", func(t *testing.T) {
+		stMock.EXPECT().GetReminder(int64(1)).Return(
+			&models.Reminder{ID: 1, ChatID: chatID}, nil)
+		stMock.EXPECT().DeleteReminder(int64(1)).Return(nil)
+		stMock.EXPECT().GetReminders(chatID).Return(nil, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.EditMessageTextConfig) {
+			if msgCfg.MessageID != callbackQueryMock.Message.MessageID {
+				t.Errorf("Expected to edit the message %d, got %d",
+					callbackQueryMock.Message.MessageID, msgCfg.MessageID)
+			}
+
+			expectedText := "There are no reminders in this chat yet"
+			if !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+
+		err := handleRemindCallbackQuery(clientMock, stMock, callbackQueryMock, "del=1")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Reminder of another chat", func(t *testing.T) {
+		stMock.EXPECT().GetReminder(int64(1)).Return(
+			&models.Reminder{ID: 1, ChatID: 999}, nil)
+		stMock.EXPECT().GetReminders(chatID).Return(nil, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Return(
+			tgbotapi.Message{}, tgbotapi.Error{Message: "Bad Request: message is not modified"})
+
+		err := handleRemindCallbackQuery(clientMock, stMock, callbackQueryMock, "del=1")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Close the menu", func(t *testing.T) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(
+			generateSendHideKeybaordCallChecker(t, callbackQueryMock))
+
+		err := handleRemindCallbackQuery(clientMock, stMock, callbackQueryMock, "close")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		for _, data := range []string{"unknown", "del=not int", "add=1"} {
+			err := handleRemindCallbackQuery(clientMock, stMock, callbackQueryMock, data)
+			if err == nil {
+				t.Errorf("Expected an error for %#v", data)
+			}
+		}
+	})
+}
+
+func TestSendDueReminders(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
+
+	// Common data mocks
+	nowMock := time.Date(2018, 10, 27, 10, 0, 30, 0, time.UTC)
+
+	t.Run("Success", func(t *testing.T) {
+		stMock.EXPECT().GetDueReminders(nowMock).Return([]*models.Reminder{
+			{ID: 1, ChatID: 1, Schedule: "0 10 * * 6", Timezone: "UTC"},
+			{ID: 2, ChatID: 2, Timezone: "UTC"},
+			{ID: 3, ChatID: 3, Schedule: "broken", Timezone: "UTC"},
+		}, nil)
+
+		// Recurring reminder
+		stMock.EXPECT().UpdateReminderNextAt(int64(1),
+			time.Date(2018, 11, 3, 10, 0, 0, 0, time.UTC)).Return(nil)
+		stMock.EXPECT().GetShoppingItems(int64(1)).Return([]*models.ShoppingItem{
+			{ID: 1, Name: "Milk"},
+		}, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			for _, expectedText := range []string{"Time to go shopping!", "1. Milk"} {
+				if msgCfg.ChatID != 1 || !strings.Contains(msgCfg.Text, expectedText) {
+					t.Errorf("Expected message to contain %#v, got %#v",
+						expectedText, msgCfg.Text)
+				}
+			}
+		})
+
+		// One-off reminder
+		stMock.EXPECT().DeleteReminder(int64(2)).Return(nil)
+		stMock.EXPECT().GetShoppingItems(int64(2)).Return(nil, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			expectedText := "Your shopping list is empty"
+			if msgCfg.ChatID != 2 || !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+
+		// Broken reminders are sent for the last time
+		stMock.EXPECT().DeleteReminder(int64(3)).Return(nil)
+		stMock.EXPECT().GetShoppingItems(int64(3)).Return(nil, nil)
+		clientMock.EXPECT().Send(gomock.Any())
+
+		sendDueReminders(clientMock, stMock, discardLogger, nowMock)
+	})
+
+	t.Run("Blocked bot", func(t *testing.T) {
+		stMock.EXPECT().GetDueReminders(nowMock).Return([]*models.Reminder{
+			{ID: 1, ChatID: 1, Schedule: "0 10 * * 6", Timezone: "UTC"},
+		}, nil)
+		stMock.EXPECT().UpdateReminderNextAt(int64(1), gomock.Any()).Return(nil)
+		stMock.EXPECT().GetShoppingItems(int64(1)).Return(nil, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Return(tgbotapi.Message{},
+			tgbotapi.Error{Message: "Forbidden: bot was blocked by the user"})
+		stMock.EXPECT().MarkChatInactive(int64(1)).Return(nil)
+
+		sendDueReminders(clientMock, stMock, discardLogger, nowMock)
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().GetDueReminders(nowMock).Return([]*models.Reminder{
+			{ID: 1, ChatID: 1, Timezone: "UTC"},
+		}, nil)
+		stMock.EXPECT().DeleteReminder(int64(1)).Return(errors.New("fake error"))
+
+		sendDueReminders(clientMock, stMock, discardLogger, nowMock)
+	})
+}
+
+func TestParseReminderSchedule(t *testing.T) {
+	// Monday
+	now := time.Date(2018, 10, 22, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		text           string
+		expectedExpr   string
+		expectedNextAt time.Time
+	}{
+		{"daily 9:00", "0 9 * * *", time.Date(2018, 10, 23, 9, 0, 0, 0, time.UTC)},
+		{"щодня 13:05", "5 13 * * *", time.Date(2018, 10, 22, 13, 5, 0, 0, time.UTC)},
+		{"weekdays 18:30", "30 18 * * 1-5", time.Date(2018, 10, 22, 18, 30, 0, 0, time.UTC)},
+		{"Saturday 10:00", "0 10 * * 6", time.Date(2018, 10, 27, 10, 0, 0, 0, time.UTC)},
+		{"суботу 10:00", "0 10 * * 6", time.Date(2018, 10, 27, 10, 0, 0, 0, time.UTC)},
+		{"sun 08:00", "0 8 * * 0", time.Date(2018, 10, 28, 8, 0, 0, 0, time.UTC)},
+		{"today 20:00", "", time.Date(2018, 10, 22, 20, 0, 0, 0, time.UTC)},
+		{"завтра 9:15", "", time.Date(2018, 10, 23, 9, 15, 0, 0, time.UTC)},
+		{"2018-12-24 12:00", "", time.Date(2018, 12, 24, 12, 0, 0, 0, time.UTC)},
+		{"cron */30 9-10 * * *", "*/30 9-10 * * *", time.Date(2018, 10, 23, 9, 0, 0, 0, time.UTC)},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.text, func(t *testing.T) {
+			expr, nextAt, ok := parseReminderSchedule(testCase.text, now)
+			if !ok {
+				t.Fatal("Expected the schedule to be valid")
+			}
+			if expr != testCase.expectedExpr {
+				t.Errorf("Expected expression %#v, got %#v", testCase.expectedExpr, expr)
+			}
+			if !nextAt.Equal(testCase.expectedNextAt) {
+				t.Errorf("Expected %v, got %v", testCase.expectedNextAt, nextAt)
+			}
+		})
+	}
+
+	for _, text := range []string{
+		"", "10:00", "saturday", "saturday 25:00", "someday 10:00",
+		"today 9:00", "2018-01-01 10:00", "cron 0 10 * *", "cron 0 0 30 2 *",
+	} {
+		if _, _, ok := parseReminderSchedule(text, now); ok {
+			t.Errorf("Expected %#v to be invalid", text)
+		}
+	}
+}
+
+func TestCheckTimezones(t *testing.T) {
+	if err := checkTimezones(settingsTimezones); err != nil {
+		t.Errorf("Unexpected err: got %#v", err)
+	}
+
+	if err := checkTimezones([]string{"Europe/Atlantis"}); err == nil {
+		t.Error("Expected an error for an unknown timezone")
+	}
+}
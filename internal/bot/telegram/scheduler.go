@@ -0,0 +1,100 @@
+package telegram
+
+import (
+	"time"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/logging"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// schedulerInterval is how often the scheduler runs scheduled jobs
+const schedulerInterval = time.Minute
+
+// timeNow returns the current time. Tests replace it
+var timeNow = time.Now
+
+// scheduledJob is a job which the bot runs on its own,
+// without updates from users. For example, it sends reminders
+type scheduledJob func(
+	client sender,
+	st storage.DataStorageInterface,
+	logger *logging.Logger,
+	now time.Time,
+)
+
+// scheduledJobs are jobs which the scheduler runs on every tick.
+// Jobs which depend on settings of the bot app are added on start
+var scheduledJobs = []scheduledJob{
+	addDueStaples,
+	sendDueReminders,
+}
+
+// runScheduler runs scheduled jobs on every tick.
+// Jobs keep their state in the storage, so they survive restarts
+var runScheduler = func(
+	client sender,
+	st storage.DataStorageInterface,
+	logger *logging.Logger,
+	jobs []scheduledJob,
+	ticks <-chan time.Time,
+) {
+	// Jobs change shopping lists on behalf of the bot
+	st = storage.WithAuditLog(st, models.Actor{})
+
+	for now := range ticks {
+		for _, job := range jobs {
+			// A broken job must not stop other jobs and next ticks
+			if err := runScheduledJob(job, client, st, logger, now); err != nil {
+				jobLogger := logger.With("tick", now)
+				if pErr, ok := err.(panicError); ok {
+					jobLogger = jobLogger.With("stack", string(pErr.stack))
+				}
+				jobLogger.Error("Scheduled job panicked", "err", err)
+			}
+		}
+	}
+}
+
+// runScheduledJob runs a job and turns its panic into panicError
+func runScheduledJob(
+	job scheduledJob,
+	client sender,
+	st storage.DataStorageInterface,
+	logger *logging.Logger,
+	now time.Time,
+) (err error) {
+	defer recoverPanic(&err)
+	job(client, st, logger, now)
+	return nil
+}
+
+// auditLogCleanUpInterval is how often the audit log is cleaned up
+const auditLogCleanUpInterval = time.Hour
+
+// cleanUpAuditLog returns a job which deletes changes
+// older than the retention from the audit log once an hour.
+//
+// Ticks can be late or skipped, so the job counts time
+// from the last run instead of waiting for a specific minute
+func cleanUpAuditLog(retention time.Duration) scheduledJob {
+	var lastRun time.Time
+	return func(
+		client sender,
+		st storage.DataStorageInterface,
+		logger *logging.Logger,
+		now time.Time,
+	) {
+		if !lastRun.IsZero() && now.Sub(lastRun) < auditLogCleanUpInterval {
+			return
+		}
+		lastRun = now
+
+		deleted, err := st.DeleteAuditLogEntriesBefore(now.Add(-retention))
+		if err != nil {
+			logger.Error("Unable to clean up the audit log", "err", err)
+			return
+		}
+		logger.Debug("The audit log is cleaned up", "deleted", deleted)
+	}
+}
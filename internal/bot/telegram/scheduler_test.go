@@ -0,0 +1,97 @@
+package telegram
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/logging"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+func TestRunScheduler(t *testing.T) {
+	var calls []time.Time
+	jobMock := func(
+		client sender,
+		st storage.DataStorageInterface,
+		logger *logging.Logger,
+		now time.Time,
+	) {
+		calls = append(calls, now)
+	}
+
+	ticks := make(chan time.Time, 2)
+	firstTick := time.Date(2018, 10, 22, 12, 0, 0, 0, time.UTC)
+	secondTick := firstTick.Add(schedulerInterval)
+	ticks <- firstTick
+	ticks <- secondTick
+	close(ticks)
+
+	runScheduler(nil, nil, discardLogger, []scheduledJob{jobMock, jobMock}, ticks)
+
+	expectedCalls := []time.Time{firstTick, firstTick, secondTick, secondTick}
+	if len(calls) != len(expectedCalls) {
+		t.Fatalf("Expected %d calls, got %d", len(expectedCalls), len(calls))
+	}
+	for i := range calls {
+		if !calls[i].Equal(expectedCalls[i]) {
+			t.Errorf("Expected call %d at %v, got %v", i, expectedCalls[i], calls[i])
+		}
+	}
+}
+
+func TestRunSchedulerRecovers(t *testing.T) {
+	var calls int
+	jobMock := func(
+		client sender,
+		st storage.DataStorageInterface,
+		logger *logging.Logger,
+		now time.Time,
+	) {
+		calls++
+	}
+	panickingJobMock := func(
+		client sender,
+		st storage.DataStorageInterface,
+		logger *logging.Logger,
+		now time.Time,
+	) {
+		panic("fake panic")
+	}
+
+	ticks := make(chan time.Time, 2)
+	ticks <- time.Date(2018, 10, 22, 12, 0, 0, 0, time.UTC)
+	ticks <- time.Date(2018, 10, 22, 12, 1, 0, 0, time.UTC)
+	close(ticks)
+
+	runScheduler(nil, nil, discardLogger,
+		[]scheduledJob{panickingJobMock, jobMock}, ticks)
+
+	if calls != 2 {
+		t.Errorf("Expected 2 calls of the job after the panicking one, got %d", calls)
+	}
+}
+
+func TestCleanUpAuditLog(t *testing.T) {
+	retention := 24 * time.Hour
+	job := cleanUpAuditLog(retention)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+	firstRun := time.Date(2018, 10, 22, 12, 30, 0, 0, time.UTC)
+	secondRun := firstRun.Add(time.Hour)
+	gomock.InOrder(
+		stMock.EXPECT().DeleteAuditLogEntriesBefore(firstRun.Add(-retention)).Return(int64(2), nil),
+		stMock.EXPECT().DeleteAuditLogEntriesBefore(secondRun.Add(-retention)).Return(int64(0), nil),
+	)
+
+	// The first tick cleans up the log, then the job waits for an hour
+	job(nil, stMock, discardLogger, firstRun)
+	job(nil, stMock, discardLogger, firstRun.Add(schedulerInterval))
+	job(nil, stMock, discardLogger, secondRun.Add(-schedulerInterval))
+	job(nil, stMock, discardLogger, secondRun)
+}
@@ -0,0 +1,359 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/money"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// Settings which can be changed via the settings menu.
+//
+// Callback query data of the menu is "settings:<setting>=<value>"
+// for buttons that change settings and "settings:<menu>"
+// for buttons that open menus.
+const (
+	settingLanguage        = "language"
+	settingSortOrder       = "sort"
+	settingConfirmClear    = "confirm_clear"
+	settingTimezone        = "timezone"
+	settingAdminsOnly      = "admins_only"
+	settingNaturalLanguage = "natural_language"
+	settingCurrency        = "currency"
+)
+
+// Menus of the settings menu
+const (
+	settingsMenuMain  = "menu"
+	settingsMenuClose = "close"
+)
+
+// settingsValueSeparator separates a setting from its value
+// in callback query data
+const settingsValueSeparator = "="
+
+// settingsTimezones are timezones which chats can choose from
+var settingsTimezones = []string{
+	models.DefaultTimezone,
+	"Europe/London",
+	"Europe/Berlin",
+	"Europe/Kiev",
+	"Europe/Moscow",
+	"America/New_York",
+	"America/Chicago",
+	"America/Los_Angeles",
+	"Asia/Tokyo",
+	"Australia/Sydney",
+}
+
+func init() {
+	registerCommands(botCommand{
+		name:                 commandSettings,
+		description:          "command.settings.description",
+		helpSection:          helpSectionSettings,
+		showInHelpMessage:    true,
+		permission:           permissionAdmin,
+		commandHandler:       handleSettings,
+		callbackQueryHandler: handleSettingsCallbackQuery,
+	})
+}
+
+func handleSettings(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	chatID := message.Chat.ID
+
+	tr, err := userTranslator(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	settings, err := st.GetChatSettings(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get settings of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	text, keyboard := settingsMenu(
+		tr, settings, settingsMenuMain, message.Chat.IsPrivate())
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.BaseChat.ReplyMarkup = keyboard
+	_, err = client.Send(msg)
+	return err
+}
+
+// handleSettingsCallbackQuery changes settings and navigates
+// the settings menu by editing the message with the menu in place
+func handleSettingsCallbackQuery(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	callbackQuery *tgbotapi.CallbackQuery,
+	data string,
+) error {
+	client.AnswerCallbackQuery(tgbotapi.NewCallback(
+		callbackQuery.ID, ""))
+
+	chatID := callbackQuery.Message.Chat.ID
+	messageID := callbackQuery.Message.MessageID
+
+	if data == settingsMenuClose {
+		return hideInlineKeyboard(client, chatID, messageID)
+	}
+
+	settings, err := st.GetChatSettings(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get settings of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	menu := settingsMenuMain
+	setting, value, hasValue := splitSettingsData(data)
+	if hasValue {
+		if err := applySetting(settings, setting, value); err != nil {
+			return err
+		}
+
+		if err := st.UpdateChatSettings(*settings); err != nil {
+			return fmt.Errorf(
+				"Unable to update settings of the chat (ChatID=%d): %v",
+				chatID, err)
+		}
+	} else {
+		switch setting {
+		case settingsMenuMain, settingLanguage, settingTimezone, settingCurrency:
+			menu = setting
+		default:
+			return fmt.Errorf(
+				"Unable to parse a settings menu from the CallbackQuery data %#v",
+				data)
+		}
+	}
+
+	// The chat language could change, so we get a translator
+	// only after the settings are updated
+	tr, err := userTranslator(st, chatID, callbackQuery.From)
+	if err != nil {
+		return err
+	}
+
+	text, keyboard := settingsMenu(
+		tr, settings, menu, callbackQuery.Message.Chat.IsPrivate())
+	msg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	msg.ReplyMarkup = &keyboard
+	_, err = client.Send(msg)
+	if isMessageNotModifiedError(err) {
+		// Nothing has changed. For example, when an user
+		// taps on the same button twice
+		return nil
+	}
+	return err
+}
+
+// applySetting validates a value of a setting and changes settings
+func applySetting(settings *models.ChatSettings, setting, value string) error {
+	switch setting {
+	case settingLanguage:
+		// Empty locale means that the bot speaks languages of users
+		if value != "" && !i18n.IsSupported(value) {
+			return fmt.Errorf("Unsupported locale %#v", value)
+		}
+		settings.Locale = value
+	case settingSortOrder:
+		if !models.IsSortOrder(value) {
+			return fmt.Errorf("Unsupported sort order %#v", value)
+		}
+		settings.SortOrder = value
+	case settingConfirmClear:
+		settings.ConfirmClear = value == "1"
+	case settingAdminsOnly:
+		settings.AdminsOnly = value == "1"
+	case settingNaturalLanguage:
+		settings.NaturalLanguage = value == "1"
+	case settingTimezone:
+		if !isSettingsTimezone(value) {
+			return fmt.Errorf("Unsupported timezone %#v", value)
+		}
+		settings.Timezone = value
+	case settingCurrency:
+		if !money.IsSupported(value) {
+			return fmt.Errorf("Unsupported currency %#v", value)
+		}
+		settings.Currency = value
+	default:
+		return fmt.Errorf("Unsupported setting %#v", setting)
+	}
+	return nil
+}
+
+// settingsMenu returns text and an inline keyboard of a settings menu.
+// Some settings only make sense in private chats
+func settingsMenu(
+	tr *i18n.Translator,
+	settings *models.ChatSettings,
+	menu string,
+	isPrivate bool,
+) (string, tgbotapi.InlineKeyboardMarkup) {
+	var text string
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	switch menu {
+	case settingLanguage:
+		text = tr.T("settings.language.prompt")
+
+		locales := append([]string{""}, i18n.Locales()...)
+		for _, locale := range locales {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				settingsButton(
+					settingsLocaleName(tr, locale),
+					locale == settings.Locale,
+					joinSettingsData(settingLanguage, locale)),
+			))
+		}
+		rows = append(rows, settingsBackButtonRow(tr))
+	case settingTimezone:
+		text = tr.T("settings.timezone.prompt")
+
+		for _, timezone := range settingsTimezones {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				settingsButton(
+					timezone,
+					timezone == settings.Timezone,
+					joinSettingsData(settingTimezone, timezone)),
+			))
+		}
+		rows = append(rows, settingsBackButtonRow(tr))
+	case settingCurrency:
+		text = tr.T("settings.currency.prompt")
+
+		for _, currency := range money.Currencies {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				settingsButton(
+					currency,
+					currency == settings.Currency,
+					joinSettingsData(settingCurrency, currency)),
+			))
+		}
+		rows = append(rows, settingsBackButtonRow(tr))
+	default:
+		text = tr.T("settings.title")
+
+		nextSortOrder := sortOrderAfter(settings.SortOrder)
+
+		confirmClear, nextConfirmClear := settingsToggle(tr, settings.ConfirmClear)
+		adminsOnly, nextAdminsOnly := settingsToggle(tr, settings.AdminsOnly)
+
+		rows = append(rows,
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+				tr.T("settings.language", settingsLocaleName(tr, settings.Locale)),
+				joinCallbackQueryData(commandSettings, settingLanguage))),
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+				tr.T("settings.sort_order", tr.T("settings.sort_order."+settings.SortOrder)),
+				joinSettingsData(settingSortOrder, nextSortOrder))),
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+				tr.T("settings.confirm_clear", confirmClear),
+				joinSettingsData(settingConfirmClear, nextConfirmClear))),
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+				tr.T("settings.admins_only", adminsOnly),
+				joinSettingsData(settingAdminsOnly, nextAdminsOnly))),
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+				tr.T("settings.timezone", settings.Timezone),
+				joinCallbackQueryData(commandSettings, settingTimezone))),
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+				tr.T("settings.currency", settings.Currency),
+				joinCallbackQueryData(commandSettings, settingCurrency))),
+		)
+
+		if isPrivate {
+			naturalLanguage, nextNaturalLanguage := settingsToggle(tr, settings.NaturalLanguage)
+			rows = append(rows,
+				tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+					tr.T("settings.natural_language", naturalLanguage),
+					joinSettingsData(settingNaturalLanguage, nextNaturalLanguage))))
+		}
+
+		rows = append(rows,
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+				tr.T("settings.close"),
+				joinCallbackQueryData(commandSettings, settingsMenuClose))))
+	}
+
+	return text, tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// sortOrderAfter returns an order of items
+// which goes after an order in models.SortOrders
+func sortOrderAfter(sortOrder string) string {
+	for i, o := range models.SortOrders {
+		if o == sortOrder {
+			return models.SortOrders[(i+1)%len(models.SortOrders)]
+		}
+	}
+	return models.SortOrders[0]
+}
+
+// settingsButton returns a button that changes a setting.
+// The button of the current value is marked
+func settingsButton(text string, isCurrent bool, data string) tgbotapi.InlineKeyboardButton {
+	if isCurrent {
+		text = "✓ " + text
+	}
+	return tgbotapi.NewInlineKeyboardButtonData(text, data)
+}
+
+// settingsToggle returns a name of a boolean setting value
+// and callback query data value that toggles it
+func settingsToggle(tr *i18n.Translator, value bool) (name, next string) {
+	if value {
+		return tr.T("settings.on"), "0"
+	}
+	return tr.T("settings.off"), "1"
+}
+
+func settingsBackButtonRow(tr *i18n.Translator) []tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+		tr.T("settings.back"),
+		joinCallbackQueryData(commandSettings, settingsMenuMain)))
+}
+
+// settingsLocaleName returns a name of a chat locale
+func settingsLocaleName(tr *i18n.Translator, locale string) string {
+	if locale == "" {
+		return tr.T("settings.language.users")
+	}
+	return i18n.LocaleName(locale)
+}
+
+func isSettingsTimezone(timezone string) bool {
+	for _, t := range settingsTimezones {
+		if t == timezone {
+			return true
+		}
+	}
+	return false
+}
+
+// joinSettingsData returns callback query data
+// for a button that changes a setting
+func joinSettingsData(setting, value string) string {
+	return joinCallbackQueryData(
+		commandSettings, setting+settingsValueSeparator+value)
+}
+
+// splitSettingsData splits a callback query payload of the settings menu
+// into a setting and its value
+func splitSettingsData(data string) (setting, value string, hasValue bool) {
+	pieces := strings.SplitN(data, settingsValueSeparator, 2)
+	if len(pieces) != 2 {
+		return data, "", false
+	}
+	return pieces[0], pieces[1], true
+}
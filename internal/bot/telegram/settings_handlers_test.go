@@ -0,0 +1,276 @@
+package telegram
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+// keyboardCallbackData returns callback data of all buttons in a keyboard
+func keyboardCallbackData(keyboard tgbotapi.InlineKeyboardMarkup) []string {
+	var data []string
+	for _, row := range keyboard.InlineKeyboard {
+		for _, button := range row {
+			data = append(data, *button.CallbackData)
+		}
+	}
+	return data
+}
+
+func TestHandleSettings(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Common data mocks
+	errMock := errors.New("fake error")
+	messageMock := &tgbotapi.Message{
+		From: &tgbotapi.User{ID: 321},
+		Chat: &tgbotapi.Chat{ID: 123},
+	}
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(messageMock.Chat.ID).Return(nil, errMock)
+
+		err := handleSettings(clientMock, stMock, messageMock)
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(messageMock.Chat.ID).Return(
+			models.NewChatSettings(messageMock.Chat.ID), nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if msgCfg.ChatID != messageMock.Chat.ID {
+				t.Errorf(
+					"Expected to reply to the chat with ID %d, but reply sent to %d",
+					messageMock.Chat.ID, msgCfg.ChatID,
+				)
+			}
+
+			keyboard, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+			if !ok {
+				t.Fatalf("Expected inline keyboard, got %#v", msgCfg.ReplyMarkup)
+			}
+
+			expectedData := []string{
+				"settings:language",
+				"settings:sort=name",
+				"settings:confirm_clear=0",
+				"settings:admins_only=1",
+				"settings:timezone",
+				"settings:currency",
+				"settings:close",
+			}
+			data := keyboardCallbackData(keyboard)
+			if strings.Join(data, " ") != strings.Join(expectedData, " ") {
+				t.Errorf("Expected callback data %v, got %v", expectedData, data)
+			}
+		})
+
+		err := handleSettings(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
+
+func TestSettingsMenuPrivateChat(t *testing.T) {
+	tr := i18n.NewTranslator("en")
+	settings := models.NewChatSettings(123)
+	toggleData := "settings:natural_language=0"
+
+	_, keyboard := settingsMenu(tr, settings, settingsMenuMain, true)
+	if data := keyboardCallbackData(keyboard); !strings.Contains(strings.Join(data, " "), toggleData) {
+		t.Errorf("Expected callback data %#v in private chats, got %v", toggleData, data)
+	}
+
+	_, keyboard = settingsMenu(tr, settings, settingsMenuMain, false)
+	if data := keyboardCallbackData(keyboard); strings.Contains(strings.Join(data, " "), toggleData) {
+		t.Errorf("Unexpected callback data %#v in groups, got %v", toggleData, data)
+	}
+}
+
+func TestHandleSettingsCallbackQuery(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	// Users with a chosen locale don't need chat settings for translations
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+	clientMock.EXPECT().AnswerCallbackQuery(gomock.Any()).AnyTimes()
+
+	// Common data mocks
+	errMock := errors.New("fake error")
+	callbackQueryMock := &tgbotapi.CallbackQuery{
+		ID:   "some-callback-id",
+		From: &tgbotapi.User{ID: 321},
+		Message: &tgbotapi.Message{
+			MessageID: 456,
+			Chat:      &tgbotapi.Chat{ID: 123},
+		},
+	}
+	chatID := callbackQueryMock.Message.Chat.ID
+
+	// expectMenu checks that the menu is edited in place
+	expectMenu := func(t *testing.T, expectedData ...string) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.EditMessageTextConfig) {
+			if msgCfg.ChatID != chatID || msgCfg.MessageID != callbackQueryMock.Message.MessageID {
+				t.Errorf("Expected to edit the message %d in the chat %d, got %d in %d",
+					callbackQueryMock.Message.MessageID, chatID,
+					msgCfg.MessageID, msgCfg.ChatID)
+			}
+
+			if msgCfg.ReplyMarkup == nil {
+				t.Fatal("Expected inline keyboard")
+			}
+
+			data := keyboardCallbackData(*msgCfg.ReplyMarkup)
+			for _, expected := range expectedData {
+				if !strings.Contains(strings.Join(data, " "), expected) {
+					t.Errorf("Expected callback data %#v, got %v", expected, data)
+				}
+			}
+		})
+	}
+
+	t.Run("Open a menu", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+		expectMenu(t, "settings:timezone=Europe/Kiev", "settings:menu")
+
+		err := handleSettingsCallbackQuery(clientMock, stMock, callbackQueryMock, "timezone")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Change a setting", func(t *testing.T) {
+		testCases := []struct {
+			data     string
+			expected func(settings *models.ChatSettings)
+		}{
+			{
+				data:     "sort=name",
+				expected: func(s *models.ChatSettings) { s.SortOrder = models.SortOrderName },
+			},
+			{
+				data:     "confirm_clear=0",
+				expected: func(s *models.ChatSettings) { s.ConfirmClear = false },
+			},
+			{
+				data:     "admins_only=1",
+				expected: func(s *models.ChatSettings) { s.AdminsOnly = true },
+			},
+			{
+				data:     "natural_language=0",
+				expected: func(s *models.ChatSettings) { s.NaturalLanguage = false },
+			},
+			{
+				data:     "language=uk",
+				expected: func(s *models.ChatSettings) { s.Locale = "uk" },
+			},
+			{
+				data:     "timezone=Europe/Kiev",
+				expected: func(s *models.ChatSettings) { s.Timezone = "Europe/Kiev" },
+			},
+			{
+				data:     "currency=UAH",
+				expected: func(s *models.ChatSettings) { s.Currency = "UAH" },
+			},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.data, func(t *testing.T) {
+				expectedSettings := models.NewChatSettings(chatID)
+				testCase.expected(expectedSettings)
+
+				stMock.EXPECT().GetChatSettings(chatID).Return(
+					models.NewChatSettings(chatID), nil)
+				stMock.EXPECT().UpdateChatSettings(*expectedSettings).Return(nil)
+				expectMenu(t, "settings:close")
+
+				err := handleSettingsCallbackQuery(
+					clientMock, stMock, callbackQueryMock, testCase.data)
+				if err != nil {
+					t.Errorf("Unexpected err: got %#v", err)
+				}
+			})
+		}
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		for _, data := range []string{"unknown", "sort=random", "language=xx", "timezone=Mars/Olympus", "currency=XYZ", "unknown=1"} {
+			t.Run(data, func(t *testing.T) {
+				stMock.EXPECT().GetChatSettings(chatID).Return(
+					models.NewChatSettings(chatID), nil)
+
+				err := handleSettingsCallbackQuery(clientMock, stMock, callbackQueryMock, data)
+				if err == nil {
+					t.Error("Expected an error")
+				}
+			})
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+		stMock.EXPECT().UpdateChatSettings(gomock.Any()).Return(errMock)
+
+		err := handleSettingsCallbackQuery(clientMock, stMock, callbackQueryMock, "sort=name")
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(
+			generateSendHideKeybaordCallChecker(t, callbackQueryMock),
+		)
+
+		err := handleSettingsCallbackQuery(clientMock, stMock, callbackQueryMock, "close")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Not modified", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+		clientMock.EXPECT().Send(gomock.Any()).Return(tgbotapi.Message{}, tgbotapi.Error{
+			Message: "Bad Request: message is not modified",
+		})
+
+		err := handleSettingsCallbackQuery(clientMock, stMock, callbackQueryMock, "menu")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
+
+func TestSettingsMenuTranslations(t *testing.T) {
+	settings := models.NewChatSettings(123)
+
+	for _, locale := range i18n.Locales() {
+		tr := i18n.NewTranslator(locale)
+
+		text, _ := settingsMenu(tr, settings, settingsMenuMain, false)
+		if text != tr.T("settings.title") {
+			t.Errorf("%s: expected the settings title, got %#v", locale, text)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// Actions of the sort menu.
+//
+// Callback query data of the menu is "sort:<action>=<value>"
+// for buttons that change the order and "sort:<action>"
+// for buttons that open menus.
+const (
+	sortActionOrder = "order"
+	sortActionMove  = "move"
+	sortActionUp    = "up"
+	sortActionDown  = "down"
+	sortActionClose = "close"
+)
+
+const sortValueSeparator = "="
+
+func init() {
+	registerCommands(botCommand{
+		name:                 commandSort,
+		description:          "command.sort.description",
+		args:                 []commandArg{{name: "order", optional: true}},
+		helpSection:          helpSectionShoppingList,
+		showInHelpMessage:    true,
+		permission:           permissionAdmin,
+		commandHandler:       handleSort,
+		callbackQueryHandler: handleSortCallbackQuery,
+	})
+}
+
+// handleSort changes the order of items in the shopping list.
+// Allow to skip the keyboard: `/sort name`
+func handleSort(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	chatID := message.Chat.ID
+
+	tr, err := userTranslator(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	settings, err := st.GetChatSettings(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get settings of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	sortOrder := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+	if sortOrder == "" {
+		text, keyboard := sortMenu(tr, settings)
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.BaseChat.ReplyMarkup = keyboard
+		_, err = client.Send(msg)
+		return err
+	}
+
+	if !models.IsSortOrder(sortOrder) {
+		text := tr.T("sort.unknown", sortOrder, strings.Join(models.SortOrders, ", "))
+		msg := tgbotapi.NewMessage(chatID, text)
+		_, err = client.Send(msg)
+		return err
+	}
+
+	settings.SortOrder = sortOrder
+	if err := st.UpdateChatSettings(*settings); err != nil {
+		return fmt.Errorf(
+			"Unable to update settings of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	text := tr.T("sort.done", tr.T("settings.sort_order."+sortOrder))
+	msg := tgbotapi.NewMessage(chatID, text)
+	_, err = client.Send(msg)
+	return err
+}
+
+// handleSortCallbackQuery changes the order of items and moves items
+// by editing the message with the menu in place
+func handleSortCallbackQuery(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	callbackQuery *tgbotapi.CallbackQuery,
+	data string,
+) error {
+	client.AnswerCallbackQuery(tgbotapi.NewCallback(
+		callbackQuery.ID, ""))
+
+	chatID := callbackQuery.Message.Chat.ID
+	messageID := callbackQuery.Message.MessageID
+
+	action, value := data, ""
+	if pieces := strings.SplitN(data, sortValueSeparator, 2); len(pieces) == 2 {
+		action, value = pieces[0], pieces[1]
+	}
+
+	if action == sortActionClose {
+		return hideInlineKeyboard(client, chatID, messageID)
+	}
+
+	tr, err := userTranslator(st, chatID, callbackQuery.From)
+	if err != nil {
+		return err
+	}
+
+	settings, err := st.GetChatSettings(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get settings of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	var text string
+	var keyboard tgbotapi.InlineKeyboardMarkup
+	switch action {
+	case sortActionOrder:
+		if !models.IsSortOrder(value) {
+			return fmt.Errorf(
+				"Unable to parse a sort order from the CallbackQuery data %#v",
+				data)
+		}
+
+		settings.SortOrder = value
+		if err := st.UpdateChatSettings(*settings); err != nil {
+			return fmt.Errorf(
+				"Unable to update settings of the chat (ChatID=%d): %v",
+				chatID, err)
+		}
+
+		text, keyboard = sortMenu(tr, settings)
+	case sortActionMove:
+		chatItems, err := st.GetShoppingItems(chatID)
+		if err != nil {
+			return fmt.Errorf(
+				"Unable to get all shopping items (ChatID=%d): %v",
+				chatID, err)
+		}
+
+		text, keyboard = sortMoveMenu(tr, chatItems)
+	case sortActionUp, sortActionDown:
+		itemID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			// User can't amend CallBackData, so most likely it's our fault
+			return fmt.Errorf(
+				"Unable to parse ItemID from the CallbackQuery data %s: %v",
+				data, err)
+		}
+
+		chatItems, err := moveShoppingItem(st, settings, itemID, action == sortActionUp)
+		if err != nil {
+			return err
+		}
+
+		text, keyboard = sortMoveMenu(tr, chatItems)
+	default:
+		return fmt.Errorf(
+			"Unable to parse an action from the CallbackQuery data %#v",
+			data)
+	}
+
+	msg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	msg.ReplyMarkup = &keyboard
+	_, err = client.Send(msg)
+	if isMessageNotModifiedError(err) {
+		// Nothing has changed. For example, when an user
+		// moves the first item up
+		return nil
+	}
+	return err
+}
+
+// moveShoppingItem swaps an item with its neighbour and
+// switches the chat to the manual order of items.
+//
+// Users move items in the order which they see, so we save
+// the current order of all items: it becomes the manual order
+func moveShoppingItem(
+	st storage.DataStorageInterface,
+	settings *models.ChatSettings,
+	itemID int64,
+	up bool,
+) ([]*models.ShoppingItem, error) {
+	chatID := settings.ChatID
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	i := -1
+	for index, item := range chatItems {
+		if item.ID == itemID {
+			i = index
+		}
+	}
+
+	j := i + 1
+	if up {
+		j = i - 1
+	}
+	if i < 0 || j < 0 || j >= len(chatItems) {
+		// The item was removed or it can't move any further
+		return chatItems, nil
+	}
+	chatItems[i], chatItems[j] = chatItems[j], chatItems[i]
+
+	var itemIDs []int64
+	for _, item := range chatItems {
+		itemIDs = append(itemIDs, item.ID)
+	}
+	if err := st.ReorderShoppingItems(chatID, itemIDs); err != nil {
+		return nil, fmt.Errorf(
+			"Unable to reorder shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	if settings.SortOrder != models.SortOrderManual {
+		settings.SortOrder = models.SortOrderManual
+		if err := st.UpdateChatSettings(*settings); err != nil {
+			return nil, fmt.Errorf(
+				"Unable to update settings of the chat (ChatID=%d): %v",
+				chatID, err)
+		}
+	}
+	return chatItems, nil
+}
+
+// sortMenu returns text and an inline keyboard to choose an order of items
+func sortMenu(
+	tr *i18n.Translator,
+	settings *models.ChatSettings,
+) (string, tgbotapi.InlineKeyboardMarkup) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, sortOrder := range models.SortOrders {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			settingsButton(
+				tr.T("settings.sort_order."+sortOrder),
+				sortOrder == settings.SortOrder,
+				joinSortData(sortActionOrder, sortOrder)),
+		))
+	}
+
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			tr.T("sort.move"), joinCallbackQueryData(commandSort, sortActionMove))),
+		sortCloseButtonRow(tr),
+	)
+	return tr.T("sort.prompt"), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// sortMoveMenu returns text and an inline keyboard to move items
+func sortMoveMenu(
+	tr *i18n.Translator,
+	items []*models.ShoppingItem,
+) (string, tgbotapi.InlineKeyboardMarkup) {
+	if len(items) == 0 {
+		return tr.T("list.empty"), tgbotapi.NewInlineKeyboardMarkup(
+			sortCloseButtonRow(tr))
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, item := range items {
+		itemID := strconv.FormatInt(item.ID, 10)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			// Tapping on a name does nothing
+			tgbotapi.NewInlineKeyboardButtonData(
+				item.Name, joinCallbackQueryData(commandSort, sortActionMove)),
+			tgbotapi.NewInlineKeyboardButtonData(
+				"⬆", joinSortData(sortActionUp, itemID)),
+			tgbotapi.NewInlineKeyboardButtonData(
+				"⬇", joinSortData(sortActionDown, itemID)),
+		))
+	}
+	rows = append(rows, sortCloseButtonRow(tr))
+
+	return tr.T("sort.move.prompt"), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func sortCloseButtonRow(tr *i18n.Translator) []tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+		tr.T("settings.close"),
+		joinCallbackQueryData(commandSort, sortActionClose)))
+}
+
+// joinSortData returns callback query data for a button of the sort menu
+func joinSortData(action, value string) string {
+	return joinCallbackQueryData(
+		commandSort, action+sortValueSeparator+value)
+}
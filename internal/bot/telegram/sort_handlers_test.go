@@ -0,0 +1,256 @@
+package telegram
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+func TestHandleSort(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Common data mocks
+	chatID := int64(123)
+	newMessageMock := func(args string) *tgbotapi.Message {
+		messageMock := mock_telegram.MessageCommandMockSetup(commandSort, args)
+		messageMock.Chat = &tgbotapi.Chat{ID: chatID}
+		messageMock.From = &tgbotapi.User{ID: 321}
+		return messageMock
+	}
+
+	t.Run("Without arguments", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			keyboard, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+			if !ok {
+				t.Fatalf("Expected an inline keyboard, got %#v", msgCfg.ReplyMarkup)
+			}
+
+			data := strings.Join(keyboardCallbackData(keyboard), " ")
+			for _, expected := range []string{"sort:order=name", "sort:order=manual", "sort:move", "sort:close"} {
+				if !strings.Contains(data, expected) {
+					t.Errorf("Expected callback data %#v, got %v", expected, data)
+				}
+			}
+
+			if keyboard.InlineKeyboard[0][0].Text != "✓ by date added" {
+				t.Errorf("Expected the current order to be marked, got %#v",
+					keyboard.InlineKeyboard[0][0].Text)
+			}
+		})
+
+		err := handleSort(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Order in arguments", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+		stMock.EXPECT().UpdateChatSettings(gomock.Any()).Do(func(settings models.ChatSettings) {
+			if settings.SortOrder != models.SortOrderAuthor {
+				t.Errorf("Expected the %#v order, got %#v",
+					models.SortOrderAuthor, settings.SortOrder)
+			}
+		}).Return(nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			expectedText := "sort items by who added"
+			if !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+
+		err := handleSort(clientMock, stMock, newMessageMock(" Author"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Unknown order in arguments", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			expectedText := `can't sort items "price"`
+			if !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+
+		err := handleSort(clientMock, stMock, newMessageMock("price"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
+
+func TestHandleSortCallbackQuery(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+	clientMock.EXPECT().AnswerCallbackQuery(gomock.Any()).AnyTimes()
+
+	// Common data mocks
+	errMock := errors.New("fake error")
+	callbackQueryMock := &tgbotapi.CallbackQuery{
+		ID:   "some-callback-id",
+		From: &tgbotapi.User{ID: 321},
+		Message: &tgbotapi.Message{
+			MessageID: 456,
+			Chat:      &tgbotapi.Chat{ID: 123},
+		},
+	}
+	chatID := callbackQueryMock.Message.Chat.ID
+	newItemsMock := func() []*models.ShoppingItem {
+		return []*models.ShoppingItem{
+			{ID: 1, Name: "Milk"},
+			{ID: 2, Name: "Bread"},
+			{ID: 3, Name: "Eggs"},
+		}
+	}
+
+	// expectMenu checks that the menu is edited in place
+	expectMenu := func(t *testing.T, expectedData ...string) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.EditMessageTextConfig) {
+			if msgCfg.ChatID != chatID || msgCfg.MessageID != callbackQueryMock.Message.MessageID {
+				t.Errorf("Expected to edit the message %d in the chat %d, got %d in %d",
+					callbackQueryMock.Message.MessageID, chatID,
+					msgCfg.MessageID, msgCfg.ChatID)
+			}
+
+			if msgCfg.ReplyMarkup == nil {
+				t.Fatal("Expected inline keyboard")
+			}
+
+			data := keyboardCallbackData(*msgCfg.ReplyMarkup)
+			if !reflect.DeepEqual(data, expectedData) {
+				t.Errorf("Expected callback data %v, got %v", expectedData, data)
+			}
+		})
+	}
+
+	t.Run("Change the order", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+		stMock.EXPECT().UpdateChatSettings(gomock.Any()).Do(func(settings models.ChatSettings) {
+			if settings.SortOrder != models.SortOrderName {
+				t.Errorf("Expected the %#v order, got %#v",
+					models.SortOrderName, settings.SortOrder)
+			}
+		}).Return(nil)
+		expectMenu(t,
+			"sort:order=added", "sort:order=name", "sort:order=category",
+			"sort:order=author", "sort:order=manual", "sort:move", "sort:close")
+
+		err := handleSortCallbackQuery(clientMock, stMock, callbackQueryMock, "order=name")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Open the move menu", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+		stMock.EXPECT().GetShoppingItems(chatID).Return(newItemsMock(), nil)
+		expectMenu(t,
+			"sort:move", "sort:up=1", "sort:down=1",
+			"sort:move", "sort:up=2", "sort:down=2",
+			"sort:move", "sort:up=3", "sort:down=3",
+			"sort:close")
+
+		err := handleSortCallbackQuery(clientMock, stMock, callbackQueryMock, "move")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Move an item", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+		stMock.EXPECT().GetShoppingItems(chatID).Return(newItemsMock(), nil)
+		stMock.EXPECT().ReorderShoppingItems(chatID, []int64{1, 3, 2}).Return(nil)
+		stMock.EXPECT().UpdateChatSettings(gomock.Any()).Do(func(settings models.ChatSettings) {
+			if settings.SortOrder != models.SortOrderManual {
+				t.Errorf("Expected the %#v order, got %#v",
+					models.SortOrderManual, settings.SortOrder)
+			}
+		}).Return(nil)
+		expectMenu(t,
+			"sort:move", "sort:up=1", "sort:down=1",
+			"sort:move", "sort:up=3", "sort:down=3",
+			"sort:move", "sort:up=2", "sort:down=2",
+			"sort:close")
+
+		err := handleSortCallbackQuery(clientMock, stMock, callbackQueryMock, "up=3")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Move the last item down", func(t *testing.T) {
+		settingsMock := models.NewChatSettings(chatID)
+		settingsMock.SortOrder = models.SortOrderManual
+		stMock.EXPECT().GetChatSettings(chatID).Return(settingsMock, nil)
+		stMock.EXPECT().GetShoppingItems(chatID).Return(newItemsMock(), nil)
+		clientMock.EXPECT().Send(gomock.Any()).Return(
+			tgbotapi.Message{}, tgbotapi.Error{Message: "Bad Request: message is not modified"})
+
+		err := handleSortCallbackQuery(clientMock, stMock, callbackQueryMock, "down=3")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Close the menu", func(t *testing.T) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(
+			generateSendHideKeybaordCallChecker(t, callbackQueryMock))
+
+		err := handleSortCallbackQuery(clientMock, stMock, callbackQueryMock, "close")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		for _, data := range []string{"unknown", "order=random", "up=not int"} {
+			stMock.EXPECT().GetChatSettings(chatID).Return(
+				models.NewChatSettings(chatID), nil)
+
+			err := handleSortCallbackQuery(clientMock, stMock, callbackQueryMock, data)
+			if err == nil {
+				t.Errorf("Expected an error for %#v", data)
+			}
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().GetChatSettings(chatID).Return(
+			models.NewChatSettings(chatID), nil)
+		stMock.EXPECT().GetShoppingItems(chatID).Return(newItemsMock(), nil)
+		stMock.EXPECT().ReorderShoppingItems(chatID, gomock.Any()).Return(errMock)
+
+		err := handleSortCallbackQuery(clientMock, stMock, callbackQueryMock, "down=1")
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+}
@@ -0,0 +1,342 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/logging"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// Actions of the staples menu.
+//
+// Callback query data of the menu is "staples:del=<staple id>"
+// for buttons that delete staples and "staples:close"
+// for the button that closes the menu.
+const (
+	staplesActionDelete = "del"
+	staplesActionClose  = "close"
+)
+
+const staplesValueSeparator = "="
+
+// Supported intervals of staples in days
+const (
+	staplesIntervalDaily   = 1
+	staplesIntervalWeekly  = 7
+	staplesIntervalMaxDays = 365
+)
+
+func init() {
+	registerCommands(botCommand{
+		name:        commandStaples,
+		description: "command.staples.description",
+		args: []commandArg{
+			{name: "item", optional: true},
+			{name: "schedule", optional: true},
+		},
+		helpSection:          helpSectionShoppingList,
+		showInHelpMessage:    true,
+		permission:           permissionAdmin,
+		commandHandler:       handleStaples,
+		callbackQueryHandler: handleStaplesCallbackQuery,
+	})
+}
+
+// handleStaples shows staples of the chat or adds a new staple.
+// The last word is a schedule, so names of items can have several words:
+// `/staples oat milk weekly`, `/staples bread 3`
+func handleStaples(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	chatID := message.Chat.ID
+
+	tr, err := userTranslator(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	words := strings.Fields(message.CommandArguments())
+	if len(words) == 0 {
+		staples, err := st.GetStaples(chatID)
+		if err != nil {
+			return fmt.Errorf(
+				"Unable to get staples of the chat (ChatID=%d): %v",
+				chatID, err)
+		}
+
+		text, keyboard := staplesMenu(tr, staples)
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.BaseChat.ReplyMarkup = keyboard
+		_, err = client.Send(msg)
+		return err
+	}
+
+	intervalDays := staplesIntervalWeekly
+	if len(words) > 1 {
+		if days, ok := parseStapleInterval(tr, words[len(words)-1]); ok {
+			intervalDays = days
+			words = words[:len(words)-1]
+		}
+	}
+
+	staple := models.Staple{
+		ChatID:       chatID,
+		Name:         strings.Join(words, " "),
+		IntervalDays: intervalDays,
+		NextAt:       timeNow().AddDate(0, 0, intervalDays),
+		CreatedBy:    message.From.ID,
+	}
+	if err := st.AddStaple(staple); err != nil {
+		return fmt.Errorf(
+			"Unable to add a staple (ChatID=%d, UserId=%d): %v",
+			chatID, message.From.ID, err)
+	}
+
+	text := tr.T("staples.done", staple.Name, formatStapleInterval(tr, intervalDays))
+	msg := tgbotapi.NewMessage(chatID, text)
+	_, err = client.Send(msg)
+	return err
+}
+
+// handleStaplesCallbackQuery deletes staples
+// by editing the message with the menu in place
+func handleStaplesCallbackQuery(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	callbackQuery *tgbotapi.CallbackQuery,
+	data string,
+) error {
+	client.AnswerCallbackQuery(tgbotapi.NewCallback(
+		callbackQuery.ID, ""))
+
+	chatID := callbackQuery.Message.Chat.ID
+	messageID := callbackQuery.Message.MessageID
+
+	if data == staplesActionClose {
+		return hideInlineKeyboard(client, chatID, messageID)
+	}
+
+	pieces := strings.SplitN(data, staplesValueSeparator, 2)
+	if len(pieces) != 2 || pieces[0] != staplesActionDelete {
+		return fmt.Errorf(
+			"Unable to parse an action from the CallbackQuery data %#v",
+			data)
+	}
+
+	stapleID, err := strconv.ParseInt(pieces[1], 10, 64)
+	if err != nil {
+		// User can't amend CallBackData, so most likely it's our fault
+		return fmt.Errorf(
+			"Unable to parse StapleID from the CallbackQuery data %s: %v",
+			data, err)
+	}
+
+	tr, err := userTranslator(st, chatID, callbackQuery.From)
+	if err != nil {
+		return err
+	}
+
+	staple, err := st.GetStaple(stapleID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get a staple (StapleID=%d): %v",
+			stapleID, err)
+	}
+
+	// Someone else could delete the staple using
+	// another message with the menu
+	if staple != nil && staple.ChatID == chatID {
+		if err := st.DeleteStaple(stapleID); err != nil {
+			return fmt.Errorf(
+				"Unable to delete a staple (StapleID=%d): %v",
+				stapleID, err)
+		}
+	}
+
+	staples, err := st.GetStaples(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get staples of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	text, keyboard := staplesMenu(tr, staples)
+	msg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	msg.ReplyMarkup = &keyboard
+	_, err = client.Send(msg)
+	if isMessageNotModifiedError(err) {
+		return nil
+	}
+	return err
+}
+
+// addDueStaples adds due staples into shopping lists of all chats
+// and lets chats know about it with one message per chat
+func addDueStaples(
+	client sender,
+	st storage.DataStorageInterface,
+	logger *logging.Logger,
+	now time.Time,
+) {
+	staples, err := st.GetDueStaples(now)
+	if err != nil {
+		logger.Error("Unable to get due staples", "err", err)
+		return
+	}
+
+	// Staples are sorted by chats
+	for len(staples) > 0 {
+		chatID := staples[0].ChatID
+		n := 1
+		for n < len(staples) && staples[n].ChatID == chatID {
+			n++
+		}
+
+		chatLogger := logger.With("chat_id", chatID)
+		if err := addChatStaples(client, st, chatLogger, staples[:n], now); err != nil {
+			chatLogger.Error("Unable to add staples", "err", err)
+		}
+		staples = staples[n:]
+	}
+}
+
+// addChatStaples adds due staples into a shopping list of a chat.
+// Staples which are already in the list are skipped:
+// nobody needs the second bottle of milk just because it's Monday
+func addChatStaples(
+	client sender,
+	st storage.DataStorageInterface,
+	logger *logging.Logger,
+	staples []*models.Staple,
+	now time.Time,
+) error {
+	chatID := staples[0].ChatID
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	var addedNames []string
+	for _, staple := range staples {
+		if findDuplicateItem(chatItems, staple.Name) == nil {
+			item, err := newShoppingItem(st, chatID, staple.Name, staple.CreatedBy)
+			if err != nil {
+				return err
+			}
+
+			if err := st.AddShoppingItemIntoShoppingList(item); err != nil {
+				return fmt.Errorf(
+					"Unable to add a staple into the shopping list (StapleID=%d, ChatID=%d): %v",
+					staple.ID, chatID, err)
+			}
+
+			chatItems = append(chatItems, &item)
+			addedNames = append(addedNames, staple.Name)
+		}
+
+		nextAt := nextStapleTime(staple, now)
+		if err := st.UpdateStapleNextAt(staple.ID, nextAt); err != nil {
+			return fmt.Errorf(
+				"Unable to schedule a staple (StapleID=%d): %v",
+				staple.ID, err)
+		}
+	}
+
+	if len(addedNames) == 0 {
+		return nil
+	}
+
+	// The translator is usable even if there is an error
+	tr, err := chatTranslator(st, chatID)
+	if err != nil {
+		logger.Error("Unable to choose a language of the chat", "err", err)
+	}
+
+	text := tr.N("staples.added", len(addedNames), len(addedNames),
+		strings.Join(addedNames, ", "))
+	msg := tgbotapi.NewMessage(chatID, text)
+	if _, err := client.Send(msg); err != nil {
+		// Items are in the list already, so we only
+		// need to stop messaging chats which blocked the bot
+		handleAPIError(st, logger, chatID, err)
+	}
+	return nil
+}
+
+// nextStapleTime returns when a staple must be added next time.
+// It skips runs which the bot missed, for example, while it was down
+func nextStapleTime(staple *models.Staple, now time.Time) time.Time {
+	nextAt := staple.NextAt
+	for !nextAt.After(now) {
+		nextAt = nextAt.AddDate(0, 0, staple.IntervalDays)
+	}
+	return nextAt
+}
+
+// parseStapleInterval returns an interval in days from a schedule
+// like "daily", "weekly" or a number of days
+func parseStapleInterval(tr *i18n.Translator, text string) (int, bool) {
+	switch {
+	case strings.EqualFold(text, "daily"),
+		strings.EqualFold(text, tr.T("staples.schedule.daily.arg")):
+		return staplesIntervalDaily, true
+	case strings.EqualFold(text, "weekly"),
+		strings.EqualFold(text, tr.T("staples.schedule.weekly.arg")):
+		return staplesIntervalWeekly, true
+	}
+
+	days, err := strconv.Atoi(text)
+	if err != nil || days < 1 || days > staplesIntervalMaxDays {
+		return 0, false
+	}
+	return days, true
+}
+
+// formatStapleInterval returns a human readable interval of a staple
+func formatStapleInterval(tr *i18n.Translator, intervalDays int) string {
+	switch intervalDays {
+	case staplesIntervalDaily:
+		return tr.T("staples.schedule.daily")
+	case staplesIntervalWeekly:
+		return tr.T("staples.schedule.weekly")
+	}
+	return tr.N("staples.schedule.days", intervalDays, intervalDays)
+}
+
+// staplesMenu returns text and an inline keyboard to delete staples
+func staplesMenu(
+	tr *i18n.Translator,
+	staples []*models.Staple,
+) (string, tgbotapi.InlineKeyboardMarkup) {
+	closeRow := tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+		tr.T("settings.close"),
+		joinCallbackQueryData(commandStaples, staplesActionClose)))
+
+	if len(staples) == 0 {
+		return tr.T("staples.empty"), tgbotapi.NewInlineKeyboardMarkup(closeRow)
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, staple := range staples {
+		text := tr.T("staples.button",
+			staple.Name, formatStapleInterval(tr, staple.IntervalDays))
+		data := joinCallbackQueryData(commandStaples,
+			staplesActionDelete+staplesValueSeparator+strconv.FormatInt(staple.ID, 10))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(text, data)))
+	}
+	rows = append(rows, closeRow)
+
+	return tr.T("staples.prompt"), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
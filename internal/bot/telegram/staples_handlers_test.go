@@ -0,0 +1,310 @@
+package telegram
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+func TestHandleStaples(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Mock: timeNow
+	nowMock := time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+	oldTimeNow := timeNow
+	defer func() { timeNow = oldTimeNow }()
+	timeNow = func() time.Time { return nowMock }
+
+	// Common data mocks
+	errMock := errors.New("fake error")
+	chatID := int64(123)
+	newMessageMock := func(args string) *tgbotapi.Message {
+		messageMock := mock_telegram.MessageCommandMockSetup(commandStaples, args)
+		messageMock.Chat = &tgbotapi.Chat{ID: chatID}
+		messageMock.From = &tgbotapi.User{ID: 321}
+		return messageMock
+	}
+
+	expectText := func(t *testing.T, expectedText string) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+	}
+
+	t.Run("Without arguments", func(t *testing.T) {
+		stMock.EXPECT().GetStaples(chatID).Return([]*models.Staple{
+			{ID: 1, Name: "Milk", IntervalDays: 7},
+			{ID: 2, Name: "Bread", IntervalDays: 3},
+		}, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			keyboard, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+			if !ok {
+				t.Fatalf("Expected an inline keyboard, got %#v", msgCfg.ReplyMarkup)
+			}
+
+			data := keyboardCallbackData(keyboard)
+			expectedData := []string{"staples:del=1", "staples:del=2", "staples:close"}
+			if !reflect.DeepEqual(data, expectedData) {
+				t.Errorf("Expected callback data %v, got %v", expectedData, data)
+			}
+
+			expectedText := "✕ Bread, every 3 days"
+			if keyboard.InlineKeyboard[1][0].Text != expectedText {
+				t.Errorf("Expected button %#v, got %#v",
+					expectedText, keyboard.InlineKeyboard[1][0].Text)
+			}
+		})
+
+		err := handleStaples(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Add with the default schedule", func(t *testing.T) {
+		stMock.EXPECT().AddStaple(models.Staple{
+			ChatID:       chatID,
+			Name:         "Oat milk",
+			IntervalDays: 7,
+			NextAt:       nowMock.AddDate(0, 0, 7),
+			CreatedBy:    321,
+		}).Return(nil)
+		expectText(t, `I'll add "Oat milk" into your shopping list every week`)
+
+		err := handleStaples(clientMock, stMock, newMessageMock("Oat milk"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Add with a schedule", func(t *testing.T) {
+		stMock.EXPECT().AddStaple(models.Staple{
+			ChatID:       chatID,
+			Name:         "Bread",
+			IntervalDays: 3,
+			NextAt:       nowMock.AddDate(0, 0, 3),
+			CreatedBy:    321,
+		}).Return(nil)
+		expectText(t, "every 3 days")
+
+		err := handleStaples(clientMock, stMock, newMessageMock("Bread 3"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().AddStaple(gomock.Any()).Return(errMock)
+
+		err := handleStaples(clientMock, stMock, newMessageMock("Milk daily"))
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+}
+
+func TestHandleStaplesCallbackQuery(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+	clientMock.EXPECT().AnswerCallbackQuery(gomock.Any()).AnyTimes()
+
+	// Common data mocks
+	callbackQueryMock := &tgbotapi.CallbackQuery{
+		ID:   "some-callback-id",
+		From: &tgbotapi.User{ID: 321},
+		Message: &tgbotapi.Message{
+			MessageID: 456,
+			Chat:      &tgbotapi.Chat{ID: 123},
+		},
+	}
+	chatID := callbackQueryMock.Message.Chat.ID
+
+	t.Run("Delete a staple", func(t *testing.T) {
+		stMock.EXPECT().GetStaple(int64(1)).Return(
+			&models.Staple{ID: 1, ChatID: chatID, Name: "Milk"}, nil)
+		stMock.EXPECT().DeleteStaple(int64(1)).Return(nil)
+		stMock.EXPECT().GetStaples(chatID).Return([]*models.Staple{
+			{ID: 2, ChatID: chatID, Name: "Bread", IntervalDays: 1},
+		}, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.EditMessageTextConfig) {
+			if msgCfg.MessageID != callbackQueryMock.Message.MessageID {
+				t.Errorf("Expected to edit the message %d, got %d",
+					callbackQueryMock.Message.MessageID, msgCfg.MessageID)
+			}
+
+			data := keyboardCallbackData(*msgCfg.ReplyMarkup)
+			expectedData := []string{"staples:del=2", "staples:close"}
+			if !reflect.DeepEqual(data, expectedData) {
+				t.Errorf("Expected callback data %v, got %v", expectedData, data)
+			}
+		})
+
+		err := handleStaplesCallbackQuery(clientMock, stMock, callbackQueryMock, "del=1")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Staple of another chat", func(t *testing.T) {
+		stMock.EXPECT().GetStaple(int64(1)).Return(
+			&models.Staple{ID: 1, ChatID: 999, Name: "Milk"}, nil)
+		stMock.EXPECT().GetStaples(chatID).Return(nil, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.EditMessageTextConfig) {
+			expectedText := "You don't have staples yet"
+			if !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+
+		err := handleStaplesCallbackQuery(clientMock, stMock, callbackQueryMock, "del=1")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Close the menu", func(t *testing.T) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(
+			generateSendHideKeybaordCallChecker(t, callbackQueryMock))
+
+		err := handleStaplesCallbackQuery(clientMock, stMock, callbackQueryMock, "close")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		for _, data := range []string{"unknown", "del=not int", "add=1"} {
+			err := handleStaplesCallbackQuery(clientMock, stMock, callbackQueryMock, data)
+			if err == nil {
+				t.Errorf("Expected an error for %#v", data)
+			}
+		}
+	})
+}
+
+func TestAddDueStaples(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetCategoryKeywords(gomock.Any()).Return(nil, nil).AnyTimes()
+
+	// Common data mocks
+	nowMock := time.Date(2018, 10, 8, 12, 0, 0, 0, time.UTC)
+	lastWeek := nowMock.AddDate(0, 0, -7)
+
+	t.Run("Success", func(t *testing.T) {
+		stMock.EXPECT().GetDueStaples(nowMock).Return([]*models.Staple{
+			{ID: 1, ChatID: 1, Name: "Milk", IntervalDays: 7, NextAt: lastWeek, CreatedBy: 11},
+			{ID: 2, ChatID: 1, Name: "Bread", IntervalDays: 7, NextAt: nowMock, CreatedBy: 11},
+			{ID: 3, ChatID: 2, Name: "Eggs", IntervalDays: 3, NextAt: nowMock, CreatedBy: 22},
+		}, nil)
+
+		// The first chat has bread in the list already
+		stMock.EXPECT().GetShoppingItems(int64(1)).Return([]*models.ShoppingItem{
+			{ID: 5, ChatID: 1, Name: "bread"},
+		}, nil)
+		stMock.EXPECT().AddShoppingItemIntoShoppingList(gomock.Any()).Do(func(item models.ShoppingItem) {
+			if item.Name != "Milk" || item.ChatID != 1 || item.CreatedBy != 11 {
+				t.Errorf("Unexpected item %#v", item)
+			}
+		}).Return(nil)
+		stMock.EXPECT().UpdateStapleNextAt(int64(1), nowMock.AddDate(0, 0, 7)).Return(nil)
+		stMock.EXPECT().UpdateStapleNextAt(int64(2), nowMock.AddDate(0, 0, 7)).Return(nil)
+		stMock.EXPECT().GetChatSettings(int64(1)).Return(models.NewChatSettings(1), nil)
+		stMock.EXPECT().GetUserLocale(1).Return("", nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			expectedText := "I've added 1 item into your shopping list: Milk."
+			if msgCfg.ChatID != 1 || !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+
+		// The second chat has an empty list
+		stMock.EXPECT().GetShoppingItems(int64(2)).Return(nil, nil)
+		stMock.EXPECT().AddShoppingItemIntoShoppingList(gomock.Any()).Return(nil)
+		stMock.EXPECT().UpdateStapleNextAt(int64(3), nowMock.AddDate(0, 0, 3)).Return(nil)
+		stMock.EXPECT().GetChatSettings(int64(2)).Return(models.NewChatSettings(2), nil)
+		stMock.EXPECT().GetUserLocale(2).Return("", nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if msgCfg.ChatID != 2 || !strings.Contains(msgCfg.Text, "Eggs") {
+				t.Errorf("Unexpected message %#v in the chat %d",
+					msgCfg.Text, msgCfg.ChatID)
+			}
+		})
+
+		addDueStaples(clientMock, stMock, discardLogger, nowMock)
+	})
+
+	t.Run("Nothing to add", func(t *testing.T) {
+		stMock.EXPECT().GetDueStaples(nowMock).Return([]*models.Staple{
+			{ID: 1, ChatID: 1, Name: "Milk", IntervalDays: 7, NextAt: nowMock},
+		}, nil)
+		stMock.EXPECT().GetShoppingItems(int64(1)).Return([]*models.ShoppingItem{
+			{ID: 5, ChatID: 1, Name: "Milk"},
+		}, nil)
+		stMock.EXPECT().UpdateStapleNextAt(int64(1), nowMock.AddDate(0, 0, 7)).Return(nil)
+
+		addDueStaples(clientMock, stMock, discardLogger, nowMock)
+	})
+
+	t.Run("Storage error in one chat", func(t *testing.T) {
+		stMock.EXPECT().GetDueStaples(nowMock).Return([]*models.Staple{
+			{ID: 1, ChatID: 1, Name: "Milk", IntervalDays: 7, NextAt: nowMock},
+			{ID: 2, ChatID: 2, Name: "Eggs", IntervalDays: 7, NextAt: nowMock},
+		}, nil)
+		stMock.EXPECT().GetShoppingItems(int64(1)).Return(nil, errors.New("fake error"))
+
+		// Other chats still get their staples
+		stMock.EXPECT().GetShoppingItems(int64(2)).Return([]*models.ShoppingItem{
+			{ID: 5, ChatID: 2, Name: "Eggs"},
+		}, nil)
+		stMock.EXPECT().UpdateStapleNextAt(int64(2), nowMock.AddDate(0, 0, 7)).Return(nil)
+
+		addDueStaples(clientMock, stMock, discardLogger, nowMock)
+	})
+}
+
+func TestParseStapleInterval(t *testing.T) {
+	tr := i18n.NewTranslator("uk")
+
+	for text, expectedDays := range map[string]int{
+		"daily": 1, "Weekly": 7, "щодня": 1, "ЩОТИЖНЯ": 7, "14": 14,
+	} {
+		days, ok := parseStapleInterval(tr, text)
+		if !ok || days != expectedDays {
+			t.Errorf("Expected %d days for %#v, got %d", expectedDays, text, days)
+		}
+	}
+
+	for _, text := range []string{"milk", "0", "-1", "1000"} {
+		if _, ok := parseStapleInterval(tr, text); ok {
+			t.Errorf("Expected %#v not to be a schedule", text)
+		}
+	}
+}
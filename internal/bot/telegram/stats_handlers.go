@@ -0,0 +1,227 @@
+package telegram
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/bot/telegram/markup"
+	"github.com/m1kola/shipsterbot/internal/pkg/chart"
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// Periods and sizes of statistics
+const (
+	statsWeeks       = 8
+	statsTopLimit    = 5
+	statsBarMaxWidth = 10
+)
+
+// Size of the chart in pixels
+const (
+	statsChartWidth  = 640
+	statsChartHeight = 320
+)
+
+// statsDateLayout is how we show weeks in statistics
+const statsDateLayout = "2006-01-02"
+
+func init() {
+	registerCommands(botCommand{
+		name:        commandStats,
+		description: "command.stats.description",
+		args: []commandArg{
+			{name: "chart", optional: true},
+		},
+		helpSection:       helpSectionShoppingList,
+		showInHelpMessage: true,
+		commandHandler:    handleStats,
+	})
+}
+
+// handleStats shows statistics of the shopping list for recent weeks.
+// `/stats chart` also sends a chart of items added per week
+func handleStats(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	chatID := message.Chat.ID
+
+	tr, err := userTranslator(st, chatID, message.From)
+	if err != nil {
+		return err
+	}
+
+	since := statsWeekStart(timeNow()).AddDate(0, 0, -7*(statsWeeks-1))
+	stats, err := st.GetChatStats(chatID, since, statsTopLimit)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get statistics of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	if len(stats.WeeklyAdded) == 0 && stats.Purchases == 0 {
+		msg := tgbotapi.NewMessage(chatID, tr.N("stats.empty", statsWeeks, statsWeeks))
+		_, err = client.Send(msg)
+		return err
+	}
+
+	weekly := statsWeeklyCounts(stats.WeeklyAdded, since)
+
+	text := markup.NewBuilder(markup.ModeHTML)
+	text.Bold(tr.N("stats.title", statsWeeks, statsWeeks)).Text("\n\n")
+	writeStatsWeekly(text, tr, weekly)
+	writeStatsTopItems(text, tr, stats.TopItems)
+	writeStatsTopActors(text, tr, stats.TopActors)
+	if stats.Purchases > 0 {
+		text.Text(tr.N("stats.purchases", stats.Purchases, stats.Purchases,
+			formatStatsDuration(tr, stats.AvgTimeToPurchase)))
+	}
+
+	msg := newMarkupMessage(chatID, text)
+	if _, err := client.Send(msg); err != nil {
+		return err
+	}
+
+	arg := strings.TrimSpace(message.CommandArguments())
+	if !strings.EqualFold(arg, "chart") &&
+		!strings.EqualFold(arg, tr.T("stats.chart.arg")) {
+		return nil
+	}
+
+	values := make([]int, len(weekly))
+	for i, count := range weekly {
+		values[i] = count.Count
+	}
+
+	var buf bytes.Buffer
+	err = chart.Bars(&buf, values, statsChartWidth, statsChartHeight)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to draw a chart of statistics (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	photo := tgbotapi.NewPhotoUpload(chatID, tgbotapi.FileBytes{
+		Name:  "stats.png",
+		Bytes: buf.Bytes(),
+	})
+	photo.Caption = tr.T("stats.chart.caption",
+		weekly[0].WeekStart.Format(statsDateLayout),
+		weekly[len(weekly)-1].WeekStart.Format(statsDateLayout))
+	_, err = client.Send(photo)
+	return err
+}
+
+// statsWeekStart returns the beginning of a week
+// which contains t. Weeks start on Monday in UTC
+func statsWeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	days := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-days, 0, 0, 0, 0, time.UTC)
+}
+
+// statsWeeklyCounts returns counts for every week since the given one:
+// storage skips weeks without events
+func statsWeeklyCounts(counts []models.WeeklyCount, since time.Time) []models.WeeklyCount {
+	weekly := make([]models.WeeklyCount, statsWeeks)
+	for i := range weekly {
+		weekly[i].WeekStart = since.AddDate(0, 0, 7*i)
+	}
+
+	for _, count := range counts {
+		week := statsWeekStart(count.WeekStart)
+		for i := range weekly {
+			if weekly[i].WeekStart.Equal(week) {
+				weekly[i].Count = count.Count
+				break
+			}
+		}
+	}
+	return weekly
+}
+
+func writeStatsWeekly(text *markup.Builder, tr *i18n.Translator, weekly []models.WeeklyCount) {
+	max := 0
+	for _, count := range weekly {
+		if count.Count > max {
+			max = count.Count
+		}
+	}
+
+	var table bytes.Buffer
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	for _, count := range weekly {
+		bar := ""
+		if max > 0 {
+			bar = strings.Repeat("█", (count.Count*statsBarMaxWidth+max-1)/max)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n",
+			count.WeekStart.Format(statsDateLayout), count.Count, bar)
+	}
+	w.Flush()
+
+	text.Text(tr.T("stats.weekly")).Text("\n").Pre(table.String()).Text("\n")
+}
+
+func writeStatsTopItems(text *markup.Builder, tr *i18n.Translator, items []models.ItemCount) {
+	if len(items) == 0 {
+		return
+	}
+
+	var table bytes.Buffer
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	for _, item := range items {
+		fmt.Fprintf(w, "%s\t%d\n", item.Name, item.Count)
+	}
+	w.Flush()
+
+	text.Text(tr.T("stats.top_items")).Text("\n").Pre(table.String()).Text("\n")
+}
+
+func writeStatsTopActors(text *markup.Builder, tr *i18n.Translator, actors []models.ActorCount) {
+	if len(actors) == 0 {
+		return
+	}
+
+	var table bytes.Buffer
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	for _, actor := range actors {
+		fmt.Fprintf(w, "%s\t%d\n", actor.Actor.Name, actor.Count)
+	}
+	w.Flush()
+
+	text.Text(tr.T("stats.top_users")).Text("\n").Pre(table.String()).Text("\n")
+}
+
+// formatStatsDuration returns a human readable duration
+// with two most significant units: "2 days 3 hours", "5 hours 10 minutes"
+func formatStatsDuration(tr *i18n.Translator, d time.Duration) string {
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	var parts []string
+	switch {
+	case days > 0:
+		parts = append(parts, tr.N("stats.duration.days", days, days))
+		if hours > 0 {
+			parts = append(parts, tr.N("stats.duration.hours", hours, hours))
+		}
+	case hours > 0:
+		parts = append(parts, tr.N("stats.duration.hours", hours, hours))
+		if minutes > 0 {
+			parts = append(parts, tr.N("stats.duration.minutes", minutes, minutes))
+		}
+	default:
+		parts = append(parts, tr.N("stats.duration.minutes", minutes, minutes))
+	}
+	return strings.Join(parts, " ")
+}
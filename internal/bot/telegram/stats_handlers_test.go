@@ -0,0 +1,170 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+func TestHandleStats(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Mock: timeNow. It's Wednesday
+	nowMock := time.Date(2018, 10, 24, 7, 0, 0, 0, time.UTC)
+	oldTimeNow := timeNow
+	defer func() { timeNow = oldTimeNow }()
+	timeNow = func() time.Time { return nowMock }
+
+	// Common data mocks
+	chatID := int64(123)
+	since := time.Date(2018, 9, 3, 0, 0, 0, 0, time.UTC)
+	newMessageMock := func(args string) *tgbotapi.Message {
+		messageMock := mock_telegram.MessageCommandMockSetup(commandStats, args)
+		messageMock.Chat = &tgbotapi.Chat{ID: chatID}
+		messageMock.From = &tgbotapi.User{ID: 321}
+		return messageMock
+	}
+	statsMock := &models.ChatStats{
+		WeeklyAdded: []models.WeeklyCount{
+			{WeekStart: time.Date(2018, 10, 15, 0, 0, 0, 0, time.UTC), Count: 4},
+			{WeekStart: time.Date(2018, 10, 22, 0, 0, 0, 0, time.UTC), Count: 2},
+		},
+		TopItems: []models.ItemCount{
+			{Name: "milk", Count: 3},
+			{Name: "<b>", Count: 1},
+		},
+		TopActors: []models.ActorCount{
+			{Actor: models.Actor{ID: 321, Name: "Alice"}, Count: 6},
+		},
+		Purchases:         3,
+		AvgTimeToPurchase: 26*time.Hour + 5*time.Minute,
+	}
+
+	t.Run("Empty stats", func(t *testing.T) {
+		stMock.EXPECT().GetChatStats(chatID, since, statsTopLimit).Return(
+			&models.ChatStats{}, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			expectedText := "Nobody has added or bought anything in the last 8 weeks."
+			if msgCfg.Text != expectedText {
+				t.Errorf("Expected %#v, got %#v", expectedText, msgCfg.Text)
+			}
+		})
+
+		err := handleStats(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		stMock.EXPECT().GetChatStats(chatID, since, statsTopLimit).Return(statsMock, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if msgCfg.ParseMode != tgbotapi.ModeHTML {
+				t.Errorf("Expected HTML parse mode, got %#v", msgCfg.ParseMode)
+			}
+
+			expectedParts := []string{
+				"<b>Statistics of your shopping list for the last 8 weeks</b>",
+				"<pre>2018-09-03  0  \n",
+				"2018-10-15  4  ██████████\n2018-10-22  2  █████\n</pre>",
+				"<pre>milk  3\n&lt;b&gt;   1\n</pre>",
+				"<pre>Alice  6\n</pre>",
+				"3 items were bought. Items stay in the list for 1 day 2 hours on average.",
+			}
+			for _, part := range expectedParts {
+				if !strings.Contains(msgCfg.Text, part) {
+					t.Errorf("Expected message to contain %#v, got %#v", part, msgCfg.Text)
+				}
+			}
+		})
+
+		err := handleStats(clientMock, stMock, newMessageMock(""))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Chart", func(t *testing.T) {
+		stMock.EXPECT().GetChatStats(chatID, since, statsTopLimit).Return(statsMock, nil)
+		gomock.InOrder(
+			clientMock.EXPECT().Send(gomock.Any()),
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(photo tgbotapi.PhotoConfig) {
+				file, ok := photo.File.(tgbotapi.FileBytes)
+				if !ok || len(file.Bytes) == 0 {
+					t.Errorf("Expected a PNG image, got %#v", photo.File)
+				}
+
+				expectedCaption := "Items added per week from 2018-09-03 to 2018-10-22"
+				if photo.Caption != expectedCaption {
+					t.Errorf("Expected caption %#v, got %#v", expectedCaption, photo.Caption)
+				}
+			}),
+		)
+
+		err := handleStats(clientMock, stMock, newMessageMock("chart"))
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
+
+func TestStatsWeekStart(t *testing.T) {
+	testCases := []struct {
+		t        time.Time
+		expected time.Time
+	}{
+		{
+			t:        time.Date(2018, 10, 22, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2018, 10, 22, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			t:        time.Date(2018, 10, 28, 23, 59, 0, 0, time.UTC),
+			expected: time.Date(2018, 10, 22, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// Weeks are in UTC
+			t:        time.Date(2018, 10, 29, 1, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)),
+			expected: time.Date(2018, 10, 22, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		if actual := statsWeekStart(tc.t); !actual.Equal(tc.expected) {
+			t.Errorf("Expected %v for %v, got %v", tc.expected, tc.t, actual)
+		}
+	}
+}
+
+func TestFormatStatsDuration(t *testing.T) {
+	tr := i18n.NewTranslator("en")
+
+	testCases := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{d: 30 * time.Second, expected: "0 minutes"},
+		{d: 45 * time.Minute, expected: "45 minutes"},
+		{d: time.Hour + time.Minute, expected: "1 hour 1 minute"},
+		{d: 3 * time.Hour, expected: "3 hours"},
+		{d: 50 * time.Hour, expected: "2 days 2 hours"},
+	}
+
+	for _, tc := range testCases {
+		if actual := formatStatsDuration(tr, tc.d); actual != tc.expected {
+			t.Errorf("Expected %#v for %v, got %#v", tc.expected, tc.d, actual)
+		}
+	}
+}
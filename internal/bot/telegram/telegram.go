@@ -1,16 +1,21 @@
 package telegram
 
 import (
-	"log"
+	"fmt"
 	"net/http"
+	"os"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 
+	"github.com/m1kola/shipsterbot/internal/pkg/logging"
 	"github.com/m1kola/shipsterbot/internal/pkg/storage"
 )
 
 const defaultServerPort = "8443"
 
+const defaultAuditLogRetention = 30 * 24 * time.Hour
+
 type webHookServerConfig struct {
 	port        string
 	TLSCertPath string
@@ -21,8 +26,13 @@ type webHookServerConfig struct {
 // with the Telegram API
 type BotApp struct {
 	bot          botClientInterface
+	limiter      *rateLimitedSender
 	storage      storage.DataStorageInterface
+	logger       *logging.Logger
 	serverConfig *webHookServerConfig
+
+	// auditLogRetention is how long the audit log keeps changes
+	auditLogRetention time.Duration
 }
 
 // WebhookTLS allows webhook webserver to operate in secure transport mode
@@ -43,6 +53,27 @@ func WebhookPort(port string) func(*BotApp) error {
 	}
 }
 
+// Logger sets a custom logger for the bot app
+func Logger(logger *logging.Logger) func(*BotApp) error {
+	return func(app *BotApp) error {
+		app.logger = logger
+		return nil
+	}
+}
+
+// AuditLogRetention sets how many days the audit log keeps changes
+func AuditLogRetention(days int) func(*BotApp) error {
+	return func(app *BotApp) error {
+		if days < 1 {
+			return fmt.Errorf(
+				"Audit log retention must be at least one day, got %d", days)
+		}
+
+		app.auditLogRetention = time.Duration(days) * 24 * time.Hour
+		return nil
+	}
+}
+
 var tgbotapiNewBotAPI = tgbotapi.NewBotAPI
 
 // NewBotApp creates a new instance of a bot struct
@@ -51,6 +82,12 @@ func NewBotApp(
 	apiToken string,
 	options ...(func(*BotApp) error),
 ) (*BotApp, error) {
+	// Reminders and the log don't work without the timezone database,
+	// so it's better to fail early
+	if err := checkTimezones(settingsTimezones); err != nil {
+		return nil, err
+	}
+
 	client, err := tgbotapiNewBotAPI(apiToken)
 	if err != nil {
 		return nil, err
@@ -60,10 +97,20 @@ func NewBotApp(
 		port: defaultServerPort,
 	}
 
+	// All outgoing messages go through the rate limiter,
+	// so the bot doesn't get banned for flooding
+	apiClient := &apiClientWrapper{client}
+	limiter := newRateLimitedSender(apiClient, defaultRateLimits)
+
 	botApp := &BotApp{
-		bot:          &apiClientWrapper{client},
+		bot: &botClientWithSender{
+			newChatMemberCache(apiClient, chatMemberCacheTTL), limiter},
+		limiter:      limiter,
 		storage:      storage,
+		logger:       logging.New(os.Stderr, logging.LevelInfo),
 		serverConfig: serverConfig,
+
+		auditLogRetention: defaultAuditLogRetention,
 	}
 
 	for _, option := range options {
@@ -76,15 +123,32 @@ func NewBotApp(
 	return botApp, nil
 }
 
+// SendQueueDepth returns number of outgoing messages
+// that are waiting for the rate limiter
+func (bapp *BotApp) SendQueueDepth() int {
+	return bapp.limiter.QueueDepth()
+}
+
 // StartBotApp starts the  bot
 func StartBotApp(bapp *BotApp) error {
+	// The bot works without published commands,
+	// users just don't get suggestions from their clients
+	if err := publishCommands(bapp.bot, botCommands); err != nil {
+		bapp.logger.Error("Unable to publish bot commands", "err", err)
+	}
+
 	updates := getUpdatesChan(bapp.bot)
-	go routeUpdates(bapp.bot, bapp.storage, updates)
+	go routeUpdates(bapp.bot, bapp.storage, bapp.logger, updates)
+	// Copy jobs, so we don't change the package-level slice
+	jobs := append(append([]scheduledJob{}, scheduledJobs...),
+		cleanUpAuditLog(bapp.auditLogRetention))
+	go runScheduler(bapp.bot, bapp.storage, bapp.logger, jobs,
+		time.Tick(schedulerInterval))
 
 	server := newServerWithIncommingRequstLogger(
-		bapp.serverConfig.port, http.DefaultServeMux)
+		bapp.serverConfig.port, http.DefaultServeMux, bapp.logger)
 
-	log.Printf("Start listening on %s", server.Addr)
+	bapp.logger.Info("Start listening", "addr", server.Addr)
 	err := listenAndServe(
 		server,
 		bapp.serverConfig.TLSCertPath,
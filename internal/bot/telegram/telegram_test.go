@@ -4,11 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 	"github.com/golang/mock/gomock"
 
+	"github.com/m1kola/shipsterbot/internal/pkg/logging"
 	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
 )
 
 func TestNewBotApp(t *testing.T) {
@@ -91,6 +94,102 @@ func TestNewBotApp(t *testing.T) {
 		})
 	})
 
+	t.Run("Logger", func(t *testing.T) {
+		t.Run("Default logger", func(t *testing.T) {
+			app, err := NewBotApp(
+				storageMock,
+				"fake_token",
+			)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if app.logger == nil {
+				t.Error("Expected default logger to be set")
+			}
+		})
+
+		t.Run("Custom logger", func(t *testing.T) {
+			app, err := NewBotApp(
+				storageMock,
+				"fake_token",
+				Logger(discardLogger),
+			)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if app.logger != discardLogger {
+				t.Errorf("%p expected as a logger, got %p",
+					discardLogger, app.logger)
+			}
+		})
+	})
+
+	t.Run("Audit log retention", func(t *testing.T) {
+		t.Run("Default retention", func(t *testing.T) {
+			app, err := NewBotApp(
+				storageMock,
+				"fake_token",
+			)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if app.auditLogRetention != defaultAuditLogRetention {
+				t.Errorf("%v expected as a retention, got %v",
+					defaultAuditLogRetention, app.auditLogRetention)
+			}
+		})
+
+		t.Run("Custom retention", func(t *testing.T) {
+			app, err := NewBotApp(
+				storageMock,
+				"fake_token",
+				AuditLogRetention(7),
+			)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			expectedRetention := 7 * 24 * time.Hour
+			if app.auditLogRetention != expectedRetention {
+				t.Errorf("%v expected as a retention, got %v",
+					expectedRetention, app.auditLogRetention)
+			}
+		})
+
+		t.Run("Invalid retention", func(t *testing.T) {
+			_, err := NewBotApp(
+				storageMock,
+				"fake_token",
+				AuditLogRetention(0),
+			)
+			if err == nil {
+				t.Error("Expected an error for zero retention")
+			}
+		})
+	})
+
+	t.Run("Rate limiter", func(t *testing.T) {
+		app, err := NewBotApp(
+			storageMock,
+			"fake_token",
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		client, ok := app.bot.(*botClientWithSender)
+		if !ok || client.sender != app.limiter {
+			t.Error("Expected the bot client to send messages via the rate limiter")
+		}
+
+		if app.SendQueueDepth() != 0 {
+			t.Errorf("Expected empty queue, got %d", app.SendQueueDepth())
+		}
+	})
+
 	t.Run("Option error", func(t *testing.T) {
 		expectedErr := errors.New("Fake error")
 
@@ -126,6 +225,7 @@ func TestNewBotApp(t *testing.T) {
 
 func TestStartBotApp(t *testing.T) {
 	mockBotApp := &BotApp{
+		logger: discardLogger,
 		serverConfig: &webHookServerConfig{
 			port: "8443",
 		},
@@ -139,6 +239,35 @@ func TestStartBotApp(t *testing.T) {
 		return nil
 	}
 
+	// Mock: runScheduler
+	oldRunScheduler := runScheduler
+	defer func() { runScheduler = oldRunScheduler }()
+	runScheduler = func(
+		client sender,
+		st storage.DataStorageInterface,
+		logger *logging.Logger,
+		jobs []scheduledJob,
+		ticks <-chan time.Time,
+	) {
+		// No op mock
+	}
+
+	// Mock: publishCommands
+	publishCommandsErr := errors.New("Fake publish error")
+	publishCommandsIsCalled := false
+	oldPublishCommands := publishCommands
+	defer func() { publishCommands = oldPublishCommands }()
+	publishCommands = func(_ requestMaker, registry *commandRegistry) error {
+		publishCommandsIsCalled = true
+
+		if registry != botCommands {
+			t.Error("Expected commands from the bot registry to be published")
+		}
+
+		// The bot should start even if commands are not published
+		return publishCommandsErr
+	}
+
 	t.Run("Without error", func(t *testing.T) {
 		// Mock: listenAndServe
 		oldListenAndServe := listenAndServe
@@ -152,6 +281,10 @@ func TestStartBotApp(t *testing.T) {
 		if err != nil {
 			t.Errorf("Expected nil, got error %v", err)
 		}
+
+		if !publishCommandsIsCalled {
+			t.Error("publishCommands wasn't called")
+		}
 	})
 
 	t.Run("With error", func(t *testing.T) {
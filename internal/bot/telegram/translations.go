@@ -0,0 +1,80 @@
+package telegram
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// userTranslator returns a translator for an user in a chat.
+//
+// Locales are chosen in this order: a locale chosen by the user
+// via the `/language` command, a language of the chat chosen
+// via the `/settings` command, a language of the user's Telegram client.
+// The returned translator is usable even if there is an error:
+// in this case it uses the language of the client.
+func userTranslator(
+	st storage.DataStorageInterface,
+	chatID int64,
+	user *tgbotapi.User,
+) (*i18n.Translator, error) {
+	if user == nil {
+		return i18n.NewTranslator(i18n.DefaultLocale), nil
+	}
+
+	locale, err := st.GetUserLocale(user.ID)
+	if err != nil {
+		return i18n.NewTranslator(user.LanguageCode), fmt.Errorf(
+			"Unable to get a locale of the user (UserID=%d): %v",
+			user.ID, err)
+	}
+
+	if locale == "" {
+		settings, err := st.GetChatSettings(chatID)
+		if err != nil {
+			return i18n.NewTranslator(user.LanguageCode), fmt.Errorf(
+				"Unable to get settings of the chat (ChatID=%d): %v",
+				chatID, err)
+		}
+		locale = settings.Locale
+	}
+
+	if locale == "" {
+		locale = user.LanguageCode
+	}
+	return i18n.NewTranslator(locale), nil
+}
+
+// chatTranslator returns a translator for messages which the bot
+// sends on its own, without a request from an user.
+//
+// Locales are chosen in this order: a language of the chat chosen
+// via the `/settings` command, a locale of the user in private chats
+// (IDs of private chats are IDs of users), the default locale.
+// The returned translator is usable even if there is an error:
+// in this case it uses the default locale.
+func chatTranslator(
+	st storage.DataStorageInterface,
+	chatID int64,
+) (*i18n.Translator, error) {
+	settings, err := st.GetChatSettings(chatID)
+	if err != nil {
+		return i18n.NewTranslator(i18n.DefaultLocale), fmt.Errorf(
+			"Unable to get settings of the chat (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	locale := settings.Locale
+	if locale == "" && chatID > 0 {
+		locale, err = st.GetUserLocale(int(chatID))
+		if err != nil {
+			return i18n.NewTranslator(i18n.DefaultLocale), fmt.Errorf(
+				"Unable to get a locale of the user (UserID=%d): %v",
+				chatID, err)
+		}
+	}
+	return i18n.NewTranslator(locale), nil
+}
@@ -0,0 +1,146 @@
+package telegram
+
+import (
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+func TestUserTranslator(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+	var chatID int64 = 123
+	userMock := &tgbotapi.User{ID: 321, LanguageCode: "uk-UA"}
+
+	t.Run("No user", func(t *testing.T) {
+		tr, err := userTranslator(stMock, chatID, nil)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+		if tr.Locale() != i18n.DefaultLocale {
+			t.Errorf("Expected locale %#v, got %#v", i18n.DefaultLocale, tr.Locale())
+		}
+	})
+
+	t.Run("Chosen locale", func(t *testing.T) {
+		stMock.EXPECT().GetUserLocale(userMock.ID).Return("en", nil)
+
+		tr, err := userTranslator(stMock, chatID, userMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+		if tr.Locale() != "en" {
+			t.Errorf("Expected locale %#v, got %#v", "en", tr.Locale())
+		}
+	})
+
+	t.Run("Chat language", func(t *testing.T) {
+		settingsMock := models.NewChatSettings(chatID)
+		settingsMock.Locale = "en"
+
+		stMock.EXPECT().GetUserLocale(userMock.ID).Return("", nil)
+		stMock.EXPECT().GetChatSettings(chatID).Return(settingsMock, nil)
+
+		tr, err := userTranslator(stMock, chatID, userMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+		if tr.Locale() != "en" {
+			t.Errorf("Expected locale %#v, got %#v", "en", tr.Locale())
+		}
+	})
+
+	t.Run("Client language", func(t *testing.T) {
+		stMock.EXPECT().GetUserLocale(userMock.ID).Return("", nil)
+		stMock.EXPECT().GetChatSettings(chatID).Return(models.NewChatSettings(chatID), nil)
+
+		tr, err := userTranslator(stMock, chatID, userMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+		if tr.Locale() != "uk" {
+			t.Errorf("Expected locale %#v, got %#v", "uk", tr.Locale())
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().GetUserLocale(userMock.ID).Return("", errors.New("fake error"))
+
+		tr, err := userTranslator(stMock, chatID, userMock)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+		if tr == nil || tr.Locale() != "uk" {
+			t.Errorf("Expected a translator for the client language, got %#v", tr)
+		}
+	})
+}
+
+func TestChatTranslator(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+	t.Run("Chat language", func(t *testing.T) {
+		var chatID int64 = -123
+		settingsMock := models.NewChatSettings(chatID)
+		settingsMock.Locale = "uk"
+		stMock.EXPECT().GetChatSettings(chatID).Return(settingsMock, nil)
+
+		tr, err := chatTranslator(stMock, chatID)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+		if tr.Locale() != "uk" {
+			t.Errorf("Expected locale %#v, got %#v", "uk", tr.Locale())
+		}
+	})
+
+	t.Run("User locale in a private chat", func(t *testing.T) {
+		var chatID int64 = 321
+		stMock.EXPECT().GetChatSettings(chatID).Return(models.NewChatSettings(chatID), nil)
+		stMock.EXPECT().GetUserLocale(321).Return("uk", nil)
+
+		tr, err := chatTranslator(stMock, chatID)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+		if tr.Locale() != "uk" {
+			t.Errorf("Expected locale %#v, got %#v", "uk", tr.Locale())
+		}
+	})
+
+	t.Run("Default locale in a group", func(t *testing.T) {
+		var chatID int64 = -123
+		stMock.EXPECT().GetChatSettings(chatID).Return(models.NewChatSettings(chatID), nil)
+
+		tr, err := chatTranslator(stMock, chatID)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+		if tr.Locale() != i18n.DefaultLocale {
+			t.Errorf("Expected locale %#v, got %#v", i18n.DefaultLocale, tr.Locale())
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		var chatID int64 = -123
+		stMock.EXPECT().GetChatSettings(chatID).Return(nil, errors.New("fake error"))
+
+		tr, err := chatTranslator(stMock, chatID)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+		if tr == nil || tr.Locale() != i18n.DefaultLocale {
+			t.Errorf("Expected a translator for the default locale, got %#v", tr)
+		}
+	})
+}
@@ -3,46 +3,143 @@ package telegram
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 
+	"github.com/m1kola/shipsterbot/internal/bot/telegram/markup"
+	"github.com/m1kola/shipsterbot/internal/pkg/category"
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/itemname"
 	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/money"
 	"github.com/m1kola/shipsterbot/internal/pkg/storage"
 )
 
-var sendHelpMessage = func(client sender, message *tgbotapi.Message, isStart bool) {
-	var greeting string
+func init() {
+	registerCommands(
+		botCommand{
+			name: commandHelp,
+			// The `/start` command is implicit: Telegram sends on user's behalf
+			// when they start the bot.
+			aliases:           []string{commandStart},
+			description:       "command.help.description",
+			showInHelpMessage: true,
+			commandHandler:    handleStart,
+		},
+		botCommand{
+			name:                     commandAdd,
+			description:              "command.add.description",
+			args:                     []commandArg{{name: "item", optional: true}},
+			helpSection:              helpSectionShoppingList,
+			showInHelpMessage:        true,
+			commandHandler:           handleAdd,
+			unfinishedCommandHandler: handleAddSession,
+			callbackQueryHandler:     handleAddCallbackQuery,
+			// The "add" inline query result
+			chosenInlineResultHandler: handleAddChosenInlineResult,
+		},
+		botCommand{
+			name:              commandList,
+			description:       "command.list.description",
+			helpSection:       helpSectionShoppingList,
+			showInHelpMessage: true,
+			commandHandler:    handleList,
+		},
+		botCommand{
+			name:                 commandDel,
+			description:          "command.del.description",
+			args:                 []commandArg{{name: "item", optional: true}},
+			helpSection:          helpSectionShoppingList,
+			showInHelpMessage:    true,
+			commandHandler:       handleDel,
+			callbackQueryHandler: handleDelCallbackQuery,
+		},
+		botCommand{
+			name:              commandDedupe,
+			description:       "command.dedupe.description",
+			helpSection:       helpSectionShoppingList,
+			showInHelpMessage: true,
+			commandHandler:    handleDedupe,
+		},
+		botCommand{
+			name:                 commandClear,
+			description:          "command.clear.description",
+			helpSection:          helpSectionShoppingList,
+			showInHelpMessage:    true,
+			permission:           permissionAdmin,
+			commandHandler:       handleClear,
+			callbackQueryHandler: handleClearCallbackQuery,
+		},
+		botCommand{
+			name:                 commandLanguage,
+			description:          "command.language.description",
+			args:                 []commandArg{{name: "language", optional: true}},
+			helpSection:          helpSectionSettings,
+			showInHelpMessage:    true,
+			commandHandler:       handleLanguage,
+			callbackQueryHandler: handleLanguageCallbackQuery,
+		},
+	)
+}
+
+// sendHelpMessage sends the list of commands from the command registry
+var sendHelpMessage = func(
+	client sender,
+	tr *i18n.Translator,
+	message *tgbotapi.Message,
+	isStart bool,
+) error {
+	text := markup.NewBuilder(markup.ModeHTML)
 	if isStart {
-		greeting = "Hi %s,"
+		text.Text(tr.T("help.greeting", message.From.FirstName))
 	} else {
-		greeting = "%s, I'm very sorry, but I don't understand you."
+		text.Text(tr.T("help.not_understood", message.From.FirstName))
 	}
-	textTemplate := greeting + `
 
-I can help you to manage your shopping list.
+	text.Text("\n\n" + tr.T("help.intro") + "\n")
+
+	sections := map[string][]botCommand{}
+	for _, command := range botCommands.helpCommands() {
+		sections[command.helpSection] = append(
+			sections[command.helpSection], command)
+	}
 
-You can control me by sending these commands:
+	writeCommands := func(commands []botCommand) {
+		for _, command := range commands {
+			text.Textf("\n%s - %s",
+				command.usage(tr), tr.T(command.description))
+		}
+	}
 
-*Shopping list*
+	writeCommands(sections[""])
+	for _, section := range helpSections {
+		if len(sections[section]) == 0 {
+			continue
+		}
 
-/add - Adds an item into your shopping list
-/list - Displays items from your shopping list
-/del - Removes an item from your shopping list
-/clear - Removes all items from the shopping list`
-	text := fmt.Sprintf(textTemplate, message.From.FirstName)
+		text.Text("\n\n")
+		text.Bold(tr.T("help.section." + section))
+		text.Text("\n")
+		writeCommands(sections[section])
+	}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, text)
-	msg.ParseMode = tgbotapi.ModeMarkdown
-	client.Send(msg)
+	msg := newMarkupMessage(message.Chat.ID, text)
+	_, err := client.Send(msg)
+	return err
 }
 
 func handleStart(
-	client sender,
+	client botClientInterface,
 	st storage.DataStorageInterface,
 	message *tgbotapi.Message,
 ) error {
-	sendHelpMessage(client, message, true)
-	return nil
+	tr, err := userTranslator(st, message.Chat.ID, message.From)
+	if err != nil {
+		return err
+	}
+
+	return sendHelpMessage(client, tr, message, true)
 }
 
 // handleUnrecoverableError sends the "something went wrong" message to a chat
@@ -51,16 +148,17 @@ func handleStart(
 // 		 See: https://github.com/m1kola/shipsterbot/issues/27
 var handleUnrecoverableError = func(
 	client botClientInterface,
+	tr *i18n.Translator,
 	chatID int64,
 	_ error,
-) {
-	text := "Sorry, but something went wrong. I'll inform developers about this issue. Please, try again a bit later."
-	msg := tgbotapi.NewMessage(chatID, text)
-	client.Send(msg)
+) error {
+	msg := tgbotapi.NewMessage(chatID, tr.T("error.unrecoverable"))
+	_, err := client.Send(msg)
+	return err
 }
 
 func handleAdd(
-	client sender,
+	client botClientInterface,
 	st storage.DataStorageInterface,
 	message *tgbotapi.Message,
 ) error {
@@ -70,9 +168,14 @@ func handleAdd(
 		return handleAddSession(client, st, message)
 	}
 
+	tr, err := userTranslator(st, message.Chat.ID, message.From)
+	if err != nil {
+		return err
+	}
+
 	// If an item name is not provided in arguments,
 	// allow the user to add an item following the two-step process
-	err := st.AddUnfinishedCommand(models.UnfinishedCommand{
+	err = st.AddUnfinishedCommand(models.UnfinishedCommand{
 		Command:   commandAdd,
 		ChatID:    message.Chat.ID,
 		CreatedBy: message.From.ID,
@@ -84,11 +187,16 @@ func handleAdd(
 			commandAdd, err)
 	}
 
-	format := "Ok [%s](tg://user?id=%d), what do you want to add into your shopping list?"
-	text := fmt.Sprintf(format, message.From.FirstName, message.From.ID)
+	keyboard, hasSuggestions, err := frequentItemsKeyboard(st, message.Chat.ID)
+	if err != nil {
+		return err
+	}
+
+	text := markup.NewBuilder(markup.ModeHTML).Format(
+		tr.Template("add.prompt"),
+		markup.Mention(message.From.FirstName, message.From.ID))
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, text)
-	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg := newMarkupMessage(message.Chat.ID, text)
 
 	// If we are not in a private chat,
 	// force user to reply because we can't listen all messages in a group.
@@ -98,21 +206,51 @@ func handleAdd(
 			ForceReply: true,
 			Selective:  true,
 		}
+	} else if hasSuggestions {
+		msg.ReplyMarkup = keyboard
 	}
 
-	client.Send(msg)
+	_, err = client.Send(msg)
+	if err != nil || !hasSuggestions || message.Chat.IsPrivate() {
+		return err
+	}
 
-	return nil
+	// A message can't force a reply and have an inline keyboard
+	// at the same time, so in groups we suggest items separately
+	suggestionsMsg := tgbotapi.NewMessage(message.Chat.ID, tr.T("add.frequent"))
+	suggestionsMsg.ReplyMarkup = keyboard
+	_, err = client.Send(suggestionsMsg)
+	return err
 }
 
 func handleList(
-	client sender,
+	client botClientInterface,
 	st storage.DataStorageInterface,
 	message *tgbotapi.Message,
 ) error {
-	var text string
-	chatID := message.Chat.ID
+	tr, err := userTranslator(st, message.Chat.ID, message.From)
+	if err != nil {
+		return err
+	}
+
+	text := markup.NewBuilder(markup.ModeHTML)
+	if err := writeShoppingList(st, tr, message.Chat.ID, text); err != nil {
+		return err
+	}
+
+	msg := newMarkupMessage(message.Chat.ID, text)
+	_, err = client.Send(msg)
+	return err
+}
 
+// writeShoppingList writes items from a shopping list
+// of a chat grouped by categories into text
+func writeShoppingList(
+	st storage.DataStorageInterface,
+	tr *i18n.Translator,
+	chatID int64,
+	text *markup.Builder,
+) error {
 	chatItems, err := st.GetShoppingItems(chatID)
 	if err != nil {
 		return fmt.Errorf(
@@ -121,64 +259,511 @@ func handleList(
 	}
 
 	if len(chatItems) == 0 {
-		text = "Your shopping list is empty. Who knows, maybe it's a good thing"
-	} else {
-		offset := len(strconv.Itoa(len(chatItems)))
-		listItemFormat := fmt.Sprintf("%%%dd. %%s\n", offset)
-
-		listNumber := 1
-		for _, item := range chatItems {
-			text += fmt.Sprintf(listItemFormat, listNumber, item.Name)
-			listNumber++
-		}
+		text.Text(tr.T("list.empty"))
+		return nil
+	}
 
-		text = fmt.Sprintf(
-			"%s\n\n```\n%s```",
-			"Here is the list item in your shopping list:",
-			text)
+	settings, err := st.GetChatSettings(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get settings of the chat (ChatID=%d): %v",
+			chatID, err)
 	}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, text)
-	msg.ParseMode = tgbotapi.ModeMarkdown
-	client.Send(msg)
+	text.Text(tr.N("list.header", len(chatItems), len(chatItems)) + "\n\n")
+	// Groups only make sense when items are sorted by categories,
+	// otherwise they break the order which users have chosen
+	var order []string
+	if settings.SortOrder == models.SortOrderCategory {
+		order = category.Order(settings.CategoryOrder)
+	}
 
+	text.Pre(formatShoppingList(tr, chatItems, order, settings.Currency))
+	writeShoppingListTotal(tr, chatItems, settings, text)
 	return nil
 }
 
+// writeShoppingListTotal writes the total of estimated prices of items
+// and warns users when the total exceeds the budget of the chat
+func writeShoppingListTotal(
+	tr *i18n.Translator,
+	items []*models.ShoppingItem,
+	settings *models.ChatSettings,
+	text *markup.Builder,
+) {
+	total, unpriced := shoppingListTotal(items)
+	if total == 0 && settings.Budget == 0 {
+		return
+	}
+
+	text.Text("\n" + tr.T("list.total", money.Format(total, settings.Currency)))
+	if unpriced > 0 {
+		text.Text(" " + tr.N("list.total.unpriced", unpriced, unpriced))
+	}
+
+	if settings.Budget == 0 {
+		return
+	}
+
+	text.Text("\n" + tr.T("list.budget", money.Format(settings.Budget, settings.Currency)))
+	if total > settings.Budget {
+		overspend := money.Format(total-settings.Budget, settings.Currency)
+		text.Text("\n").Bold(tr.T("list.budget.exceeded", overspend))
+	}
+}
+
+// shoppingListTotal returns the total of estimated prices of items
+// and how many items don't have prices
+func shoppingListTotal(items []*models.ShoppingItem) (total money.Amount, unpriced int) {
+	for _, item := range items {
+		if item.Price == 0 {
+			unpriced++
+			continue
+		}
+		total += item.Price.Times(itemQuantity(item))
+	}
+	return total, unpriced
+}
+
 var handleAddSession = func(
-	client sender,
+	client botClientInterface,
 	st storage.DataStorageInterface,
 	message *tgbotapi.Message,
 ) error {
+	tr, err := userTranslator(st, message.Chat.ID, message.From)
+	if err != nil {
+		return err
+	}
+
 	itemName := message.CommandArguments()
 	if itemName == "" {
 		itemName = message.Text
 	}
 
-	err := st.AddShoppingItemIntoShoppingList(models.ShoppingItem{
-		Name:      itemName,
-		ChatID:    message.Chat.ID,
-		CreatedBy: message.From.ID})
+	// Users can tell us an estimated price: "milk €1.20"
+	itemName, price, _ := money.ExtractPrice(itemName)
+
+	chatID := message.Chat.ID
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	// Users often forget what's already in the list,
+	// so we ask them before adding a duplicate
+	if duplicate := findDuplicateItem(chatItems, itemName); duplicate != nil {
+		return sendDuplicatePrompt(client, tr, chatID, duplicate, price)
+	}
+
+	item, err := newShoppingItem(st, chatID, itemName, message.From.ID)
+	if err != nil {
+		return err
+	}
+	item.Price = price
+
+	err = st.AddShoppingItemIntoShoppingList(item)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to add a new shopping item (ChatID=%d, UserId=%d): %v",
+			chatID, message.From.ID, err)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, tr.T("add.done", itemName))
+	_, err = client.Send(msg)
+	return err
+}
+
+// Actions of the keyboard which we show when an user adds a duplicate.
+//
+// Callback query data of the keyboard is "add:<action>=<item ID>[,<price>]"
+// where the item is the item which is already in the list
+// and the price is a price which the user typed with the duplicate.
+// We don't put names into callback query data:
+// Telegram limits its length to 64 bytes.
+//
+// Suggestions of frequently bought items use the same format, but
+// they refer to entries of the item history: "add:re=<entry ID>"
+const (
+	addCallbackDataIncrease = "inc"
+	addCallbackDataAnyway   = "new"
+	addCallbackDataFrequent = "re"
+)
+
+// frequentItemsLimit is how many frequently bought items we suggest
+const frequentItemsLimit = 8
+
+const (
+	addCallbackDataSeparator      = "="
+	addCallbackDataPriceSeparator = ","
+)
+
+func joinAddData(action string, itemID int64, price money.Amount) string {
+	data := action + addCallbackDataSeparator + strconv.FormatInt(itemID, 10)
+	if price > 0 {
+		data += addCallbackDataPriceSeparator + price.String()
+	}
+	return joinCallbackQueryData(commandAdd, data)
+}
+
+func handleAddCallbackQuery(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	callbackQuery *tgbotapi.CallbackQuery,
+	data string,
+) error {
+	client.AnswerCallbackQuery(tgbotapi.NewCallback(
+		callbackQuery.ID, ""))
+
+	tr, err := userTranslator(st, callbackQuery.Message.Chat.ID, callbackQuery.From)
 	if err != nil {
+		return err
+	}
+
+	chatID := callbackQuery.Message.Chat.ID
+	messageID := callbackQuery.Message.MessageID
+
+	pieces := strings.SplitN(data, addCallbackDataSeparator, 2)
+	action := pieces[0]
+	dataIsValid := len(pieces) == 2 &&
+		(action == addCallbackDataIncrease || action == addCallbackDataAnyway ||
+			action == addCallbackDataFrequent)
+	if !dataIsValid {
 		return fmt.Errorf(
-			"Unable to add a new shopping item (ItemName=%s, ChatID=%d, UserId=%d): %v",
-			itemName, message.Chat.ID, message.From.ID, err)
+			"Unable to parse an action from the CallbackQuery data %#v",
+			data)
 	}
 
-	text := "Lovely! I've added \"%s\" into your shopping list. Anything else?"
-	text = fmt.Sprintf(text, itemName)
-	msg := tgbotapi.NewMessage(message.Chat.ID, text)
-	client.Send(msg)
+	values := strings.SplitN(pieces[1], addCallbackDataPriceSeparator, 2)
+	itemID, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		// User can't amend CallBackData, so most likely it's our fault
+		return fmt.Errorf(
+			"Unable to parse ItemID from the CallbackQuery data %s: %v",
+			data, err)
+	}
+
+	var price money.Amount
+	if len(values) == 2 {
+		if price, err = money.Parse(values[1]); err != nil {
+			return fmt.Errorf(
+				"Unable to parse a price from the CallbackQuery data %s: %v",
+				data, err)
+		}
+	}
+
+	if action == addCallbackDataFrequent {
+		// The ID is an ID of an entry in the item history
+		return addFrequentItem(client, st, tr, callbackQuery, itemID)
+	}
+
+	item, err := st.GetShoppingItem(itemID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get a shopping item (ItemID=%d): %v",
+			itemID, err)
+	}
+
+	var text string
+	switch {
+	case item == nil || item.ChatID != chatID:
+		// Someone has removed the item, since we asked
+		text = tr.T("add.not_found")
+	case action == addCallbackDataIncrease:
+		if err := st.IncreaseShoppingItemQuantity(itemID, 1); err != nil {
+			return fmt.Errorf(
+				"Unable to increase quantity of a shopping item (ItemID=%d): %v",
+				itemID, err)
+		}
+
+		// The price which the user typed is newer than the price in the list
+		if price > 0 {
+			if err := st.UpdateShoppingItemPrice(itemID, price); err != nil {
+				return fmt.Errorf(
+					"Unable to update price of a shopping item (ItemID=%d): %v",
+					itemID, err)
+			}
+		}
+
+		text = tr.T("add.increased", itemQuantity(item)+1, item.Name)
+	default:
+		newItem, err := newShoppingItem(st, chatID, item.Name, callbackQuery.From.ID)
+		if err != nil {
+			return err
+		}
+		newItem.Price = price
+
+		err = st.AddShoppingItemIntoShoppingList(newItem)
+		if err != nil {
+			return fmt.Errorf(
+				"Unable to add a new shopping item (ItemID=%d, ChatID=%d, UserId=%d): %v",
+				itemID, chatID, callbackQuery.From.ID, err)
+		}
+
+		text = tr.T("add.done", item.Name)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	return sendAfterHidingKeyboard(client, chatID, messageID, msg)
+}
+
+// addFrequentItem adds an item which an user picked from suggestions.
+// The suggestions stay, so users can add several items one by one
+func addFrequentItem(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	tr *i18n.Translator,
+	callbackQuery *tgbotapi.CallbackQuery,
+	entryID int64,
+) error {
+	chatID := callbackQuery.Message.Chat.ID
+	messageID := callbackQuery.Message.MessageID
+	userID := callbackQuery.From.ID
+
+	entry, err := st.GetItemHistoryEntry(entryID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get an item history entry (EntryID=%d): %v",
+			entryID, err)
+	}
+
+	if entry == nil || entry.ChatID != chatID {
+		msg := tgbotapi.NewMessage(chatID, tr.T("add.not_found"))
+		_, err = client.Send(msg)
+		return err
+	}
+
+	// The user has answered our question by tapping the button,
+	// so we don't wait for the item name anymore
+	if err := st.DeleteUnfinishedCommand(chatID, userID); err != nil {
+		return fmt.Errorf(
+			"Unable to delete an unfinished comamnd (ChatID=%d and UserId=%d): %v",
+			chatID, userID, err)
+	}
+
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	if duplicate := findDuplicateItem(chatItems, entry.Name); duplicate != nil {
+		return sendDuplicatePrompt(client, tr, chatID, duplicate, 0)
+	}
+
+	item, err := newShoppingItem(st, chatID, entry.Name, userID)
+	if err != nil {
+		return err
+	}
+
+	err = st.AddShoppingItemIntoShoppingList(item)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to add a new shopping item (EntryID=%d, ChatID=%d, UserId=%d): %v",
+			entry.ID, chatID, userID, err)
+	}
+
+	keyboard, hasSuggestions, err := frequentItemsKeyboard(st, chatID)
+	if err != nil {
+		return err
+	}
+
+	msg := tgbotapi.NewMessage(chatID, tr.T("add.done", entry.Name))
+	if !hasSuggestions {
+		return sendAfterHidingKeyboard(client, chatID, messageID, msg)
+	}
+
+	// The item is added already, so users must get the confirmation
+	// even if suggestions are out of date
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, keyboard)
+	_, editErr := client.Send(edit)
+	if _, err := client.Send(msg); err != nil {
+		return err
+	}
+	if editErr != nil && !isMessageNotModifiedError(editErr) {
+		return keyboardError{editErr}
+	}
 	return nil
 }
 
-func handleDel(
+// frequentItemsKeyboard returns an inline keyboard with items which users
+// of a chat buy most often, except items which are in the list already.
+// It returns false, if there is nothing to suggest
+func frequentItemsKeyboard(
+	st storage.DataStorageInterface,
+	chatID int64,
+) (tgbotapi.InlineKeyboardMarkup, bool, error) {
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return tgbotapi.InlineKeyboardMarkup{}, false, fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	// Some of frequent items can be in the list,
+	// so we ask for more items than we show
+	entries, err := st.GetFrequentItems(chatID, frequentItemsLimit+len(chatItems))
+	if err != nil {
+		return tgbotapi.InlineKeyboardMarkup{}, false, fmt.Errorf(
+			"Unable to get frequent items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	var buttons []tgbotapi.InlineKeyboardButton
+	for _, entry := range entries {
+		if len(buttons) == frequentItemsLimit {
+			break
+		}
+		if findDuplicateItem(chatItems, entry.Name) != nil {
+			continue
+		}
+
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(
+			entry.Name, joinAddData(addCallbackDataFrequent, entry.ID, 0)))
+	}
+
+	if len(buttons) == 0 {
+		return tgbotapi.InlineKeyboardMarkup{}, false, nil
+	}
+
+	// Names are short, so two buttons fit in a row
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i := 0; i < len(buttons); i += 2 {
+		end := i + 2
+		if end > len(buttons) {
+			end = len(buttons)
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(buttons[i:end]...))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...), true, nil
+}
+
+// sendDuplicatePrompt asks an user whether they need more of an item
+// which is in the list already or they want to add it anyway
+func sendDuplicatePrompt(
 	client sender,
+	tr *i18n.Translator,
+	chatID int64,
+	duplicate *models.ShoppingItem,
+	price money.Amount,
+) error {
+	increaseCallbackData := joinAddData(addCallbackDataIncrease, duplicate.ID, price)
+	anywayCallbackData := joinAddData(addCallbackDataAnyway, duplicate.ID, price)
+
+	msg := tgbotapi.NewMessage(chatID, tr.T("add.duplicate", duplicate.Name))
+	msg.BaseChat.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		[]tgbotapi.InlineKeyboardButton{
+			tgbotapi.NewInlineKeyboardButtonData(
+				tr.T("add.duplicate.increase", itemQuantity(duplicate)+1),
+				increaseCallbackData),
+			tgbotapi.NewInlineKeyboardButtonData(
+				tr.T("add.duplicate.anyway"), anywayCallbackData)})
+	_, err := client.Send(msg)
+	return err
+}
+
+// handleDedupe merges items with the same name into one item
+// with the total quantity. The first added item is kept
+func handleDedupe(
+	client botClientInterface,
 	st storage.DataStorageInterface,
 	message *tgbotapi.Message,
 ) error {
+	tr, err := userTranslator(st, message.Chat.ID, message.From)
+	if err != nil {
+		return err
+	}
+
+	chatID := message.Chat.ID
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	var merged int
+	for _, group := range groupDuplicateItems(chatItems) {
+		if len(group) < 2 {
+			continue
+		}
+
+		item := group[0]
+		var duplicateIDs []int64
+		for _, duplicate := range group[1:] {
+			duplicateIDs = append(duplicateIDs, duplicate.ID)
+		}
+
+		if err := st.MergeShoppingItems(item.ID, duplicateIDs); err != nil {
+			return fmt.Errorf(
+				"Unable to merge shopping items (ItemID=%d, DuplicateIDs=%v): %v",
+				item.ID, duplicateIDs, err)
+		}
+		merged += len(duplicateIDs)
+	}
+
 	var text string
-	var itemButtonRows [][]tgbotapi.InlineKeyboardButton
+	if merged == 0 {
+		text = tr.T("dedupe.none")
+	} else {
+		text = tr.N("dedupe.done", merged, merged)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	_, err = client.Send(msg)
+	return err
+}
+
+// findDuplicateItem returns an item with the same name
+// or nil, if there is no such item
+func findDuplicateItem(items []*models.ShoppingItem, name string) *models.ShoppingItem {
+	for _, item := range items {
+		if itemname.Equal(item.Name, name) {
+			return item
+		}
+	}
+	return nil
+}
+
+// groupDuplicateItems groups items with the same name.
+// Groups and items in groups keep the order of items
+func groupDuplicateItems(items []*models.ShoppingItem) [][]*models.ShoppingItem {
+	var groups [][]*models.ShoppingItem
+	groupIndexes := map[string]int{}
+	for _, item := range items {
+		name := itemname.Normalize(item.Name)
+
+		i, ok := groupIndexes[name]
+		if !ok {
+			i = len(groups)
+			groupIndexes[name] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], item)
+	}
+	return groups
+}
+
+// itemQuantity returns quantity of an item.
+// Items which were created without quantity are single items
+func itemQuantity(item *models.ShoppingItem) int {
+	if item.Quantity < 1 {
+		return 1
+	}
+	return item.Quantity
+}
+
+func handleDel(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	tr, err := userTranslator(st, message.Chat.ID, message.From)
+	if err != nil {
+		return err
+	}
 
 	chatID := message.Chat.ID
 	chatItems, err := st.GetShoppingItems(chatID)
@@ -188,11 +773,96 @@ func handleDel(
 			chatID, err)
 	}
 
-	isEmpty := len(chatItems) == 0
+	// Allow to skip the keyboard: `/del milk, bread`
+	itemNames := splitItemNames(message.CommandArguments())
+	if len(itemNames) == 0 {
+		return sendDelKeyboard(client, chatID, chatItems, tr.T("del.prompt"), tr)
+	}
+
+	var deletedNames, notFoundNames []string
+	var ambiguousNames []string
+	ambiguousItems := map[string][]*models.ShoppingItem{}
+	for _, itemName := range itemNames {
+		matches, exact := matchItems(chatItems, itemName)
+		switch {
+		case len(matches) == 0:
+			notFoundNames = append(notFoundNames, itemName)
+		case exact:
+			item := matches[0]
+			if err := st.DeleteShoppingItem(item.ID); err != nil {
+				return fmt.Errorf(
+					"Unable to delete a shopping item (ItemID=%d): %v",
+					item.ID, err)
+			}
+
+			deletedNames = append(deletedNames, item.Name)
+			// The next names must not match the deleted item
+			chatItems = withoutItem(chatItems, item)
+		default:
+			ambiguousNames = append(ambiguousNames, itemName)
+			ambiguousItems[itemName] = matches
+		}
+	}
+
+	if len(deletedNames) == 1 {
+		msg := tgbotapi.NewMessage(chatID, tr.T("del.done", deletedNames[0]))
+		if _, err := client.Send(msg); err != nil {
+			return err
+		}
+	} else if len(deletedNames) > 1 {
+		text := tr.N("del.done.many", len(deletedNames),
+			len(deletedNames), strings.Join(deletedNames, ", "))
+		msg := tgbotapi.NewMessage(chatID, text)
+		if _, err := client.Send(msg); err != nil {
+			return err
+		}
+	}
+
+	if len(itemNames) == 1 && len(notFoundNames) == 1 {
+		// We can't find the only item, so we let the user choose it
+		text := tr.T("del.not_found.prompt", notFoundNames[0])
+		return sendDelKeyboard(client, chatID, chatItems, text, tr)
+	} else if len(notFoundNames) > 0 {
+		text := tr.T("del.not_found.many", strings.Join(notFoundNames, ", "))
+		msg := tgbotapi.NewMessage(chatID, text)
+		if _, err := client.Send(msg); err != nil {
+			return err
+		}
+	}
+
+	// Items only look like the name, so we let the user choose one of them.
+	// We don't delete a single similar item straight away:
+	// "ham" could be a typo, but "hamburger" is not ham
+	for _, itemName := range ambiguousNames {
+		text := tr.T("del.ambiguous.prompt", itemName)
+		if len(ambiguousItems[itemName]) == 1 {
+			text = tr.T("del.similar.prompt", itemName)
+		}
+		err := sendDelKeyboard(client, chatID, ambiguousItems[itemName], text, tr)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendDelKeyboard sends a keyboard with items which users can delete
+func sendDelKeyboard(
+	client sender,
+	chatID int64,
+	items []*models.ShoppingItem,
+	text string,
+	tr *i18n.Translator,
+) error {
+	isEmpty := len(items) == 0
 	if isEmpty {
-		text = "Your shopping list is empty. No need to delete items 🙂"
-	} else {
-		for _, item := range chatItems {
+		text = tr.T("del.empty")
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	if !isEmpty {
+		var itemButtonRows [][]tgbotapi.InlineKeyboardButton
+		for _, item := range items {
 			callbackData := joinCallbackQueryData(
 				commandDel, strconv.FormatInt(item.ID, 10),
 			)
@@ -202,17 +872,50 @@ func handleDel(
 			itemButtonRows = append(itemButtonRows, itemButtonRow)
 		}
 
-		text = "Ok, what item do you want to delete from your shopping list?"
-	}
-
-	msg := tgbotapi.NewMessage(chatID, text)
-	if !isEmpty {
 		msg.BaseChat.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
 			itemButtonRows...)
 	}
-	client.Send(msg)
+	_, err := client.Send(msg)
+	return err
+}
 
-	return nil
+// splitItemNames splits comma separated names of items
+func splitItemNames(text string) []string {
+	var names []string
+	for _, name := range strings.Split(text, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// matchItems returns items which look like a name.
+// If there are items with exactly the same name, it returns
+// the first of them: it doesn't matter which one users delete.
+// exact is false, if items only look like the name
+func matchItems(items []*models.ShoppingItem, name string) (matches []*models.ShoppingItem, exact bool) {
+	if item := findDuplicateItem(items, name); item != nil {
+		return []*models.ShoppingItem{item}, true
+	}
+
+	for _, item := range items {
+		if itemname.Match(item.Name, name) {
+			matches = append(matches, item)
+		}
+	}
+	return matches, false
+}
+
+// withoutItem returns a copy of items without an item
+func withoutItem(items []*models.ShoppingItem, item *models.ShoppingItem) []*models.ShoppingItem {
+	var rest []*models.ShoppingItem
+	for _, i := range items {
+		if i != item {
+			rest = append(rest, i)
+		}
+	}
+	return rest
 }
 
 func handleDelCallbackQuery(
@@ -224,6 +927,11 @@ func handleDelCallbackQuery(
 	client.AnswerCallbackQuery(tgbotapi.NewCallback(
 		callbackQuery.ID, ""))
 
+	tr, err := userTranslator(st, callbackQuery.Message.Chat.ID, callbackQuery.From)
+	if err != nil {
+		return err
+	}
+
 	chatID := callbackQuery.Message.Chat.ID
 	messageID := callbackQuery.Message.MessageID
 	itemID, err := strconv.ParseInt(data, 10, 64)
@@ -250,33 +958,30 @@ func handleDelCallbackQuery(
 				itemID, err)
 		}
 
-		text = "It's nice to see that you think that you don't "
-		text += "need this \"%s\" thing. "
-		text += "I've removed it from your shopping list.\n\n"
-		text += "Can I do anything else for you?"
-		text = fmt.Sprintf(text, item.Name)
+		text = tr.T("del.done", item.Name)
 	} else {
-		text = "Can't find an item, sorry."
+		text = tr.T("del.not_found")
 	}
 
-	hideInlineKeyboard(client, chatID, messageID)
-
 	// Send deletion confimration text
 	msg := tgbotapi.NewMessage(chatID, text)
-	client.Send(msg)
-
-	return nil
+	return sendAfterHidingKeyboard(client, chatID, messageID, msg)
 }
 
 const clearCallbackDataConfim = "1"
 const clearCallbackDataCancel = "0"
 
 func handleClear(
-	client sender,
+	client botClientInterface,
 	st storage.DataStorageInterface,
 	message *tgbotapi.Message,
 ) error {
-	var text string
+	tr, err := userTranslator(st, message.Chat.ID, message.From)
+	if err != nil {
+		return err
+	}
+
+	text := markup.NewBuilder(markup.ModeHTML)
 
 	chatID := message.Chat.ID
 
@@ -289,14 +994,36 @@ func handleClear(
 	}
 
 	isEmpty := len(chatItems) == 0
+	if !isEmpty {
+		settings, err := st.GetChatSettings(chatID)
+		if err != nil {
+			return fmt.Errorf(
+				"Unable to get settings of the chat (ChatID=%d): %v",
+				chatID, err)
+		}
+
+		// Some chats prefer to delete items without confirmation
+		if !settings.ConfirmClear {
+			if err := st.DeleteAllShoppingItems(chatID); err != nil {
+				return fmt.Errorf(
+					"Unable to delete all shopping items (ChatID=%d): %v",
+					chatID, err)
+			}
+
+			msg := tgbotapi.NewMessage(chatID, tr.T("clear.done"))
+			_, err = client.Send(msg)
+			return err
+		}
+	}
+
 	if isEmpty {
-		text = "Your shopping list is empty. No need to delete items 🙂"
+		text.Text(tr.T("del.empty"))
 	} else {
-		text = "Are you sure that you want to *remove all items* from you shopping list?"
+		text.Format(tr.Template("clear.confirm"),
+			markup.Bold(tr.T("clear.confirm.remove_all")))
 	}
 
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg := newMarkupMessage(chatID, text)
 	if !isEmpty {
 		yesCallbackData := joinCallbackQueryData(commandClear, clearCallbackDataConfim)
 		cancelCallbackData := joinCallbackQueryData(commandClear, clearCallbackDataCancel)
@@ -304,11 +1031,11 @@ func handleClear(
 		msg.BaseChat.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
 			[]tgbotapi.InlineKeyboardButton{
 				// Order of the buttons in keyboard is important
-				tgbotapi.NewInlineKeyboardButtonData("Yes", yesCallbackData),
-				tgbotapi.NewInlineKeyboardButtonData("Cancel", cancelCallbackData)})
+				tgbotapi.NewInlineKeyboardButtonData(tr.T("clear.yes"), yesCallbackData),
+				tgbotapi.NewInlineKeyboardButtonData(tr.T("clear.cancel"), cancelCallbackData)})
 	}
-	client.Send(msg)
-	return nil
+	_, err = client.Send(msg)
+	return err
 }
 
 func handleClearCallbackQuery(
@@ -317,10 +1044,14 @@ func handleClearCallbackQuery(
 	callbackQuery *tgbotapi.CallbackQuery,
 	data string,
 ) error {
-
 	client.AnswerCallbackQuery(tgbotapi.NewCallback(
 		callbackQuery.ID, ""))
 
+	tr, err := userTranslator(st, callbackQuery.Message.Chat.ID, callbackQuery.From)
+	if err != nil {
+		return err
+	}
+
 	chatID := callbackQuery.Message.Chat.ID
 	messageID := callbackQuery.Message.MessageID
 	dataIsValid := data == clearCallbackDataConfim || data == clearCallbackDataCancel
@@ -334,7 +1065,7 @@ func handleClearCallbackQuery(
 	var text string
 	confirmed := data == clearCallbackDataConfim
 	if confirmed {
-		text = "Ok, I've deleted all items from you shopping list.\n\nNow you can start from scratch, if you wish."
+		text = tr.T("clear.done")
 
 		err := st.DeleteAllShoppingItems(chatID)
 		if err != nil {
@@ -343,21 +1074,181 @@ func handleClearCallbackQuery(
 				chatID, err)
 		}
 	} else {
-		text = "Canceling. Your items are still in your list."
+		text = tr.T("clear.canceled")
 	}
 
-	hideInlineKeyboard(client, chatID, messageID)
-
 	// Send deletion confimration text
 	msg := tgbotapi.NewMessage(chatID, text)
-	client.Send(msg)
+	return sendAfterHidingKeyboard(client, chatID, messageID, msg)
+}
 
-	return nil
+func handleLanguage(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	message *tgbotapi.Message,
+) error {
+	// Allow to skip the keyboard: `/language uk`
+	if locale := message.CommandArguments(); i18n.IsSupported(locale) {
+		return setUserLocale(client, st, message.Chat.ID, message.From, locale)
+	}
+
+	tr, err := userTranslator(st, message.Chat.ID, message.From)
+	if err != nil {
+		return err
+	}
+
+	var localeButtonRows [][]tgbotapi.InlineKeyboardButton
+	for _, locale := range i18n.Locales() {
+		callbackData := joinCallbackQueryData(commandLanguage, locale)
+		localeButtonRow := tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				i18n.LocaleName(locale), callbackData),
+		)
+		localeButtonRows = append(localeButtonRows, localeButtonRow)
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, tr.T("language.prompt"))
+	msg.BaseChat.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		localeButtonRows...)
+	_, err = client.Send(msg)
+	return err
+}
+
+func handleLanguageCallbackQuery(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	callbackQuery *tgbotapi.CallbackQuery,
+	data string,
+) error {
+	client.AnswerCallbackQuery(tgbotapi.NewCallback(
+		callbackQuery.ID, ""))
+
+	if !i18n.IsSupported(data) {
+		return fmt.Errorf(
+			"Unable to parse a locale from the CallbackQuery data %#v",
+			data)
+	}
+
+	chatID := callbackQuery.Message.Chat.ID
+	messageID := callbackQuery.Message.MessageID
+	hideErr := hideInlineKeyboard(client, chatID, messageID)
+	if err := setUserLocale(client, st, chatID, callbackQuery.From, data); err != nil {
+		return err
+	}
+	return hideErr
+}
+
+// setUserLocale saves a locale chosen by an user
+// and confirms it in the user's new language
+func setUserLocale(
+	client sender,
+	st storage.DataStorageInterface,
+	chatID int64,
+	user *tgbotapi.User,
+	locale string,
+) error {
+	if err := st.SetUserLocale(user.ID, locale); err != nil {
+		return fmt.Errorf(
+			"Unable to set a locale of the user (UserID=%d, Locale=%s): %v",
+			user.ID, locale, err)
+	}
+
+	tr := i18n.NewTranslator(locale)
+	msg := tgbotapi.NewMessage(chatID, tr.T("language.done"))
+	_, err := client.Send(msg)
+	return err
+}
+
+// formatShoppingItems returns a numbered list of shopping items
+func formatShoppingItems(items []*models.ShoppingItem) string {
+	var listText string
+	listItemFormat := shoppingItemFormat(len(items))
+	for i, item := range items {
+		listText += fmt.Sprintf(listItemFormat, i+1, shoppingItemName(item))
+	}
+	return listText
+}
+
+// formatShoppingList returns a numbered list of shopping items
+// grouped by categories in the order of aisles in a store.
+// Without an order of categories the list is flat.
+// Prices are in the currency of the chat
+func formatShoppingList(
+	tr *i18n.Translator,
+	items []*models.ShoppingItem,
+	order []string,
+	currency string,
+) string {
+	groups := map[string][]*models.ShoppingItem{}
+	for _, item := range items {
+		c := itemCategory(item)
+		groups[c] = append(groups[c], item)
+	}
+
+	var listText string
+	listItemFormat := shoppingItemFormat(len(items))
+
+	// Headers are useless, if we know nothing about items
+	if len(order) == 0 || len(groups) == 1 && len(groups[category.Other]) > 0 {
+		for i, item := range items {
+			listText += fmt.Sprintf(listItemFormat, i+1, shoppingListItemName(item, currency))
+		}
+		return listText
+	}
+
+	listNumber := 1
+	for _, c := range order {
+		if len(groups[c]) == 0 {
+			continue
+		}
+
+		if listText != "" {
+			listText += "\n"
+		}
+		listText += tr.T("category."+c) + "\n"
+		for _, item := range groups[c] {
+			listText += fmt.Sprintf(listItemFormat, listNumber, shoppingListItemName(item, currency))
+			listNumber++
+		}
+	}
+	return listText
+}
+
+// shoppingItemFormat returns a format of a line in a numbered list
+// with numbers aligned to the right
+func shoppingItemFormat(itemsCount int) string {
+	offset := len(strconv.Itoa(itemsCount))
+	return fmt.Sprintf("%%%dd. %%s\n", offset)
+}
+
+// shoppingItemName returns a name of an item with its quantity
+func shoppingItemName(item *models.ShoppingItem) string {
+	if quantity := itemQuantity(item); quantity > 1 {
+		return fmt.Sprintf("%s ×%d", item.Name, quantity)
+	}
+	return item.Name
+}
+
+// shoppingListItemName returns a name of an item in the list
+// with its quantity and the estimated price of the quantity
+func shoppingListItemName(item *models.ShoppingItem, currency string) string {
+	name := shoppingItemName(item)
+	if item.Price == 0 {
+		return name
+	}
+	return name + " · " + money.Format(item.Price.Times(itemQuantity(item)), currency)
+}
+
+// newMarkupMessage creates a new message with formatted text
+func newMarkupMessage(chatID int64, text *markup.Builder) tgbotapi.MessageConfig {
+	msg := tgbotapi.NewMessage(chatID, text.String())
+	msg.ParseMode = text.ParseMode()
+	return msg
 }
 
 // hideInlineKeyboard makes the telegram client to hide inline keybaord
 // by editing a message with `messageID` in in a chat with `chatID`
-func hideInlineKeyboard(client botClientInterface, chatID int64, messageID int) {
+func hideInlineKeyboard(client botClientInterface, chatID int64, messageID int) error {
 	// It's important to send replyMarkup exactly like this,
 	// because otherwise telegram clients will not hide the keybaord
 	replyMarkup := tgbotapi.NewInlineKeyboardMarkup(
@@ -368,5 +1259,30 @@ func hideInlineKeyboard(client botClientInterface, chatID int64, messageID int)
 		messageID,
 		replyMarkup,
 	)
-	client.Send(msg)
+	_, err := client.Send(msg)
+	if err == nil {
+		return nil
+	}
+	if isMessageNotModifiedError(err) {
+		// The keyboard is already hidden. For example,
+		// when an user taps on a button twice
+		return nil
+	}
+	return keyboardError{err}
+}
+
+// sendAfterHidingKeyboard hides the inline keyboard and sends a reply.
+// Handlers call it when they've changed the shopping list already,
+// so users get the reply even if the keyboard stays
+func sendAfterHidingKeyboard(
+	client botClientInterface,
+	chatID int64,
+	messageID int,
+	msg tgbotapi.Chattable,
+) error {
+	hideErr := hideInlineKeyboard(client, chatID, messageID)
+	if _, err := client.Send(msg); err != nil {
+		return err
+	}
+	return hideErr
 }
@@ -3,6 +3,8 @@ package telegram
 import (
 	"errors"
 	"fmt"
+	"html"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
@@ -10,18 +12,30 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 
 	"github.com/golang/mock/gomock"
+	"github.com/m1kola/shipsterbot/internal/pkg/category"
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
 	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
 	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
 	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/money"
 	"github.com/m1kola/shipsterbot/internal/pkg/storage"
 )
 
+// expectDefaultSettings allows handlers to get
+// default settings of users and chats any number of times
+func expectDefaultSettings(stMock *mock_storage.MockDataStorageInterface) {
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("", nil).AnyTimes()
+	stMock.EXPECT().GetChatSettings(gomock.Any()).Return(
+		models.NewChatSettings(0), nil).AnyTimes()
+}
+
 func TestHelpMessages(t *testing.T) {
 	// Common interface mocks
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
-	clientMock := mock_telegram.NewMocksender(mockCtrl)
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
 	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
 
 	messageMock := &tgbotapi.Message{
 		From: &tgbotapi.User{FirstName: "m1kola"},
@@ -69,7 +83,7 @@ func TestHelpMessages(t *testing.T) {
 				generateGreetingChecker(t),
 			)
 
-			sendHelpMessage(clientMock, messageMock, true)
+			sendHelpMessage(clientMock, i18n.NewTranslator("en"), messageMock, true)
 		})
 
 		t.Run("Unknown command", func(t *testing.T) {
@@ -77,8 +91,22 @@ func TestHelpMessages(t *testing.T) {
 				generateUnknownCommandChecker(t),
 			)
 
-			sendHelpMessage(clientMock, messageMock, false)
+			sendHelpMessage(clientMock, i18n.NewTranslator("en"), messageMock, false)
+		})
+	})
+
+	t.Run("Commands from the registry", func(t *testing.T) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			for _, command := range botCommands.helpCommands() {
+				expectedText := command.usage(i18n.NewTranslator("en"))
+				if !strings.Contains(msgCfg.Text, html.EscapeString(expectedText)) {
+					t.Errorf("Expected message to contain %#v, got %#v",
+						expectedText, msgCfg.Text)
+				}
+			}
 		})
+
+		sendHelpMessage(clientMock, i18n.NewTranslator("en"), messageMock, true)
 	})
 
 	t.Run("handleStart", func(t *testing.T) {
@@ -90,6 +118,29 @@ func TestHelpMessages(t *testing.T) {
 			handleStart(clientMock, stMock, messageMock)
 		})
 	})
+
+	t.Run("Hostile name", func(t *testing.T) {
+		// Data mocks
+		messageMock := &tgbotapi.Message{
+			From: &tgbotapi.User{FirstName: `<a href="http://example.com">m1kola</a>`},
+			Chat: &tgbotapi.Chat{ID: 123},
+		}
+
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			expectedText := "Hi &lt;a href=&quot;http://example.com&quot;&gt;m1kola&lt;/a&gt;,"
+			if !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+
+			if msgCfg.ParseMode != tgbotapi.ModeHTML {
+				t.Errorf("Expected %#v parse mode, got %#v",
+					tgbotapi.ModeHTML, msgCfg.ParseMode)
+			}
+		})
+
+		sendHelpMessage(clientMock, i18n.NewTranslator("en"), messageMock, true)
+	})
 }
 
 func TestHandleUnrecoverableError(t *testing.T) {
@@ -114,15 +165,16 @@ func TestHandleUnrecoverableError(t *testing.T) {
 		}
 	})
 
-	handleUnrecoverableError(clientMock, expectedChatID, errors.New("fake err"))
+	handleUnrecoverableError(clientMock, i18n.NewTranslator("en"), expectedChatID, errors.New("fake err"))
 }
 
 func TestHandleAdd(t *testing.T) {
 	// Common interface mocks
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
-	clientMock := mock_telegram.NewMocksender(mockCtrl)
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
 	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
 
 	// Common data mocks
 	errMock := errors.New("fake error")
@@ -133,7 +185,7 @@ func TestHandleAdd(t *testing.T) {
 		handleAddSessionOld := handleAddSession
 		defer func() { handleAddSession = handleAddSessionOld }()
 		handleAddSession = func(
-			_ sender,
+			_ botClientInterface,
 			_ storage.DataStorageInterface,
 			message *tgbotapi.Message,
 		) error {
@@ -167,6 +219,10 @@ func TestHandleAdd(t *testing.T) {
 	})
 
 	t.Run("Success", func(t *testing.T) {
+		// There is nothing to suggest
+		stMock.EXPECT().GetShoppingItems(int64(123)).Return(nil, nil).AnyTimes()
+		stMock.EXPECT().GetFrequentItems(int64(123), gomock.Any()).Return(nil, nil).AnyTimes()
+
 		t.Run("Private chat", func(t *testing.T) {
 			messageMock := &tgbotapi.Message{
 				Chat: &tgbotapi.Chat{ID: 123, Type: "private"},
@@ -224,6 +280,28 @@ func TestHandleAdd(t *testing.T) {
 				t.Errorf("Unexpected err: got %#v", err)
 			}
 		})
+
+		t.Run("Hostile name", func(t *testing.T) {
+			messageMock := &tgbotapi.Message{
+				Chat: &tgbotapi.Chat{ID: 123, Type: "private"},
+				From: &tgbotapi.User{ID: 321, FirstName: "</a><b>m1kola"},
+			}
+
+			stMock.EXPECT().AddUnfinishedCommand(gomock.Any()).Return(nil)
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				expectedText := `<a href="tg://user?id=321">&lt;/a&gt;&lt;b&gt;m1kola</a>`
+				if !strings.Contains(msgCfg.Text, expectedText) {
+					t.Errorf("Expected message to contain %#v, got %#v",
+						expectedText, msgCfg.Text)
+				}
+			})
+
+			err := handleAdd(clientMock, stMock, messageMock)
+
+			if err != nil {
+				t.Errorf("Unexpected err: got %#v", err)
+			}
+		})
 	})
 }
 
@@ -231,8 +309,9 @@ func TestHandleList(t *testing.T) {
 	// Common interface mocks
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
-	clientMock := mock_telegram.NewMocksender(mockCtrl)
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
 	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
 
 	// Common data mocks
 	errMock := errors.New("fake error")
@@ -251,6 +330,17 @@ func TestHandleList(t *testing.T) {
 		}
 	})
 
+	t.Run("Send error", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(gomock.Any()).Return(nil, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Return(tgbotapi.Message{}, errMock)
+
+		err := handleList(clientMock, stMock, messageMock)
+
+		if err != errMock {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+
 	t.Run("Success", func(t *testing.T) {
 		t.Run("Empty shopping list", func(t *testing.T) {
 			// Data mocks
@@ -310,15 +400,169 @@ func TestHandleList(t *testing.T) {
 				t.Errorf("Unexpected err: got %#v", err)
 			}
 		})
+
+		t.Run("Shopping list with quantities", func(t *testing.T) {
+			// Data mocks
+			storageDataMock := []*models.ShoppingItem{
+				{Name: "Milk", Quantity: 3},
+				{Name: "Bread", Quantity: 1},
+			}
+
+			stMock.EXPECT().GetShoppingItems(gomock.Any()).Return(storageDataMock, nil)
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				expectedText := "<pre>1. Milk ×3\n2. Bread\n</pre>"
+				if !strings.Contains(msgCfg.Text, expectedText) {
+					t.Errorf("Expected message to contain %#v, got %#v",
+						expectedText, msgCfg.Text)
+				}
+			})
+
+			err := handleList(clientMock, stMock, messageMock)
+
+			if err != nil {
+				t.Errorf("Unexpected err: got %#v", err)
+			}
+		})
+
+		t.Run("Shopping list with prices", func(t *testing.T) {
+			// Data mocks
+			storageDataMock := []*models.ShoppingItem{
+				{Name: "Milk", Quantity: 3, Price: 110},
+				{Name: "Bread"},
+			}
+
+			stMock.EXPECT().GetShoppingItems(gomock.Any()).Return(storageDataMock, nil)
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				expectedText := "<pre>1. Milk ×3 · €3.30\n2. Bread\n</pre>" +
+					"\nEstimated total: €3.30 (1 item without a price)"
+				if !strings.HasSuffix(msgCfg.Text, expectedText) {
+					t.Errorf("Expected message to end with %#v, got %#v",
+						expectedText, msgCfg.Text)
+				}
+			})
+
+			err := handleList(clientMock, stMock, messageMock)
+
+			if err != nil {
+				t.Errorf("Unexpected err: got %#v", err)
+			}
+		})
+
+		t.Run("Shopping list with hostile items", func(t *testing.T) {
+			// Data mocks
+			storageDataMock := []*models.ShoppingItem{
+				{Name: "</pre><b>Milk</b>"},
+				{Name: "*Eggs* & `bread`"},
+			}
+			expectedItemTexts := []string{
+				"&lt;/pre&gt;&lt;b&gt;Milk&lt;/b&gt;",
+				"*Eggs* &amp; `bread`",
+			}
+
+			stMock.EXPECT().GetShoppingItems(gomock.Any()).Return(storageDataMock, nil)
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				for _, expectedText := range expectedItemTexts {
+					if !strings.Contains(msgCfg.Text, expectedText) {
+						t.Errorf("Expected message to contain %#v, got %#v",
+							expectedText, msgCfg.Text)
+					}
+				}
+
+				if strings.Count(msgCfg.Text, "</pre>") != 1 {
+					t.Errorf("Expected message to contain a single pre block, got %#v",
+						msgCfg.Text)
+				}
+			})
+
+			err := handleList(clientMock, stMock, messageMock)
+
+			if err != nil {
+				t.Errorf("Unexpected err: got %#v", err)
+			}
+		})
 	})
 }
 
+func TestHandleListWithBudget(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Common data mocks
+	messageMock := &tgbotapi.Message{
+		Chat: &tgbotapi.Chat{ID: 123},
+		From: &tgbotapi.User{ID: 321},
+	}
+	settings := models.NewChatSettings(messageMock.Chat.ID)
+	settings.Currency = money.UAH
+	settings.Budget = 5000
+
+	stMock.EXPECT().GetChatSettings(messageMock.Chat.ID).Return(settings, nil)
+	stMock.EXPECT().GetShoppingItems(messageMock.Chat.ID).Return([]*models.ShoppingItem{
+		{Name: "Cheese", Quantity: 2, Price: 3000},
+	}, nil)
+	clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+		expectedText := "\nEstimated total: 60.00 ₴" +
+			"\nBudget: 50.00 ₴" +
+			"\n<b>⚠️ The list is over the budget by 10.00 ₴</b>"
+		if !strings.HasSuffix(msgCfg.Text, expectedText) {
+			t.Errorf("Expected message to end with %#v, got %#v",
+				expectedText, msgCfg.Text)
+		}
+	})
+
+	err := handleList(clientMock, stMock, messageMock)
+	if err != nil {
+		t.Errorf("Unexpected err: got %#v", err)
+	}
+}
+
+func TestHandleListSortedByCategory(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil).AnyTimes()
+
+	// Common data mocks
+	messageMock := &tgbotapi.Message{
+		Chat: &tgbotapi.Chat{ID: 123},
+		From: &tgbotapi.User{ID: 321},
+	}
+	settings := models.NewChatSettings(messageMock.Chat.ID)
+	settings.SortOrder = models.SortOrderCategory
+	settings.CategoryOrder = []string{category.Dairy}
+
+	stMock.EXPECT().GetChatSettings(messageMock.Chat.ID).Return(settings, nil)
+	stMock.EXPECT().GetShoppingItems(messageMock.Chat.ID).Return([]*models.ShoppingItem{
+		{Name: "Milk", Quantity: 3, Category: category.Dairy},
+		{Name: "Bread", Category: category.Bakery},
+	}, nil)
+	clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+		expectedText := "<pre>Dairy\n1. Milk ×3\n\nBakery\n2. Bread\n</pre>"
+		if !strings.Contains(msgCfg.Text, expectedText) {
+			t.Errorf("Expected message to contain %#v, got %#v",
+				expectedText, msgCfg.Text)
+		}
+	})
+
+	err := handleList(clientMock, stMock, messageMock)
+	if err != nil {
+		t.Errorf("Unexpected err: got %#v", err)
+	}
+}
+
 func TestHandleAddSession(t *testing.T) {
 	// Common interface mocks
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
-	clientMock := mock_telegram.NewMocksender(mockCtrl)
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
 	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
 
 	t.Run("Storage error", func(t *testing.T) {
 		// Data mocks
@@ -329,6 +573,8 @@ func TestHandleAddSession(t *testing.T) {
 			From: &tgbotapi.User{ID: 321},
 		}
 
+		stMock.EXPECT().GetShoppingItems(messageMock.Chat.ID).Return(nil, nil)
+		stMock.EXPECT().GetCategoryKeywords(messageMock.Chat.ID).Return(nil, nil)
 		stMock.EXPECT().AddShoppingItemIntoShoppingList(gomock.Any()).Return(errMock)
 
 		err := handleAddSession(clientMock, stMock, messageMock)
@@ -355,9 +601,19 @@ func TestHandleAddSession(t *testing.T) {
 			messageMock.From = &tgbotapi.User{ID: 321}
 
 			// Set up interface mocks
+			stMock.EXPECT().GetShoppingItems(messageMock.Chat.ID).Return(
+				[]*models.ShoppingItem{{ID: 1, Name: "Bread"}}, nil)
+			stMock.EXPECT().GetCategoryKeywords(messageMock.Chat.ID).Return(
+				map[string]string{expectedItemName: category.Drinks}, nil)
 			stMock.EXPECT().AddShoppingItemIntoShoppingList(
 				gomock.Any(),
 			).Do(func(item models.ShoppingItem) {
+				if item.Category != category.Drinks {
+					t.Errorf(
+						"Expected item with category %#v, got %#v",
+						category.Drinks, item.Category,
+					)
+				}
 				if item.Name != expectedItemName {
 					t.Errorf(
 						"Expected item with name %#v, got %#v",
@@ -389,12 +645,531 @@ func TestHandleAddSession(t *testing.T) {
 				}
 			})
 
-			err := handleAddSession(clientMock, stMock, messageMock)
-			if err != nil {
-				t.Errorf("Unexpected err: got %#v", err)
+			err := handleAddSession(clientMock, stMock, messageMock)
+			if err != nil {
+				t.Errorf("Unexpected err: got %#v", err)
+			}
+		}
+
+	})
+
+	t.Run("With a price", func(t *testing.T) {
+		// Data mocks
+		messageMock := &tgbotapi.Message{
+			Text: "Oat milk €1,20",
+			Chat: &tgbotapi.Chat{ID: 123},
+			From: &tgbotapi.User{ID: 321},
+		}
+
+		// Interface mocks
+		stMock.EXPECT().GetShoppingItems(messageMock.Chat.ID).Return(nil, nil)
+		stMock.EXPECT().GetCategoryKeywords(messageMock.Chat.ID).Return(nil, nil)
+		stMock.EXPECT().AddShoppingItemIntoShoppingList(
+			gomock.Any(),
+		).Do(func(item models.ShoppingItem) {
+			if item.Name != "Oat milk" || item.Price != 120 {
+				t.Errorf("Expected Oat milk for 1.20, got %#v for %s",
+					item.Name, item.Price)
+			}
+		}).Return(nil)
+		clientMock.EXPECT().Send(gomock.Any())
+
+		err := handleAddSession(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		// Data mocks
+		messageMock := &tgbotapi.Message{
+			Text: "milks ",
+			Chat: &tgbotapi.Chat{ID: 123},
+			From: &tgbotapi.User{ID: 321},
+		}
+		duplicate := &models.ShoppingItem{ID: 7, Name: "Milk", Quantity: 2}
+
+		// Interface mocks
+		stMock.EXPECT().GetShoppingItems(messageMock.Chat.ID).Return(
+			[]*models.ShoppingItem{{ID: 1, Name: "Bread"}, duplicate}, nil)
+		clientMock.EXPECT().Send(
+			gomock.Any(),
+		).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.Contains(msgCfg.Text, duplicate.Name) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					duplicate.Name, msgCfg.Text)
+			}
+
+			replyMarkup, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+			if !ok {
+				t.Fatalf("Expected an inline keyboard, got %#v", msgCfg.ReplyMarkup)
+			}
+
+			buttons := replyMarkup.InlineKeyboard[0]
+			expectedData := []string{"add:inc=7", "add:new=7"}
+			if len(buttons) != len(expectedData) {
+				t.Fatalf("Expected %d buttons, got %d", len(expectedData), len(buttons))
+			}
+			for i, button := range buttons {
+				if *button.CallbackData != expectedData[i] {
+					t.Errorf("Expected callback data %#v, got %#v",
+						expectedData[i], *button.CallbackData)
+				}
+			}
+
+			expectedText := "buy 3"
+			if !strings.Contains(buttons[0].Text, expectedText) {
+				t.Errorf("Expected button to contain %#v, got %#v",
+					expectedText, buttons[0].Text)
+			}
+		})
+
+		err := handleAddSession(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Duplicate with a price", func(t *testing.T) {
+		// Data mocks
+		messageMock := &tgbotapi.Message{
+			Text: "Milk €1,20",
+			Chat: &tgbotapi.Chat{ID: 123},
+			From: &tgbotapi.User{ID: 321},
+		}
+
+		// Interface mocks
+		stMock.EXPECT().GetShoppingItems(messageMock.Chat.ID).Return(
+			[]*models.ShoppingItem{{ID: 7, Name: "Milk"}}, nil)
+		clientMock.EXPECT().Send(
+			gomock.Any(),
+		).Do(func(msgCfg tgbotapi.MessageConfig) {
+			replyMarkup, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+			if !ok {
+				t.Fatalf("Expected an inline keyboard, got %#v", msgCfg.ReplyMarkup)
+			}
+
+			data := keyboardCallbackData(replyMarkup)
+			expectedData := []string{"add:inc=7,1.20", "add:new=7,1.20"}
+			if !reflect.DeepEqual(data, expectedData) {
+				t.Errorf("Expected callback data %v, got %v", expectedData, data)
+			}
+		})
+
+		err := handleAddSession(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
+
+func TestHandleAddCallbackQuery(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
+
+	// Common data mocks
+	errMock := errors.New("Fake error")
+	chatID := int64(123)
+	callbackQueryMock := &tgbotapi.CallbackQuery{
+		ID:   "some-callback-id",
+		From: &tgbotapi.User{ID: 321},
+		Message: &tgbotapi.Message{
+			MessageID: 456,
+			Chat:      &tgbotapi.Chat{ID: chatID},
+		},
+	}
+	item := &models.ShoppingItem{ID: 7, Name: "Milk", ChatID: chatID, Quantity: 2}
+
+	expectReply := func(t *testing.T, expectedText string) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(
+			generateSendHideKeybaordCallChecker(t, callbackQueryMock))
+		clientMock.EXPECT().Send(
+			gomock.Any(),
+		).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+	}
+
+	t.Run("Callback data parsing error", func(t *testing.T) {
+		for _, dataMock := range []string{"inc", "foo=7", "inc=not int", "inc=7,not price"} {
+			clientMock.EXPECT().AnswerCallbackQuery(gomock.Any())
+
+			err := handleAddCallbackQuery(clientMock, stMock, callbackQueryMock, dataMock)
+			if err == nil {
+				t.Errorf("Expected an error for %#v", dataMock)
+			}
+		}
+	})
+
+	t.Run("Increase quantity", func(t *testing.T) {
+		clientMock.EXPECT().AnswerCallbackQuery(gomock.Any())
+		stMock.EXPECT().GetShoppingItem(item.ID).Return(item, nil)
+		stMock.EXPECT().IncreaseShoppingItemQuantity(item.ID, 1).Return(nil)
+		expectReply(t, "3 × \"Milk\"")
+
+		err := handleAddCallbackQuery(clientMock, stMock, callbackQueryMock, "inc=7")
+		if err != nil {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("Increase quantity with a price", func(t *testing.T) {
+		clientMock.EXPECT().AnswerCallbackQuery(gomock.Any())
+		stMock.EXPECT().GetShoppingItem(item.ID).Return(item, nil)
+		stMock.EXPECT().IncreaseShoppingItemQuantity(item.ID, 1).Return(nil)
+		stMock.EXPECT().UpdateShoppingItemPrice(item.ID, money.Amount(120)).Return(nil)
+		expectReply(t, "3 × \"Milk\"")
+
+		err := handleAddCallbackQuery(clientMock, stMock, callbackQueryMock, "inc=7,1.20")
+		if err != nil {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("Add anyway with a price", func(t *testing.T) {
+		clientMock.EXPECT().AnswerCallbackQuery(gomock.Any())
+		stMock.EXPECT().GetShoppingItem(item.ID).Return(item, nil)
+		stMock.EXPECT().GetCategoryKeywords(chatID).Return(nil, nil)
+		stMock.EXPECT().AddShoppingItemIntoShoppingList(models.ShoppingItem{
+			Name:      item.Name,
+			ChatID:    chatID,
+			Category:  category.Dairy,
+			CreatedBy: callbackQueryMock.From.ID,
+			Price:     120,
+		}).Return(nil)
+		expectReply(t, item.Name)
+
+		err := handleAddCallbackQuery(clientMock, stMock, callbackQueryMock, "new=7,1.20")
+		if err != nil {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("Add anyway", func(t *testing.T) {
+		clientMock.EXPECT().AnswerCallbackQuery(gomock.Any())
+		stMock.EXPECT().GetShoppingItem(item.ID).Return(item, nil)
+		stMock.EXPECT().GetCategoryKeywords(chatID).Return(nil, nil)
+		stMock.EXPECT().AddShoppingItemIntoShoppingList(models.ShoppingItem{
+			Name:      item.Name,
+			ChatID:    chatID,
+			Category:  category.Dairy,
+			CreatedBy: callbackQueryMock.From.ID,
+		}).Return(nil)
+		expectReply(t, item.Name)
+
+		err := handleAddCallbackQuery(clientMock, stMock, callbackQueryMock, "new=7")
+		if err != nil {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+	})
+
+	t.Run("Item wasn't found", func(t *testing.T) {
+		otherChatItem := *item
+		otherChatItem.ChatID = 999
+
+		for _, foundItem := range []*models.ShoppingItem{nil, &otherChatItem} {
+			clientMock.EXPECT().AnswerCallbackQuery(gomock.Any())
+			stMock.EXPECT().GetShoppingItem(item.ID).Return(foundItem, nil)
+			expectReply(t, "Can't find this item")
+
+			err := handleAddCallbackQuery(clientMock, stMock, callbackQueryMock, "inc=7")
+			if err != nil {
+				t.Errorf("Unexpected error: %#v", err)
+			}
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		clientMock.EXPECT().AnswerCallbackQuery(gomock.Any())
+		stMock.EXPECT().GetShoppingItem(item.ID).Return(item, nil)
+		stMock.EXPECT().IncreaseShoppingItemQuantity(item.ID, 1).Return(errMock)
+
+		err := handleAddCallbackQuery(clientMock, stMock, callbackQueryMock, "inc=7")
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+}
+
+func TestHandleAddFrequentItems(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
+	stMock.EXPECT().AddUnfinishedCommand(gomock.Any()).Return(nil).AnyTimes()
+
+	// Common data mocks
+	chatID := int64(123)
+	expectSuggestions := func() {
+		stMock.EXPECT().GetShoppingItems(chatID).Return([]*models.ShoppingItem{
+			{ID: 1, Name: "milk"},
+		}, nil)
+		stMock.EXPECT().GetFrequentItems(chatID, frequentItemsLimit+1).Return(
+			[]*models.ItemHistoryEntry{
+				{ID: 10, Name: "Bread"},
+				{ID: 11, Name: "Milk"},
+				{ID: 12, Name: "Eggs"},
+				{ID: 13, Name: "Apples"},
+			}, nil)
+	}
+	expectedData := []string{"add:re=10", "add:re=12", "add:re=13"}
+
+	t.Run("Private chat", func(t *testing.T) {
+		messageMock := &tgbotapi.Message{
+			Chat: &tgbotapi.Chat{ID: chatID, Type: "private"},
+			From: &tgbotapi.User{ID: 321, FirstName: "m1kola"},
+		}
+
+		expectSuggestions()
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			keyboard, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+			if !ok {
+				t.Fatalf("Expected an inline keyboard, got %#v", msgCfg.ReplyMarkup)
+			}
+
+			data := keyboardCallbackData(keyboard)
+			if !reflect.DeepEqual(data, expectedData) {
+				t.Errorf("Expected callback data %v, got %v", expectedData, data)
+			}
+			if len(keyboard.InlineKeyboard) != 2 {
+				t.Errorf("Expected 2 rows, got %d", len(keyboard.InlineKeyboard))
+			}
+		})
+
+		err := handleAdd(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Group chat", func(t *testing.T) {
+		messageMock := &tgbotapi.Message{
+			Chat: &tgbotapi.Chat{ID: chatID, Type: "group"},
+			From: &tgbotapi.User{ID: 321, FirstName: "m1kola"},
+		}
+
+		expectSuggestions()
+		gomock.InOrder(
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				if _, ok := msgCfg.ReplyMarkup.(tgbotapi.ForceReply); !ok {
+					t.Errorf("Expected bot to force clients to reply, got %#v",
+						msgCfg.ReplyMarkup)
+				}
+			}),
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				keyboard, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+				if !ok {
+					t.Fatalf("Expected an inline keyboard, got %#v", msgCfg.ReplyMarkup)
+				}
+
+				data := keyboardCallbackData(keyboard)
+				if !reflect.DeepEqual(data, expectedData) {
+					t.Errorf("Expected callback data %v, got %v", expectedData, data)
+				}
+			}),
+		)
+
+		err := handleAdd(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
+
+func TestHandleAddCallbackQueryFrequentItem(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
+	clientMock.EXPECT().AnswerCallbackQuery(gomock.Any()).AnyTimes()
+	stMock.EXPECT().GetCategoryKeywords(gomock.Any()).Return(nil, nil).AnyTimes()
+
+	// Common data mocks
+	errMock := errors.New("Fake error")
+	chatID := int64(123)
+	callbackQueryMock := &tgbotapi.CallbackQuery{
+		ID:   "some-callback-id",
+		From: &tgbotapi.User{ID: 321},
+		Message: &tgbotapi.Message{
+			MessageID: 456,
+			Chat:      &tgbotapi.Chat{ID: chatID},
+		},
+	}
+	entry := &models.ItemHistoryEntry{ID: 10, ChatID: chatID, Name: "Bread"}
+
+	expectText := func(t *testing.T, expectedText string) {
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if !strings.Contains(msgCfg.Text, expectedText) {
+				t.Errorf("Expected message to contain %#v, got %#v",
+					expectedText, msgCfg.Text)
+			}
+		})
+	}
+
+	t.Run("Add an item", func(t *testing.T) {
+		stMock.EXPECT().GetItemHistoryEntry(entry.ID).Return(entry, nil)
+		stMock.EXPECT().DeleteUnfinishedCommand(chatID, 321).Return(nil)
+		stMock.EXPECT().GetShoppingItems(chatID).Return(nil, nil)
+		stMock.EXPECT().AddShoppingItemIntoShoppingList(gomock.Any()).Do(func(item models.ShoppingItem) {
+			if item.Name != "Bread" || item.ChatID != chatID || item.CreatedBy != 321 {
+				t.Errorf("Unexpected item %#v", item)
+			}
+		}).Return(nil)
+
+		// Suggestions are updated in place
+		stMock.EXPECT().GetShoppingItems(chatID).Return([]*models.ShoppingItem{
+			{ID: 1, Name: "Bread"},
+		}, nil)
+		stMock.EXPECT().GetFrequentItems(chatID, gomock.Any()).Return(
+			[]*models.ItemHistoryEntry{entry, {ID: 11, Name: "Milk"}}, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.EditMessageReplyMarkupConfig) {
+			data := keyboardCallbackData(*msgCfg.ReplyMarkup)
+			expectedData := []string{"add:re=11"}
+			if !reflect.DeepEqual(data, expectedData) {
+				t.Errorf("Expected callback data %v, got %v", expectedData, data)
+			}
+		})
+		expectText(t, `I've added "Bread"`)
+
+		err := handleAddCallbackQuery(clientMock, stMock, callbackQueryMock, "re=10")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("The last suggestion", func(t *testing.T) {
+		stMock.EXPECT().GetItemHistoryEntry(entry.ID).Return(entry, nil)
+		stMock.EXPECT().DeleteUnfinishedCommand(chatID, 321).Return(nil)
+		stMock.EXPECT().GetShoppingItems(chatID).Return(nil, nil)
+		stMock.EXPECT().AddShoppingItemIntoShoppingList(gomock.Any()).Return(nil)
+		stMock.EXPECT().GetShoppingItems(chatID).Return([]*models.ShoppingItem{
+			{ID: 1, Name: "Bread"},
+		}, nil)
+		stMock.EXPECT().GetFrequentItems(chatID, gomock.Any()).Return(
+			[]*models.ItemHistoryEntry{entry}, nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(
+			generateSendHideKeybaordCallChecker(t, callbackQueryMock))
+		expectText(t, `I've added "Bread"`)
+
+		err := handleAddCallbackQuery(clientMock, stMock, callbackQueryMock, "re=10")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Item is in the list already", func(t *testing.T) {
+		stMock.EXPECT().GetItemHistoryEntry(entry.ID).Return(entry, nil)
+		stMock.EXPECT().DeleteUnfinishedCommand(chatID, 321).Return(nil)
+		stMock.EXPECT().GetShoppingItems(chatID).Return([]*models.ShoppingItem{
+			{ID: 1, Name: "bread"},
+		}, nil)
+		expectText(t, `"bread" is already in your shopping list`)
+
+		err := handleAddCallbackQuery(clientMock, stMock, callbackQueryMock, "re=10")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Entry of another chat", func(t *testing.T) {
+		stMock.EXPECT().GetItemHistoryEntry(entry.ID).Return(
+			&models.ItemHistoryEntry{ID: 10, ChatID: 999, Name: "Bread"}, nil)
+		expectText(t, "Can't find this item")
+
+		err := handleAddCallbackQuery(clientMock, stMock, callbackQueryMock, "re=10")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().GetItemHistoryEntry(entry.ID).Return(nil, errMock)
+
+		err := handleAddCallbackQuery(clientMock, stMock, callbackQueryMock, "re=10")
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+}
+
+func TestHandleDedupe(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
+
+	// Common data mocks
+	errMock := errors.New("Fake error")
+	messageMock := &tgbotapi.Message{
+		Chat: &tgbotapi.Chat{ID: 123},
+		From: &tgbotapi.User{ID: 321},
+	}
+
+	expectText := func(t *testing.T, expectedText string) {
+		clientMock.EXPECT().Send(
+			gomock.Any(),
+		).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if msgCfg.Text != expectedText {
+				t.Errorf("Expected text %#v, got %#v", expectedText, msgCfg.Text)
 			}
+		})
+	}
+
+	t.Run("Duplicates merged", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(messageMock.Chat.ID).Return(
+			[]*models.ShoppingItem{
+				{ID: 1, Name: "Milk"},
+				{ID: 2, Name: "Bread"},
+				{ID: 3, Name: "milk"},
+				{ID: 4, Name: "Eggs"},
+				{ID: 5, Name: "egg"},
+				{ID: 6, Name: " Milks"},
+			}, nil)
+		gomock.InOrder(
+			stMock.EXPECT().MergeShoppingItems(int64(1), []int64{3, 6}).Return(nil),
+			stMock.EXPECT().MergeShoppingItems(int64(4), []int64{5}).Return(nil),
+		)
+		expectText(t, "Done! I've merged 3 duplicates into other items.")
+
+		err := handleDedupe(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
 		}
+	})
+
+	t.Run("No duplicates", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(messageMock.Chat.ID).Return(
+			[]*models.ShoppingItem{{ID: 1, Name: "Milk"}, {ID: 2, Name: "Bread"}}, nil)
+		expectText(t, "There are no duplicates in your shopping list.")
+
+		err := handleDedupe(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(messageMock.Chat.ID).Return(
+			[]*models.ShoppingItem{{ID: 1, Name: "Milk"}, {ID: 2, Name: "milk"}}, nil)
+		stMock.EXPECT().MergeShoppingItems(int64(1), []int64{2}).Return(errMock)
 
+		err := handleDedupe(clientMock, stMock, messageMock)
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
 	})
 }
 
@@ -402,8 +1177,9 @@ func TestHandleDel(t *testing.T) {
 	// Common interface mocks
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
-	clientMock := mock_telegram.NewMocksender(mockCtrl)
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
 	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
 
 	// Common data mocks
 	errMock := errors.New("fake error")
@@ -502,6 +1278,202 @@ func TestHandleDel(t *testing.T) {
 				t.Errorf("Unexpected err: got %#v", err)
 			}
 		})
+
+		t.Run("Item name in arguments", func(t *testing.T) {
+			// Data mocks
+			messageMock := mock_telegram.MessageCommandMockSetup(commandDel, "молоко")
+			messageMock.Chat = &tgbotapi.Chat{ID: 123}
+			storageDataMock := []*models.ShoppingItem{
+				{ID: 1, Name: "Milk"},
+				{ID: 2, Name: "Молоко"},
+			}
+
+			stMock.EXPECT().GetShoppingItems(gomock.Any()).Return(storageDataMock, nil)
+			stMock.EXPECT().DeleteShoppingItem(storageDataMock[1].ID).Return(nil)
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				expectedText := storageDataMock[1].Name
+				if !strings.Contains(msgCfg.Text, expectedText) {
+					t.Errorf("Expected message to contain %#v, got %#v",
+						expectedText, msgCfg.Text)
+				}
+			})
+
+			err := handleDel(clientMock, stMock, messageMock)
+			if err != nil {
+				t.Errorf("Unexpected err: got %#v", err)
+			}
+		})
+
+		t.Run("Unknown item name in arguments", func(t *testing.T) {
+			// Data mocks
+			messageMock := mock_telegram.MessageCommandMockSetup(commandDel, "bread")
+			messageMock.Chat = &tgbotapi.Chat{ID: 123}
+			storageDataMock := []*models.ShoppingItem{
+				{ID: 1, Name: "Milk"},
+			}
+
+			stMock.EXPECT().GetShoppingItems(gomock.Any()).Return(storageDataMock, nil)
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				expectedText := `can't find "bread"`
+				if !strings.Contains(msgCfg.Text, expectedText) {
+					t.Errorf("Expected message to contain %#v, got %#v",
+						expectedText, msgCfg.Text)
+				}
+
+				if _, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup); !ok {
+					t.Error("Expected message to contain inline keybaord")
+				}
+			})
+
+			err := handleDel(clientMock, stMock, messageMock)
+			if err != nil {
+				t.Errorf("Unexpected err: got %#v", err)
+			}
+		})
+
+		t.Run("Similar item names in arguments", func(t *testing.T) {
+			// We ask before deleting items which only look like the name
+			testCases := []struct {
+				args         string
+				items        []*models.ShoppingItem
+				expectedText string
+				expectedData []string
+			}{
+				{
+					args:         "bred",
+					items:        []*models.ShoppingItem{{ID: 1, Name: "Milk"}, {ID: 2, Name: "White bread"}},
+					expectedText: `I can't find "bred" exactly`,
+					expectedData: []string{"del:2"},
+				},
+				{
+					args:         "choc",
+					items:        []*models.ShoppingItem{{ID: 1, Name: "Dark chocolate"}},
+					expectedText: `I can't find "choc" exactly`,
+					expectedData: []string{"del:1"},
+				},
+				{
+					args:         "ham",
+					items:        []*models.ShoppingItem{{ID: 1, Name: "Shampoo"}},
+					expectedText: `can't find "ham"`,
+					expectedData: []string{"del:1"},
+				},
+				{
+					args:         "tea",
+					items:        []*models.ShoppingItem{{ID: 1, Name: "Steak"}},
+					expectedText: `can't find "tea"`,
+					expectedData: []string{"del:1"},
+				},
+			}
+
+			for _, testCase := range testCases {
+				t.Run(testCase.args, func(t *testing.T) {
+					// Data mocks
+					messageMock := mock_telegram.MessageCommandMockSetup(commandDel, testCase.args)
+					messageMock.Chat = &tgbotapi.Chat{ID: 123}
+
+					stMock.EXPECT().GetShoppingItems(gomock.Any()).Return(testCase.items, nil)
+					clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+						if !strings.Contains(msgCfg.Text, testCase.expectedText) {
+							t.Errorf("Expected message to contain %#v, got %#v",
+								testCase.expectedText, msgCfg.Text)
+						}
+
+						keyboard, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+						if !ok {
+							t.Fatal("Expected message to contain inline keybaord")
+						}
+
+						data := keyboardCallbackData(keyboard)
+						if !reflect.DeepEqual(data, testCase.expectedData) {
+							t.Errorf("Expected callback data %v, got %v",
+								testCase.expectedData, data)
+						}
+					})
+
+					err := handleDel(clientMock, stMock, messageMock)
+					if err != nil {
+						t.Errorf("Unexpected err: got %#v", err)
+					}
+				})
+			}
+		})
+
+		t.Run("Several items match arguments", func(t *testing.T) {
+			// Data mocks
+			messageMock := mock_telegram.MessageCommandMockSetup(commandDel, "milk")
+			messageMock.Chat = &tgbotapi.Chat{ID: 123}
+			storageDataMock := []*models.ShoppingItem{
+				{ID: 1, Name: "Oat milk"},
+				{ID: 2, Name: "Bread"},
+				{ID: 3, Name: "Soy milk"},
+			}
+
+			stMock.EXPECT().GetShoppingItems(gomock.Any()).Return(storageDataMock, nil)
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				expectedText := `several items like "milk"`
+				if !strings.Contains(msgCfg.Text, expectedText) {
+					t.Errorf("Expected message to contain %#v, got %#v",
+						expectedText, msgCfg.Text)
+				}
+
+				inlineKeyboardMarkup, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+				if !ok {
+					t.Fatal("Expected message to contain inline keybaord")
+				}
+
+				expectedCallbackData := []string{"del:1", "del:3"}
+				if len(inlineKeyboardMarkup.InlineKeyboard) != len(expectedCallbackData) {
+					t.Fatalf("Expected %d rows, got %d",
+						len(expectedCallbackData), len(inlineKeyboardMarkup.InlineKeyboard))
+				}
+				for i, row := range inlineKeyboardMarkup.InlineKeyboard {
+					if *row[0].CallbackData != expectedCallbackData[i] {
+						t.Errorf("Expected callback data %#v, got %#v",
+							expectedCallbackData[i], *row[0].CallbackData)
+					}
+				}
+			})
+
+			err := handleDel(clientMock, stMock, messageMock)
+			if err != nil {
+				t.Errorf("Unexpected err: got %#v", err)
+			}
+		})
+
+		t.Run("Several item names in arguments", func(t *testing.T) {
+			// Data mocks
+			messageMock := mock_telegram.MessageCommandMockSetup(
+				commandDel, "milk, eggs , bread,")
+			messageMock.Chat = &tgbotapi.Chat{ID: 123}
+			storageDataMock := []*models.ShoppingItem{
+				{ID: 1, Name: "Milk"},
+				{ID: 2, Name: "Eggs"},
+				{ID: 3, Name: "Milk"},
+			}
+
+			stMock.EXPECT().GetShoppingItems(gomock.Any()).Return(storageDataMock, nil)
+			stMock.EXPECT().DeleteShoppingItem(storageDataMock[0].ID).Return(nil)
+			stMock.EXPECT().DeleteShoppingItem(storageDataMock[1].ID).Return(nil)
+			gomock.InOrder(
+				clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+					expectedText := "I've removed 2 items from your shopping list: Milk, Eggs."
+					if msgCfg.Text != expectedText {
+						t.Errorf("Expected text %#v, got %#v", expectedText, msgCfg.Text)
+					}
+				}),
+				clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+					expectedText := "Sorry, I can't find these items: bread."
+					if msgCfg.Text != expectedText {
+						t.Errorf("Expected text %#v, got %#v", expectedText, msgCfg.Text)
+					}
+				}),
+			)
+
+			err := handleDel(clientMock, stMock, messageMock)
+			if err != nil {
+				t.Errorf("Unexpected err: got %#v", err)
+			}
+		})
 	})
 }
 
@@ -511,6 +1483,7 @@ func TestHandleDelCallbackQuery(t *testing.T) {
 	defer mockCtrl.Finish()
 	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
 	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
 
 	// Common data mocks
 	errMock := errors.New("Fake error")
@@ -675,6 +1648,34 @@ func TestHandleDelCallbackQuery(t *testing.T) {
 			}
 		})
 
+		t.Run("Unable to hide the keyboard", func(t *testing.T) {
+			// Data mocks
+			sendErrMock := tgbotapi.Error{Message: "Bad Request: message can't be edited"}
+
+			// Interface mocks
+			clientMock.EXPECT().AnswerCallbackQuery(
+				gomock.Any(),
+			).Do(generateCallbackQueryIDChecker(t))
+			stMock.EXPECT().GetShoppingItem(expectedItemID).Return(item, nil)
+			stMock.EXPECT().DeleteShoppingItem(expectedItemID).Return(nil)
+
+			gomock.InOrder(
+				clientMock.EXPECT().Send(gomock.Any()).Return(tgbotapi.Message{}, sendErrMock),
+				clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+					if !strings.Contains(msgCfg.Text, item.Name) {
+						t.Errorf(
+							"Expected message to contain the item name: %#v, got: %#v",
+							item.Name, msgCfg.Text,
+						)
+					}
+				}),
+			)
+
+			err := handleDelCallbackQuery(clientMock, stMock, callbackQueryMock, dataMock)
+			if err != (keyboardError{sendErrMock}) {
+				t.Errorf("Expected keyboardError, got %#v", err)
+			}
+		})
 	})
 }
 
@@ -682,8 +1683,9 @@ func TestHandleClear(t *testing.T) {
 	// Common interface mocks
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
-	clientMock := mock_telegram.NewMocksender(mockCtrl)
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
 	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
 
 	// Common data mocks
 	errMock := errors.New("fake error")
@@ -729,6 +1731,45 @@ func TestHandleClear(t *testing.T) {
 			}
 		})
 
+		t.Run("Without confirmation", func(t *testing.T) {
+			// Interface mocks
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+			// Data mocks
+			settingsMock := models.NewChatSettings(messageMock.Chat.ID)
+			settingsMock.ConfirmClear = false
+			storageDataMock := []*models.ShoppingItem{
+				{ID: 1, Name: "Milk"},
+			}
+
+			stMock.EXPECT().GetUserLocale(gomock.Any()).Return("en", nil)
+			stMock.EXPECT().GetShoppingItems(messageMock.Chat.ID).Return(storageDataMock, nil)
+			stMock.EXPECT().GetChatSettings(messageMock.Chat.ID).Return(settingsMock, nil)
+			stMock.EXPECT().DeleteAllShoppingItems(messageMock.Chat.ID).Return(nil)
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				expectedText := "deleted all items"
+				if !strings.Contains(msgCfg.Text, expectedText) {
+					t.Errorf("Expected message to contain %#v, got %#v",
+						expectedText, msgCfg.Text)
+				}
+
+				if msgCfg.ReplyMarkup != nil {
+					t.Errorf("Expected no keyboard, got %#v", msgCfg.ReplyMarkup)
+				}
+			})
+
+			messageMock := &tgbotapi.Message{
+				From: &tgbotapi.User{ID: 321},
+				Chat: messageMock.Chat,
+			}
+			err := handleClear(clientMock, stMock, messageMock)
+			if err != nil {
+				t.Errorf("Unexpected err: got %#v", err)
+			}
+		})
+
 		t.Run("Shopping list with items", func(t *testing.T) {
 			// Data mocks
 			storageDataMock := []*models.ShoppingItem{
@@ -793,6 +1834,7 @@ func TestHandleClearCallbackQuery(t *testing.T) {
 	defer mockCtrl.Finish()
 	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
 	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
 
 	// Common data mocks
 	errMock := errors.New("Fake error")
@@ -940,6 +1982,44 @@ func TestHandleClearCallbackQuery(t *testing.T) {
 	})
 }
 
+func TestHideInlineKeyboard(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+
+	testCases := []struct {
+		testName    string
+		sendErr     error
+		expectedErr error
+	}{
+		{
+			testName: "Success",
+		},
+		{
+			testName:    "Send error",
+			sendErr:     tgbotapi.Error{Message: "Bad Request: chat not found"},
+			expectedErr: keyboardError{tgbotapi.Error{Message: "Bad Request: chat not found"}},
+		},
+		{
+			testName: "Message is not modified",
+			sendErr:  tgbotapi.Error{Message: "Bad Request: message is not modified"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			clientMock.EXPECT().Send(gomock.Any()).Return(tgbotapi.Message{}, testCase.sendErr)
+
+			err := hideInlineKeyboard(clientMock, 123, 321)
+
+			if err != testCase.expectedErr {
+				t.Errorf("Expected err %#v, got %#v", testCase.expectedErr, err)
+			}
+		})
+	}
+}
+
 // --- Utils
 
 func generateSendHideKeybaordCallChecker(
@@ -965,3 +2045,160 @@ func generateSendHideKeybaordCallChecker(
 		}
 	}
 }
+
+func TestHandleLanguage(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+	// Common data mocks
+	errMock := errors.New("Fake error")
+	messageMock := &tgbotapi.Message{
+		From: &tgbotapi.User{ID: 321, LanguageCode: "en-US"},
+		Chat: &tgbotapi.Chat{ID: 123},
+		Text: "/language",
+		Entities: &[]tgbotapi.MessageEntity{
+			{Type: "bot_command", Offset: 0, Length: 9},
+		},
+	}
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().GetUserLocale(messageMock.From.ID).Return("", errMock)
+
+		err := handleLanguage(clientMock, stMock, messageMock)
+		if !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+
+	t.Run("Keyboard", func(t *testing.T) {
+		stMock.EXPECT().GetUserLocale(messageMock.From.ID).Return("", nil)
+		stMock.EXPECT().GetChatSettings(messageMock.Chat.ID).Return(
+			models.NewChatSettings(messageMock.Chat.ID), nil)
+
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			if msgCfg.ChatID != messageMock.Chat.ID {
+				t.Errorf(
+					"Expected to reply to the chat with ID %d, but reply sent to %d",
+					messageMock.Chat.ID, msgCfg.ChatID,
+				)
+			}
+
+			markup, ok := msgCfg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+			if !ok {
+				t.Fatalf("Expected inline keyboard, got %#v", msgCfg.ReplyMarkup)
+			}
+
+			if len(markup.InlineKeyboard) != len(i18n.Locales()) {
+				t.Fatalf("Expected %d buttons, got %d",
+					len(i18n.Locales()), len(markup.InlineKeyboard))
+			}
+
+			for i, locale := range i18n.Locales() {
+				button := markup.InlineKeyboard[i][0]
+				expectedData := joinCallbackQueryData(commandLanguage, locale)
+				if *button.CallbackData != expectedData {
+					t.Errorf("Expected callback data %#v, got %#v",
+						expectedData, *button.CallbackData)
+				}
+			}
+		})
+
+		err := handleLanguage(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Locale in arguments", func(t *testing.T) {
+		// Data mocks
+		messageMock := &tgbotapi.Message{
+			From: messageMock.From,
+			Chat: messageMock.Chat,
+			Text: "/language uk",
+			Entities: &[]tgbotapi.MessageEntity{
+				{Type: "bot_command", Offset: 0, Length: 9},
+			},
+		}
+
+		stMock.EXPECT().SetUserLocale(messageMock.From.ID, "uk").Return(nil)
+		clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+			expectedText := i18n.NewTranslator("uk").T("language.done")
+			if msgCfg.Text != expectedText {
+				t.Errorf("Expected %#v, got %#v", expectedText, msgCfg.Text)
+			}
+		})
+
+		err := handleLanguage(clientMock, stMock, messageMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
+
+func TestHandleLanguageCallbackQuery(t *testing.T) {
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+	// Common data mocks
+	errMock := errors.New("Fake error")
+	callbackQueryMock := &tgbotapi.CallbackQuery{
+		ID:   "some-callback-id",
+		From: &tgbotapi.User{ID: 321},
+		Message: &tgbotapi.Message{
+			MessageID: 123,
+			Chat:      &tgbotapi.Chat{ID: 123},
+		},
+	}
+
+	t.Run("Callback data parsing error", func(t *testing.T) {
+		clientMock.EXPECT().AnswerCallbackQuery(gomock.Any())
+
+		err := handleLanguageCallbackQuery(clientMock, stMock, callbackQueryMock, "xx")
+
+		expectedErrorText := "Unable to parse a locale"
+		if err == nil || !strings.Contains(err.Error(), expectedErrorText) {
+			t.Errorf("Expected error to contain %#v, got %#v",
+				expectedErrorText, err)
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		clientMock.EXPECT().AnswerCallbackQuery(gomock.Any())
+		clientMock.EXPECT().Send(gomock.Any()).Do(
+			generateSendHideKeybaordCallChecker(t, callbackQueryMock),
+		)
+		stMock.EXPECT().SetUserLocale(callbackQueryMock.From.ID, "uk").Return(errMock)
+
+		err := handleLanguageCallbackQuery(clientMock, stMock, callbackQueryMock, "uk")
+		if err == nil || !strings.Contains(err.Error(), errMock.Error()) {
+			t.Errorf("Expected err %#v, got %#v", errMock, err)
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		clientMock.EXPECT().AnswerCallbackQuery(gomock.Any())
+		gomock.InOrder(
+			clientMock.EXPECT().Send(gomock.Any()).Do(
+				generateSendHideKeybaordCallChecker(t, callbackQueryMock),
+			),
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				expectedText := i18n.NewTranslator("uk").T("language.done")
+				if msgCfg.Text != expectedText {
+					t.Errorf("Expected %#v, got %#v", expectedText, msgCfg.Text)
+				}
+			}),
+		)
+		stMock.EXPECT().SetUserLocale(callbackQueryMock.From.ID, "uk").Return(nil)
+
+		err := handleLanguageCallbackQuery(clientMock, stMock, callbackQueryMock, "uk")
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+}
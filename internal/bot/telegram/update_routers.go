@@ -3,10 +3,12 @@ package telegram
 import (
 	"errors"
 	"fmt"
-	"log"
+	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 
+	"github.com/m1kola/shipsterbot/internal/pkg/logging"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
 	"github.com/m1kola/shipsterbot/internal/pkg/storage"
 )
 
@@ -14,10 +16,11 @@ import (
 func routeUpdates(
 	client botClientInterface,
 	st storage.DataStorageInterface,
+	logger *logging.Logger,
 	updates <-chan tgbotapi.Update,
 ) {
 	for update := range updates {
-		go routeUpdate(client, st, update)
+		go routeUpdate(client, st, logger, update)
 	}
 }
 
@@ -25,29 +28,139 @@ func routeUpdates(
 var routeUpdate = func(
 	client botClientInterface,
 	st storage.DataStorageInterface,
+	logger *logging.Logger,
 	update tgbotapi.Update,
 ) {
 	var err error
 	var message *tgbotapi.Message
+	var from *tgbotapi.User
+
+	logger = logger.With(updateLogKeyvals(update)...)
 
 	if update.CallbackQuery != nil {
 		message = update.CallbackQuery.Message
+		from = update.CallbackQuery.From
 
-		err = routeCallbackQuery(client, st, update.CallbackQuery)
+		logger.Info("CallbackQuery received")
+		markChatActive(st, logger, message)
+		client = callbackQueryAnswerLogger{client, logger}
+		err = routeCallbackQuery(client, withActor(st, from), update.CallbackQuery)
 	} else if update.Message != nil {
 		message = update.Message
+		from = message.From
+
+		markChatActive(st, logger, message)
+		err = routeMessage(client, withActor(st, from), logger, message)
+	} else if update.InlineQuery != nil {
+		from = update.InlineQuery.From
 
-		err = routeMessage(client, st, message)
+		logger.Info("InlineQuery received")
+		err = routeInlineQuery(client, withActor(st, from), update.InlineQuery)
+	} else if update.ChosenInlineResult != nil {
+		from = update.ChosenInlineResult.From
+
+		logger.Info("ChosenInlineResult received")
+		err = routeChosenInlineResult(client, withActor(st, from), update.ChosenInlineResult)
 	}
 
 	if err != nil {
-		routeErrors(client, message, err)
+		routeErrors(client, st, logger, from, message, err)
+	}
+}
+
+// markChatActive marks a chat as active, because we've received
+// an update from it. For example, an user could unblock the bot
+func markChatActive(
+	st storage.DataStorageInterface,
+	logger *logging.Logger,
+	message *tgbotapi.Message,
+) {
+	if message == nil || message.Chat == nil {
+		return
+	}
+
+	if err := st.MarkChatActive(message.Chat.ID); err != nil {
+		logger.Error("Unable to mark the chat as active", "err", err)
+	}
+}
+
+// callbackQueryAnswerLogger logs errors of answers to callback queries.
+// Handlers don't check them: users only see a loading indicator
+// for a bit longer, if we fail to answer
+type callbackQueryAnswerLogger struct {
+	botClientInterface
+	logger *logging.Logger
+}
+
+func (c callbackQueryAnswerLogger) AnswerCallbackQuery(
+	config tgbotapi.CallbackConfig,
+) (tgbotapi.APIResponse, error) {
+	resp, err := c.botClientInterface.AnswerCallbackQuery(config)
+	if err != nil {
+		c.logger.Warn("Unable to answer the callback query", "err", err)
+	}
+	return resp, err
+}
+
+// withActor returns a storage which records changes
+// of shopping lists made by the user into the audit log
+func withActor(
+	st storage.DataStorageInterface,
+	from *tgbotapi.User,
+) storage.DataStorageInterface {
+	actor := models.Actor{}
+	if from != nil {
+		actor.ID = from.ID
+		actor.Name = userName(from)
+	}
+	return storage.WithAuditLog(st, actor)
+}
+
+// userName returns a full name of an user or
+// the username, if the user doesn't have a name
+func userName(user *tgbotapi.User) string {
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if name == "" {
+		name = user.UserName
 	}
+	return name
 }
 
-// routeErrors handles errors that occur during user interactions with the bot
+// updateLogKeyvals returns keys and values that identify
+// an update in log records
+func updateLogKeyvals(update tgbotapi.Update) []interface{} {
+	keyvals := []interface{}{"update_id", update.UpdateID}
+
+	var message *tgbotapi.Message
+	var command string
+	if update.CallbackQuery != nil {
+		message = update.CallbackQuery.Message
+		command, _, _ = splitCallbackQueryData(update.CallbackQuery.Data)
+	} else if update.Message != nil {
+		message = update.Message
+		command = message.Command()
+	} else if update.ChosenInlineResult != nil {
+		command = update.ChosenInlineResult.ResultID
+	}
+
+	if message != nil && message.Chat != nil {
+		keyvals = append(keyvals, "chat_id", message.Chat.ID)
+	}
+
+	if command != "" {
+		keyvals = append(keyvals, "command", command)
+	}
+
+	return keyvals
+}
+
+// routeErrors handles errors that occur during user interactions with the bot.
+// Replies are sent in the language of the user `from`
 var routeErrors = func(
 	client botClientInterface,
+	st storage.DataStorageInterface,
+	logger *logging.Logger,
+	from *tgbotapi.User,
 	message *tgbotapi.Message,
 	err error,
 ) {
@@ -56,25 +169,98 @@ var routeErrors = func(
 		return
 	}
 
-	log.Print(err)
+	// Handlers have replied already, so an user
+	// only sees a keyboard which doesn't work anymore
+	if _, ok := err.(keyboardError); ok {
+		logger.Warn("Unable to update the inline keyboard", "err", err)
+		return
+	}
+
+	// Inline queries and their results don't have a chat,
+	// so we don't have where to reply
+	if message == nil || message.Chat == nil {
+		if _, ok := err.(updateRoutingError); ok {
+			logger.Info("Unable to route the update", "err", err)
+		} else {
+			logger.Error("Unable to handle the update", "err", err)
+		}
+		return
+	}
+
+	tr, trErr := userTranslator(st, message.Chat.ID, from)
+	if trErr != nil {
+		// We still can reply using the language of the user's client
+		logger.Error("Unable to get the user's translator", "err", trErr)
+	}
 
 	// It's ok if we can't handle a message, because an user can send nonsense.
 	// Let's send a message saying that we don't understand the input.
 	if _, ok := err.(updateRoutingError); ok {
+		logger.Info("Unable to route the update", "err", err)
+
 		if len(message.Text) > 0 {
 			// Send help text only if we've received a message text: we don't
 			// want to reply to service messages
 			// when people added or removed from the group, etc.
 
-			log.Print("No supported bot commands found")
-			sendHelpMessage(client, message, false)
+			logger.Info("No supported bot commands found")
+			if err := sendHelpMessage(client, tr, message, false); err != nil {
+				handleAPIError(st, logger, message.Chat.ID, err)
+			}
 		}
 		return
 	}
 
+	if pErr, ok := err.(panicError); ok {
+		logger = logger.With("stack", string(pErr.stack))
+	}
+
 	// Other types of error mean that we are in trouble
 	// and we need to do something with it
-	handleUnrecoverableError(client, message.Chat.ID, err)
+	if !handleAPIError(st, logger, message.Chat.ID, err) {
+		return
+	}
+
+	if err := handleUnrecoverableError(client, tr, message.Chat.ID, err); err != nil {
+		logger.Error("Unable to send the error message", "err", err)
+	}
+}
+
+// handleAPIError logs an error and reacts to errors
+// returned by the Telegram Bot API.
+//
+// It returns false, if it doesn't make sense to reply into the chat:
+// for example, when the bot was blocked by an user.
+func handleAPIError(
+	st storage.DataStorageInterface,
+	logger *logging.Logger,
+	chatID int64,
+	err error,
+) (canReply bool) {
+	kind := classifyAPIError(err)
+	logger = logger.With("err", err, "err_kind", kind)
+
+	switch kind {
+	case apiErrorBlockedByUser, apiErrorChatNotFound:
+		logger.Warn("The chat is not available anymore")
+
+		if err := st.MarkChatInactive(chatID); err != nil {
+			logger.Error("Unable to mark the chat as inactive",
+				"storage_err", err)
+		}
+		return false
+	case apiErrorFloodWait:
+		// There is no point in sending more messages right now
+		retryAfter := err.(tgbotapi.Error).RetryAfter
+		logger.Warn("Rate limit exceeded", "retry_after", retryAfter)
+		return false
+	case apiErrorBadRequest:
+		logger.Error("Telegram Bot API rejected the request")
+		return true
+	}
+
+	logger.Error("Unable to handle the update")
+	return true
 }
 
 // routeCallbackQuery routes callback queries to specific handlers
@@ -99,19 +285,72 @@ var routeCallbackQuery = func(
 				callbackQuery.Data)}
 	}
 
+	// Callback queries from inline messages don't have a chat,
+	// so chat permissions don't apply to them
+	if callbackQuery.Message != nil {
+		allowed, err := hasPermission(client, st,
+			callbackQuery.Message.Chat, callbackQuery.From, i.permission)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return denyCallbackQuery(client, st, callbackQuery)
+		}
+	}
+
 	return i.callbackQueryHandler(client, st, callbackQuery, payload)
 }
 
+// routeInlineQuery routes inline queries: users type
+// `@botname milk` in any chat to use the bot
+var routeInlineQuery = func(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	inlineQuery *tgbotapi.InlineQuery,
+) error {
+	handler := inlineQueryHandlerFunc(handleInlineQuery)
+	for i := len(globalMiddlewares) - 1; i >= 0; i-- {
+		handler = globalMiddlewares[i].wrapInlineQueryHandler(handler)
+	}
+	return handler(client, st, inlineQuery)
+}
+
+// routeChosenInlineResult routes inline query results which users chose
+// to handlers of commands. IDs of results are names of commands.
+//
+// Telegram only sends chosen results, if inline feedback
+// is enabled for the bot
+var routeChosenInlineResult = func(
+	client botClientInterface,
+	st storage.DataStorageInterface,
+	chosenInlineResult *tgbotapi.ChosenInlineResult,
+) error {
+	i, ok := getBotCommandsMapping()[chosenInlineResult.ResultID]
+	if !ok || i.chosenInlineResultHandler == nil {
+		// Some results, like the shared shopping list,
+		// don't require any actions
+		return updateRoutingError{
+			fmt.Errorf("Unable to find a handler for ChosenInlineResult: %v",
+				chosenInlineResult.ResultID)}
+	}
+
+	return i.chosenInlineResultHandler(client, st, chosenInlineResult)
+}
+
 // routeMessage routes text messages
 //
 // Messages can contain entities in some cases (commands, mentions, etc),
-// which should be handled separately
+// which should be handled separately. Plain messages in private chats
+// which don't continue unfinished commands are routed by their intents
 var routeMessage = func(
-	client sender,
+	client botClientInterface,
 	st storage.DataStorageInterface,
+	logger *logging.Logger,
 	message *tgbotapi.Message,
 ) error {
-	log.Printf("Message received: \"%s\"", message.Text)
+	// Message text is user content, so we only log it in the debug mode
+	logger.Info("Message received")
+	logger.Debug("Message text", "text", message.Text)
 
 	err := routeMessageEntities(client, st, message)
 	// We should only try to continue processing an message,
@@ -126,7 +365,11 @@ var routeMessage = func(
 		return err
 	}
 
-	return routeMessageText(client, st, message)
+	err = routeMessageText(client, st, logger, message)
+	if _, ok := err.(updateRoutingError); ok && message.Chat != nil && message.Chat.IsPrivate() {
+		return routeMessageIntent(client, st, logger, message)
+	}
+	return err
 }
 
 // routeMessageEntities routes message to a specific handler
@@ -135,7 +378,7 @@ var routeMessage = func(
 // receive mentions and orher entities.
 // Everything other than command need to be ignored
 var routeMessageEntities = func(
-	client sender,
+	client botClientInterface,
 	st storage.DataStorageInterface,
 	message *tgbotapi.Message,
 ) error {
@@ -150,6 +393,15 @@ var routeMessageEntities = func(
 		return errCommandIsNotSupported
 	}
 
+	allowed, err := hasPermission(client, st,
+		message.Chat, message.From, i.permission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return denyCommand(client, st, message)
+	}
+
 	return i.commandHandler(client, st, message)
 }
 
@@ -160,8 +412,9 @@ var routeMessageEntities = func(
 // but in some cases we need to handle message text.
 // For example, when user asks us to add an item into the shopping list
 var routeMessageText = func(
-	client sender,
+	client botClientInterface,
 	st storage.DataStorageInterface,
+	logger *logging.Logger,
 	message *tgbotapi.Message,
 ) error {
 	session, err := st.GetUnfinishedCommand(message.Chat.ID,
@@ -195,5 +448,18 @@ var routeMessageText = func(
 			message.Chat.ID, message.From.ID, err)
 	}
 
+	logger.Info("Unfinished command found", "command", session.Command)
+
+	// Admins could turn the "admins only" policy on or
+	// demote the user, since the user started the command
+	allowed, err := hasPermission(client, st,
+		message.Chat, message.From, i.permission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return denyCommand(client, st, message)
+	}
+
 	return i.unfinishedCommandHandler(client, st, message)
 }
@@ -1,20 +1,27 @@
 package telegram
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"strings"
 	"testing"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 	"github.com/golang/mock/gomock"
 
+	"github.com/m1kola/shipsterbot/internal/pkg/i18n"
+	"github.com/m1kola/shipsterbot/internal/pkg/logging"
 	"github.com/m1kola/shipsterbot/internal/pkg/mocks/bot/mock_telegram"
 	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
 	"github.com/m1kola/shipsterbot/internal/pkg/models"
 	"github.com/m1kola/shipsterbot/internal/pkg/storage"
 )
 
+// discardLogger is a logger for tests that don't check log records
+var discardLogger = logging.New(ioutil.Discard, logging.LevelDebug)
+
 func TestRouteUpdates(t *testing.T) {
 	// Interface mocks
 	mockCtrl := gomock.NewController(t)
@@ -27,7 +34,7 @@ func TestRouteUpdates(t *testing.T) {
 	routeUpdateOld := routeUpdate
 	defer func() { routeUpdate = routeUpdateOld }()
 	routeUpdate = func(
-		botClientInterface, storage.DataStorageInterface, tgbotapi.Update,
+		botClientInterface, storage.DataStorageInterface, *logging.Logger, tgbotapi.Update,
 	) {
 		routeUpdateIsCalled <- true
 	}
@@ -35,7 +42,7 @@ func TestRouteUpdates(t *testing.T) {
 	updates := make(chan tgbotapi.Update)
 	defer close(updates)
 
-	go routeUpdates(clientMock, stMock, updates)
+	go routeUpdates(clientMock, stMock, discardLogger, updates)
 
 	updates <- tgbotapi.Update{}
 	if !<-routeUpdateIsCalled {
@@ -74,7 +81,7 @@ func TestRouteUpdate(t *testing.T) {
 			return nil
 		}
 
-		routeUpdate(clientMock, stMock, updateMock)
+		routeUpdate(clientMock, stMock, discardLogger, updateMock)
 
 		if !routeCallbackQueryIsCalled {
 			t.Error("func handleCallbackQuery wasn't called")
@@ -93,8 +100,9 @@ func TestRouteUpdate(t *testing.T) {
 		routeMessageOld := routeMessage
 		defer func() { routeMessage = routeMessageOld }()
 		routeMessage = func(
-			_ sender,
+			_ botClientInterface,
 			_ storage.DataStorageInterface,
+			_ *logging.Logger,
 			message *tgbotapi.Message,
 		) error {
 			routeMessageIsCalled = true
@@ -105,13 +113,76 @@ func TestRouteUpdate(t *testing.T) {
 			return nil
 		}
 
-		routeUpdate(clientMock, stMock, updateMock)
+		routeUpdate(clientMock, stMock, discardLogger, updateMock)
 
 		if !routeMessageIsCalled {
 			t.Error("func handleCallbackQuery wasn't called")
 		}
 	})
 
+	t.Run("InlineQuery update", func(t *testing.T) {
+		// Data mocks
+		inlineQueryMock := &tgbotapi.InlineQuery{}
+		updateMock := tgbotapi.Update{
+			InlineQuery: inlineQueryMock,
+		}
+
+		// Function mocks
+		routeInlineQueryIsCalled := false
+		routeInlineQueryOld := routeInlineQuery
+		defer func() { routeInlineQuery = routeInlineQueryOld }()
+		routeInlineQuery = func(
+			_ botClientInterface,
+			_ storage.DataStorageInterface,
+			inlineQuery *tgbotapi.InlineQuery,
+		) error {
+			routeInlineQueryIsCalled = true
+			if inlineQuery != inlineQueryMock {
+				t.Error("Wrong InlineQuery received")
+			}
+
+			// Errors can't be sent anywhere, so they are only logged
+			return errors.New("Fake error")
+		}
+
+		routeUpdate(clientMock, stMock, discardLogger, updateMock)
+
+		if !routeInlineQueryIsCalled {
+			t.Error("func routeInlineQuery wasn't called")
+		}
+	})
+
+	t.Run("ChosenInlineResult update", func(t *testing.T) {
+		// Data mocks
+		chosenInlineResultMock := &tgbotapi.ChosenInlineResult{}
+		updateMock := tgbotapi.Update{
+			ChosenInlineResult: chosenInlineResultMock,
+		}
+
+		// Function mocks
+		routeChosenInlineResultIsCalled := false
+		routeChosenInlineResultOld := routeChosenInlineResult
+		defer func() { routeChosenInlineResult = routeChosenInlineResultOld }()
+		routeChosenInlineResult = func(
+			_ botClientInterface,
+			_ storage.DataStorageInterface,
+			chosenInlineResult *tgbotapi.ChosenInlineResult,
+		) error {
+			routeChosenInlineResultIsCalled = true
+			if chosenInlineResult != chosenInlineResultMock {
+				t.Error("Wrong ChosenInlineResult received")
+			}
+
+			return updateRoutingError{errors.New("Fake error")}
+		}
+
+		routeUpdate(clientMock, stMock, discardLogger, updateMock)
+
+		if !routeChosenInlineResultIsCalled {
+			t.Error("func routeChosenInlineResult wasn't called")
+		}
+	})
+
 	t.Run("CallbackQuery update error", func(t *testing.T) {
 		// Data mocks
 		errMock := errors.New("Fake error")
@@ -120,8 +191,10 @@ func TestRouteUpdate(t *testing.T) {
 				ID: 123,
 			},
 		}
+		fromMock := &tgbotapi.User{ID: 321}
 		updateMock := tgbotapi.Update{
 			CallbackQuery: &tgbotapi.CallbackQuery{
+				From:    fromMock,
 				Message: messageMock,
 			},
 		}
@@ -133,16 +206,26 @@ func TestRouteUpdate(t *testing.T) {
 			return errMock
 		}
 
+		// Interface mocks
+		stMock.EXPECT().MarkChatActive(messageMock.Chat.ID).Return(nil)
+
 		routeErrorsIsCalled := false
 		routeErrorsOld := routeErrors
 		defer func() { routeErrors = routeErrorsOld }()
 		routeErrors = func(
 			_ botClientInterface,
+			_ storage.DataStorageInterface,
+			_ *logging.Logger,
+			actualFrom *tgbotapi.User,
 			actualMessage *tgbotapi.Message,
 			actualErr error,
 		) {
 			routeErrorsIsCalled = true
 
+			if actualFrom != fromMock {
+				t.Errorf("got %#v, expected %#v", actualFrom, fromMock)
+			}
+
 			if actualMessage != messageMock {
 				t.Errorf("got %#v, expected %#v", actualMessage, messageMock)
 			}
@@ -152,7 +235,7 @@ func TestRouteUpdate(t *testing.T) {
 			}
 		}
 
-		routeUpdate(clientMock, stMock, updateMock)
+		routeUpdate(clientMock, stMock, discardLogger, updateMock)
 
 		if !routeErrorsIsCalled {
 			t.Error("func routeErrors wasn't called")
@@ -163,10 +246,12 @@ func TestRouteUpdate(t *testing.T) {
 		// Data mocks
 		errMock := errors.New("Fake error")
 		messageMock := &tgbotapi.Message{
+			From: &tgbotapi.User{ID: 321},
 			Chat: &tgbotapi.Chat{
 				ID: 123,
 			},
 		}
+		fromMock := messageMock.From
 		updateMock := tgbotapi.Update{
 			Message: messageMock,
 		}
@@ -174,20 +259,35 @@ func TestRouteUpdate(t *testing.T) {
 		// Function mocks
 		routeMessageOld := routeMessage
 		defer func() { routeMessage = routeMessageOld }()
-		routeMessage = func(_ sender, _ storage.DataStorageInterface, _ *tgbotapi.Message) error {
+		routeMessage = func(
+			_ botClientInterface,
+			_ storage.DataStorageInterface,
+			_ *logging.Logger,
+			_ *tgbotapi.Message,
+		) error {
 			return errMock
 		}
 
+		// Interface mocks
+		stMock.EXPECT().MarkChatActive(messageMock.Chat.ID).Return(nil)
+
 		routeErrorsIsCalled := false
 		routeErrorsOld := routeErrors
 		defer func() { routeErrors = routeErrorsOld }()
 		routeErrors = func(
 			_ botClientInterface,
+			_ storage.DataStorageInterface,
+			_ *logging.Logger,
+			actualFrom *tgbotapi.User,
 			actualMessage *tgbotapi.Message,
 			actualErr error,
 		) {
 			routeErrorsIsCalled = true
 
+			if actualFrom != fromMock {
+				t.Errorf("got %#v, expected %#v", actualFrom, fromMock)
+			}
+
 			if actualMessage != messageMock {
 				t.Errorf("got %#v, expected %#v", actualMessage, messageMock)
 			}
@@ -197,7 +297,7 @@ func TestRouteUpdate(t *testing.T) {
 			}
 		}
 
-		routeUpdate(clientMock, stMock, updateMock)
+		routeUpdate(clientMock, stMock, discardLogger, updateMock)
 
 		if !routeErrorsIsCalled {
 			t.Error("func routeErrors wasn't called")
@@ -205,13 +305,111 @@ func TestRouteUpdate(t *testing.T) {
 	})
 }
 
+func TestCallbackQueryAnswerLogger(t *testing.T) {
+	// Interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+
+	// Data mocks
+	errMock := errors.New("fake error")
+	configMock := tgbotapi.NewCallback("some-callback-id", "")
+
+	var buf bytes.Buffer
+	client := callbackQueryAnswerLogger{clientMock, logging.New(&buf, logging.LevelDebug)}
+
+	clientMock.EXPECT().AnswerCallbackQuery(configMock).Return(tgbotapi.APIResponse{}, errMock)
+	if _, err := client.AnswerCallbackQuery(configMock); err != errMock {
+		t.Errorf("Expected err %#v, got %#v", errMock, err)
+	}
+
+	if !strings.Contains(buf.String(), "fake error") {
+		t.Errorf("Expected the error to be logged, got %#v", buf.String())
+	}
+}
+
+func TestUpdateLogKeyvals(t *testing.T) {
+	testCases := []struct {
+		testName string
+		update   tgbotapi.Update
+		expected []interface{}
+	}{
+		{
+			testName: "Empty update",
+			update:   tgbotapi.Update{UpdateID: 1},
+			expected: []interface{}{"update_id", 1},
+		},
+		{
+			testName: "Message with a command",
+			update: tgbotapi.Update{
+				UpdateID: 1,
+				Message: func() *tgbotapi.Message {
+					message := mock_telegram.MessageCommandMockSetup(commandAdd, "milk")
+					message.Chat = &tgbotapi.Chat{ID: 123}
+					return message
+				}(),
+			},
+			expected: []interface{}{
+				"update_id", 1, "chat_id", int64(123), "command", commandAdd},
+		},
+		{
+			testName: "Message without a command",
+			update: tgbotapi.Update{
+				UpdateID: 1,
+				Message: &tgbotapi.Message{
+					Chat: &tgbotapi.Chat{ID: 123},
+					Text: "milk",
+				},
+			},
+			expected: []interface{}{"update_id", 1, "chat_id", int64(123)},
+		},
+		{
+			testName: "CallbackQuery",
+			update: tgbotapi.Update{
+				UpdateID: 1,
+				CallbackQuery: &tgbotapi.CallbackQuery{
+					Data: fmt.Sprintf("%s:%s", commandDel, "1"),
+					Message: &tgbotapi.Message{
+						Chat: &tgbotapi.Chat{ID: 123},
+					},
+				},
+			},
+			expected: []interface{}{
+				"update_id", 1, "chat_id", int64(123), "command", commandDel},
+		},
+		{
+			testName: "ChosenInlineResult",
+			update: tgbotapi.Update{
+				UpdateID: 1,
+				ChosenInlineResult: &tgbotapi.ChosenInlineResult{
+					ResultID: commandAdd,
+				},
+			},
+			expected: []interface{}{"update_id", 1, "command", commandAdd},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.testName, func(t *testing.T) {
+			actual := updateLogKeyvals(testCase.update)
+
+			if fmt.Sprint(actual) != fmt.Sprint(testCase.expected) {
+				t.Errorf("Expected %#v, got %#v", testCase.expected, actual)
+			}
+		})
+	}
+}
+
 func TestRouteErrors(t *testing.T) {
 	// Common Interface mocks
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
 
 	// Common data mocks
+	fromMock := &tgbotapi.User{ID: 321, LanguageCode: "en"}
+	stMock.EXPECT().GetUserLocale(fromMock.ID).Return("uk", nil).AnyTimes()
 	messageMock := &tgbotapi.Message{
 		Chat: &tgbotapi.Chat{
 			ID: 123,
@@ -224,11 +422,12 @@ func TestRouteErrors(t *testing.T) {
 		handleUnrecoverableErrorIsCalled := false
 		handleUnrecoverableErrorOld := handleUnrecoverableError
 		defer func() { handleUnrecoverableError = handleUnrecoverableErrorOld }()
-		handleUnrecoverableError = func(_ botClientInterface, _ int64, _ error) {
+		handleUnrecoverableError = func(_ botClientInterface, _ *i18n.Translator, _ int64, _ error) error {
 			handleUnrecoverableErrorIsCalled = true
+			return nil
 		}
 
-		routeErrors(clientMock, messageMock, nil)
+		routeErrors(clientMock, stMock, discardLogger, fromMock, messageMock, nil)
 
 		if handleUnrecoverableErrorIsCalled {
 			t.Error("routeErrors must not continue routing, when it receives err == nil")
@@ -244,8 +443,8 @@ func TestRouteErrors(t *testing.T) {
 		handleUnrecoverableErrorOld := handleUnrecoverableError
 		defer func() { handleUnrecoverableError = handleUnrecoverableErrorOld }()
 		handleUnrecoverableError = func(
-			_ botClientInterface, actualChatID int64, actualErr error,
-		) {
+			_ botClientInterface, _ *i18n.Translator, actualChatID int64, actualErr error,
+		) error {
 			handleUnrecoverableErrorIsCalled = true
 
 			if actualErr != errMock {
@@ -256,13 +455,91 @@ func TestRouteErrors(t *testing.T) {
 				t.Errorf("got chat ID == %d, expected %d",
 					actualChatID, messageMock.Chat.ID)
 			}
+
+			return nil
+		}
+
+		routeErrors(clientMock, stMock, discardLogger, fromMock, messageMock, errMock)
+
+		if !handleUnrecoverableErrorIsCalled {
+			t.Error("handleUnrecoverableError wasn't called")
+		}
+	})
+
+	t.Run("error is keyboardError", func(t *testing.T) {
+		// Function mocks
+		handleUnrecoverableErrorIsCalled := false
+		handleUnrecoverableErrorOld := handleUnrecoverableError
+		defer func() { handleUnrecoverableError = handleUnrecoverableErrorOld }()
+		handleUnrecoverableError = func(_ botClientInterface, _ *i18n.Translator, _ int64, _ error) error {
+			handleUnrecoverableErrorIsCalled = true
+			return nil
+		}
+
+		var buf bytes.Buffer
+		logger := logging.New(&buf, logging.LevelDebug)
+
+		errMock := keyboardError{errors.New("fake error")}
+		routeErrors(clientMock, stMock, logger, fromMock, messageMock, errMock)
+
+		if handleUnrecoverableErrorIsCalled {
+			t.Error("handleUnrecoverableError must not be called, when users got a reply")
+		}
+
+		if !strings.Contains(buf.String(), "fake error") {
+			t.Errorf("Expected the error to be logged, got %#v", buf.String())
+		}
+	})
+
+	t.Run("update without a chat", func(t *testing.T) {
+		// Function mocks
+		handleUnrecoverableErrorIsCalled := false
+		handleUnrecoverableErrorOld := handleUnrecoverableError
+		defer func() { handleUnrecoverableError = handleUnrecoverableErrorOld }()
+		handleUnrecoverableError = func(_ botClientInterface, _ *i18n.Translator, _ int64, _ error) error {
+			handleUnrecoverableErrorIsCalled = true
+			return nil
+		}
+
+		var buf bytes.Buffer
+		logger := logging.New(&buf, logging.LevelDebug)
+
+		routeErrors(clientMock, stMock, logger, fromMock, nil, errors.New("fake error"))
+
+		if handleUnrecoverableErrorIsCalled {
+			t.Error("handleUnrecoverableError must not be called without a chat")
+		}
+
+		if !strings.Contains(buf.String(), "fake error") {
+			t.Errorf("Expected the error to be logged, got %#v", buf.String())
 		}
+	})
 
-		routeErrors(clientMock, messageMock, errMock)
+	t.Run("error is panicError", func(t *testing.T) {
+		// Data mocks
+		errMock := panicError{value: "fake panic", stack: []byte("fake stack")}
+
+		// Function mocks
+		handleUnrecoverableErrorIsCalled := false
+		handleUnrecoverableErrorOld := handleUnrecoverableError
+		defer func() { handleUnrecoverableError = handleUnrecoverableErrorOld }()
+		handleUnrecoverableError = func(_ botClientInterface, _ *i18n.Translator, _ int64, _ error) error {
+			handleUnrecoverableErrorIsCalled = true
+			return nil
+		}
+
+		var buf bytes.Buffer
+		logger := logging.New(&buf, logging.LevelDebug)
+
+		routeErrors(clientMock, stMock, logger, fromMock, messageMock, errMock)
 
 		if !handleUnrecoverableErrorIsCalled {
 			t.Error("handleUnrecoverableError wasn't called")
 		}
+
+		if !strings.Contains(buf.String(), "fake stack") {
+			t.Errorf("Expected the stack trace to be logged, got %#v", buf.String())
+		}
 	})
 
 	t.Run("error is updateRoutingError", func(t *testing.T) {
@@ -276,11 +553,17 @@ func TestRouteErrors(t *testing.T) {
 		defer func() { sendHelpMessage = sendHelpMessageOld }()
 		sendHelpMessage = func(
 			_ sender,
+			tr *i18n.Translator,
 			actualMessage *tgbotapi.Message,
 			isStart bool,
-		) {
+		) error {
 			sendHelpMessageIsCalled = true
 
+			if tr.Locale() != "uk" {
+				t.Errorf("Expected a translator for the user's locale, got %#v",
+					tr.Locale())
+			}
+
 			if isStart {
 				t.Error("isStart should be false, got true")
 			}
@@ -288,14 +571,73 @@ func TestRouteErrors(t *testing.T) {
 			if actualMessage != messageMock {
 				t.Errorf("got %#v, expected %#v", actualMessage, messageMock)
 			}
+
+			return nil
 		}
 
-		routeErrors(clientMock, messageMock, errMock)
+		routeErrors(clientMock, stMock, discardLogger, fromMock, messageMock, errMock)
 
 		if !sendHelpMessageIsCalled {
 			t.Error("sendHelpMessage wasn't called")
 		}
 	})
+
+	t.Run("error is API error", func(t *testing.T) {
+		testCases := []struct {
+			testName       string
+			err            error
+			expectInactive bool
+			expectReply    bool
+		}{
+			{
+				testName:       "Blocked by user",
+				err:            tgbotapi.Error{Message: "Forbidden: bot was blocked by the user"},
+				expectInactive: true,
+			},
+			{
+				testName:       "Chat not found",
+				err:            tgbotapi.Error{Message: "Bad Request: chat not found"},
+				expectInactive: true,
+			},
+			{
+				testName: "Flood wait",
+				err: tgbotapi.Error{
+					Message:            "Too Many Requests: retry after 5",
+					ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 5},
+				},
+			},
+			{
+				testName:    "Bad request",
+				err:         tgbotapi.Error{Message: "Bad Request: can't parse entities"},
+				expectReply: true,
+			},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.testName, func(t *testing.T) {
+				// Function mocks
+				handleUnrecoverableErrorIsCalled := false
+				handleUnrecoverableErrorOld := handleUnrecoverableError
+				defer func() { handleUnrecoverableError = handleUnrecoverableErrorOld }()
+				handleUnrecoverableError = func(_ botClientInterface, _ *i18n.Translator, _ int64, _ error) error {
+					handleUnrecoverableErrorIsCalled = true
+					return nil
+				}
+
+				// Interface mocks
+				if testCase.expectInactive {
+					stMock.EXPECT().MarkChatInactive(messageMock.Chat.ID).Return(nil)
+				}
+
+				routeErrors(clientMock, stMock, discardLogger, fromMock, messageMock, testCase.err)
+
+				if handleUnrecoverableErrorIsCalled != testCase.expectReply {
+					t.Errorf("Expected handleUnrecoverableError to be called: %v, got %v",
+						testCase.expectReply, handleUnrecoverableErrorIsCalled)
+				}
+			})
+		}
+	})
 }
 
 func TestRouteCallbackQuery(t *testing.T) {
@@ -330,15 +672,29 @@ func TestRouteCallbackQuery(t *testing.T) {
 		return map[string]botCommand{
 			commandClear: {
 				callbackQueryHandler: handlerMock,
+				permission:           permissionAdmin,
 			},
 		}
 	}
+	allowedMock := true
+	hasPermissionOld := hasPermission
+	defer func() { hasPermission = hasPermissionOld }()
+	hasPermission = func(
+		_ chatMemberGetter,
+		_ storage.DataStorageInterface,
+		_ *tgbotapi.Chat,
+		_ *tgbotapi.User,
+		_ permission,
+	) (bool, error) {
+		return allowedMock, nil
+	}
 
 	// Interface mocks
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
 	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
 
 	t.Run("Commands", func(t *testing.T) {
 		t.Run("Supported command", func(t *testing.T) {
@@ -361,6 +717,32 @@ func TestRouteCallbackQuery(t *testing.T) {
 		})
 	})
 
+	t.Run("Permission denied", func(t *testing.T) {
+		allowedMock = false
+		defer func() { allowedMock = true }()
+
+		// Data mocks
+		callbackQueryMock := &tgbotapi.CallbackQuery{
+			ID:   "some-callback-id",
+			Data: callbackQueryMock.Data,
+			Message: &tgbotapi.Message{
+				Chat: &tgbotapi.Chat{ID: 123, Type: "group"},
+			},
+		}
+
+		clientMock.EXPECT().AnswerCallbackQuery(gomock.Any()).Do(func(config tgbotapi.CallbackConfig) {
+			if config.CallbackQueryID != callbackQueryMock.ID || !config.ShowAlert {
+				t.Errorf("Expected an alert for the callback query %#v, got %#v",
+					callbackQueryMock.ID, config)
+			}
+		})
+
+		err := routeCallbackQuery(clientMock, stMock, callbackQueryMock)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
 	t.Run("Callback data error", func(t *testing.T) {
 		// Data mocks
 		callbackQueryMock := &tgbotapi.CallbackQuery{
@@ -374,6 +756,63 @@ func TestRouteCallbackQuery(t *testing.T) {
 	})
 }
 
+func TestRouteChosenInlineResult(t *testing.T) {
+	// Common data mocks
+	errMock := errors.New("Fake error")
+	chosenInlineResultMock := &tgbotapi.ChosenInlineResult{
+		ResultID: commandAdd,
+	}
+
+	// Common function mocks
+	handlerMock := func(
+		_ botClientInterface,
+		_ storage.DataStorageInterface,
+		chosenInlineResult *tgbotapi.ChosenInlineResult,
+	) error {
+		if chosenInlineResult != chosenInlineResultMock {
+			t.Error("Unexpected chosenInlineResult")
+		}
+		return errMock
+	}
+	getBotCommandsMappingOld := getBotCommandsMapping
+	defer func() { getBotCommandsMapping = getBotCommandsMappingOld }()
+	getBotCommandsMapping = func() map[string]botCommand {
+		return map[string]botCommand{
+			commandAdd: {
+				chosenInlineResultHandler: handlerMock,
+			},
+			commandList: {},
+		}
+	}
+
+	// Interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+	t.Run("Supported result", func(t *testing.T) {
+		err := routeChosenInlineResult(clientMock, stMock, chosenInlineResultMock)
+		if errMock != err {
+			t.Fatalf("Expected the %#v error, got %#v", errMock, err)
+		}
+	})
+
+	for _, resultID := range []string{inlineResultShare, commandList} {
+		t.Run("Not supported result "+resultID, func(t *testing.T) {
+			// Data mocks
+			chosenInlineResultMock := &tgbotapi.ChosenInlineResult{
+				ResultID: resultID,
+			}
+
+			err := routeChosenInlineResult(clientMock, stMock, chosenInlineResultMock)
+			if _, ok := err.(updateRoutingError); !ok {
+				t.Fatalf("expected %T got %T", updateRoutingError{}, err)
+			}
+		})
+	}
+}
+
 func TestRouteMessage(t *testing.T) {
 	// Mock setup funcs
 	var routeMessageEntitiesMockSetup = func(errMock error) func() {
@@ -381,7 +820,7 @@ func TestRouteMessage(t *testing.T) {
 		tearDownFunc := func() { routeMessageEntities = routeMessageEntitiesOld }
 
 		routeMessageEntities = func(
-			_ sender,
+			_ botClientInterface,
 			_ storage.DataStorageInterface,
 			_ *tgbotapi.Message,
 		) error {
@@ -412,7 +851,7 @@ func TestRouteMessage(t *testing.T) {
 				)
 				defer tearDownFunc()
 
-				err := routeMessage(clientMock, stMock, messageMock)
+				err := routeMessage(clientMock, stMock, discardLogger, messageMock)
 
 				if errCommandIsNotSupported != err {
 					t.Errorf("Expected %#v, got %#v",
@@ -429,7 +868,7 @@ func TestRouteMessage(t *testing.T) {
 			tearDownFunc := routeMessageEntitiesMockSetup(errMock)
 			defer tearDownFunc()
 
-			err := routeMessage(clientMock, stMock, messageMock)
+			err := routeMessage(clientMock, stMock, discardLogger, messageMock)
 
 			if errMock != err {
 				t.Errorf("Expected %#v, got %#v", errMock, err)
@@ -451,14 +890,15 @@ func TestRouteMessage(t *testing.T) {
 		routeMessageTextOld := routeMessageText
 		defer func() { routeMessageText = routeMessageTextOld }()
 		routeMessageText = func(
-			client sender,
+			client botClientInterface,
 			st storage.DataStorageInterface,
+			logger *logging.Logger,
 			message *tgbotapi.Message,
 		) error {
 			return errFromrouteMessageTextMock
 		}
 
-		err := routeMessage(clientMock, stMock, messageMock)
+		err := routeMessage(clientMock, stMock, discardLogger, messageMock)
 
 		if errFromRouteMessageEntities == err {
 			t.Fatalf("Expected %#v, got %#v", errFromrouteMessageTextMock, err)
@@ -468,6 +908,93 @@ func TestRouteMessage(t *testing.T) {
 			t.Fatalf("Expected %#v, got %#v", errFromrouteMessageTextMock, err)
 		}
 	})
+
+	t.Run("Intents in private chats", func(t *testing.T) {
+		// Data mocks
+		errMock := errors.New("Fake error")
+		messageMock := &tgbotapi.Message{
+			Chat: &tgbotapi.Chat{ID: 123, Type: "private"},
+		}
+
+		// Function mocks
+		tearDownFunc := routeMessageEntitiesMockSetup(updateRoutingError{
+			errors.New("Fake error")})
+		defer tearDownFunc()
+
+		routeMessageTextOld := routeMessageText
+		defer func() { routeMessageText = routeMessageTextOld }()
+		routeMessageText = func(
+			_ botClientInterface,
+			_ storage.DataStorageInterface,
+			_ *logging.Logger,
+			_ *tgbotapi.Message,
+		) error {
+			return updateRoutingError{errors.New("Fake error")}
+		}
+
+		routeMessageIntentOld := routeMessageIntent
+		defer func() { routeMessageIntent = routeMessageIntentOld }()
+		routeMessageIntent = func(
+			_ botClientInterface,
+			_ storage.DataStorageInterface,
+			_ *logging.Logger,
+			message *tgbotapi.Message,
+		) error {
+			if message != messageMock {
+				t.Error("Wrong message received")
+			}
+			return errMock
+		}
+
+		err := routeMessage(clientMock, stMock, discardLogger, messageMock)
+		if errMock != err {
+			t.Fatalf("Expected %#v, got %#v", errMock, err)
+		}
+	})
+}
+
+func TestRouteMessageLogging(t *testing.T) {
+	// Function mocks
+	routeMessageEntitiesOld := routeMessageEntities
+	defer func() { routeMessageEntities = routeMessageEntitiesOld }()
+	routeMessageEntities = func(
+		_ botClientInterface,
+		_ storage.DataStorageInterface,
+		_ *tgbotapi.Message,
+	) error {
+		return nil
+	}
+
+	// Common interface mocks
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+	// Common data mocks
+	messageMock := &tgbotapi.Message{Text: "private text"}
+
+	t.Run("Info level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := logging.New(&buf, logging.LevelInfo)
+
+		routeMessage(clientMock, stMock, logger, messageMock)
+
+		if strings.Contains(buf.String(), messageMock.Text) {
+			t.Errorf("Message text must not be logged, got %#v", buf.String())
+		}
+	})
+
+	t.Run("Debug level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := logging.New(&buf, logging.LevelDebug)
+
+		routeMessage(clientMock, stMock, logger, messageMock)
+
+		if !strings.Contains(buf.String(), messageMock.Text) {
+			t.Errorf("Message text expected to be logged, got %#v", buf.String())
+		}
+	})
 }
 
 func TestRouteMessageEntities(t *testing.T) {
@@ -476,7 +1003,7 @@ func TestRouteMessageEntities(t *testing.T) {
 
 	// Common function mocks
 	handlerMock := func(
-		_ sender, _ storage.DataStorageInterface, _ *tgbotapi.Message,
+		_ botClientInterface, _ storage.DataStorageInterface, _ *tgbotapi.Message,
 	) error {
 		return errMock
 	}
@@ -487,14 +1014,30 @@ func TestRouteMessageEntities(t *testing.T) {
 			commandAdd: {
 				commandHandler: handlerMock,
 			},
+			commandDel: {
+				commandHandler: handlerMock,
+				permission:     permissionAdmin,
+			},
 		}
 	}
+	hasPermissionOld := hasPermission
+	defer func() { hasPermission = hasPermissionOld }()
+	hasPermission = func(
+		_ chatMemberGetter,
+		_ storage.DataStorageInterface,
+		_ *tgbotapi.Chat,
+		_ *tgbotapi.User,
+		p permission,
+	) (bool, error) {
+		return p == permissionEveryone, nil
+	}
 
 	// Common interface mocks
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
 	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
 
 	t.Run("Empty message Entities", func(t *testing.T) {
 		// Data mocks
@@ -520,6 +1063,25 @@ func TestRouteMessageEntities(t *testing.T) {
 			}
 		})
 
+		t.Run("Permission denied", func(t *testing.T) {
+			// Data mocks
+			messageMock := mock_telegram.MessageCommandMockSetup(commandDel, "")
+			messageMock.MessageID = 456
+			messageMock.Chat = &tgbotapi.Chat{ID: 123, Type: "group"}
+
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				if msgCfg.ReplyToMessageID != messageMock.MessageID {
+					t.Errorf("Expected a reply to the message %d, got %d",
+						messageMock.MessageID, msgCfg.ReplyToMessageID)
+				}
+			})
+
+			err := routeMessageEntities(clientMock, stMock, messageMock)
+			if err != nil {
+				t.Errorf("Unexpected err: got %#v", err)
+			}
+		})
+
 		t.Run("Not supported command", func(t *testing.T) {
 			// Data mocks
 			messageMock := mock_telegram.MessageCommandMockSetup("invalid_command", "")
@@ -546,7 +1108,7 @@ func TestRouteMessageText(t *testing.T) {
 
 	// Common function mocks
 	handlerMock := func(
-		_ sender, _ storage.DataStorageInterface, message *tgbotapi.Message,
+		_ botClientInterface, _ storage.DataStorageInterface, message *tgbotapi.Message,
 	) error {
 		if message != messageMock {
 			t.Error("Wrong message received")
@@ -561,14 +1123,30 @@ func TestRouteMessageText(t *testing.T) {
 			commandStart: {
 				unfinishedCommandHandler: handlerMock,
 			},
+			commandSettings: {
+				unfinishedCommandHandler: handlerMock,
+				permission:               permissionAdmin,
+			},
 		}
 	}
+	hasPermissionOld := hasPermission
+	defer func() { hasPermission = hasPermissionOld }()
+	hasPermission = func(
+		_ chatMemberGetter,
+		_ storage.DataStorageInterface,
+		_ *tgbotapi.Chat,
+		_ *tgbotapi.User,
+		p permission,
+	) (bool, error) {
+		return p == permissionEveryone, nil
+	}
 
 	// Common interface mocks
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	clientMock := mock_telegram.NewMockbotClientInterface(mockCtrl)
 	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+	expectDefaultSettings(stMock)
 
 	t.Run("Commands", func(t *testing.T) {
 		t.Run("Supported command", func(t *testing.T) {
@@ -589,13 +1167,44 @@ func TestRouteMessageText(t *testing.T) {
 				).Return(nil),
 			)
 
-			err := routeMessageText(clientMock, stMock, messageMock)
+			err := routeMessageText(clientMock, stMock, discardLogger, messageMock)
 
 			if errMock != err {
 				t.Errorf("expected %v, got %v", errMock, err)
 			}
 		})
 
+		t.Run("Permission denied", func(t *testing.T) {
+			// Data mocks
+			unfinishedCommandMock := &models.UnfinishedCommand{
+				Command: commandSettings,
+			}
+
+			// Interface mocks
+			gomock.InOrder(
+				stMock.EXPECT().GetUnfinishedCommand(
+					messageMock.Chat.ID,
+					messageMock.From.ID,
+				).Return(unfinishedCommandMock, nil),
+				stMock.EXPECT().DeleteUnfinishedCommand(
+					messageMock.Chat.ID,
+					messageMock.From.ID,
+				).Return(nil),
+			)
+			clientMock.EXPECT().Send(gomock.Any()).Do(func(msgCfg tgbotapi.MessageConfig) {
+				expectedText := "only administrators"
+				if !strings.Contains(msgCfg.Text, expectedText) {
+					t.Errorf("Expected message to contain %#v, got %#v",
+						expectedText, msgCfg.Text)
+				}
+			})
+
+			err := routeMessageText(clientMock, stMock, discardLogger, messageMock)
+			if err != nil {
+				t.Errorf("Unexpected err: got %#v", err)
+			}
+		})
+
 		t.Run("Unfinished command wasn not found", func(t *testing.T) {
 			// Interface mocks
 			stMock.EXPECT().GetUnfinishedCommand(
@@ -603,7 +1212,7 @@ func TestRouteMessageText(t *testing.T) {
 				messageMock.From.ID,
 			).Return(nil, nil)
 
-			err := routeMessageText(clientMock, stMock, messageMock)
+			err := routeMessageText(clientMock, stMock, discardLogger, messageMock)
 
 			if _, ok := err.(updateRoutingError); !ok {
 				t.Fatalf("expected error of %T, got %T", updateRoutingError{}, err)
@@ -628,7 +1237,7 @@ func TestRouteMessageText(t *testing.T) {
 				messageMock.From.ID,
 			).Return(unfinishedCommandMock, nil)
 
-			err := routeMessageText(clientMock, stMock, messageMock)
+			err := routeMessageText(clientMock, stMock, discardLogger, messageMock)
 
 			if _, ok := err.(updateRoutingError); !ok {
 				t.Fatalf("expected error of %T, got %T", updateRoutingError{}, err)
@@ -653,7 +1262,7 @@ func TestRouteMessageText(t *testing.T) {
 				messageMock.From.ID,
 			).Return(nil, errMock)
 
-			err := routeMessageText(clientMock, stMock, messageMock)
+			err := routeMessageText(clientMock, stMock, discardLogger, messageMock)
 
 			if !strings.Contains(err.Error(), errMock.Error()) {
 				t.Errorf("Expected error to contain %#v, got \"%s\"",
@@ -680,7 +1289,7 @@ func TestRouteMessageText(t *testing.T) {
 				).Return(errMock),
 			)
 
-			err := routeMessageText(clientMock, stMock, messageMock)
+			err := routeMessageText(clientMock, stMock, discardLogger, messageMock)
 
 			if !strings.Contains(err.Error(), errMock.Error()) {
 				t.Errorf("Expected error to contain %#v, got \"%s\"",
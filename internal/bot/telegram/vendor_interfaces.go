@@ -7,6 +7,8 @@ package telegram
 //go:generate mockgen -source=$GOFILE -destination=../../mocks/bot/mock_$GOPACKAGE/$GOFILE -package=mock_$GOPACKAGE
 
 import (
+	"net/url"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 )
 
@@ -32,6 +34,24 @@ type callbackQueryAnswerer interface {
 	AnswerCallbackQuery(config tgbotapi.CallbackConfig) (tgbotapi.APIResponse, error)
 }
 
+type inlineQueryAnswerer interface {
+	AnswerInlineQuery(config tgbotapi.InlineConfig) (tgbotapi.APIResponse, error)
+}
+
+type chatMemberGetter interface {
+	GetChatMember(config tgbotapi.ChatConfigWithUser) (tgbotapi.ChatMember, error)
+}
+
+type fileURLGetter interface {
+	GetFileDirectURL(fileID string) (string, error)
+}
+
+// requestMaker allows to call API methods
+// which are not supported by the library yet
+type requestMaker interface {
+	MakeRequest(endpoint string, params url.Values) (tgbotapi.APIResponse, error)
+}
+
 type tokenListenForWebhook interface {
 	webhookListener
 	tokener
@@ -42,4 +62,8 @@ type botClientInterface interface {
 	tokener
 	sender
 	callbackQueryAnswerer
+	inlineQueryAnswerer
+	chatMemberGetter
+	fileURLGetter
+	requestMaker
 }
@@ -2,8 +2,7 @@ package cli
 
 import (
 	"database/sql"
-	"fmt"
-	"log"
+	"strconv"
 
 	"github.com/spf13/cobra"
 
@@ -26,24 +25,28 @@ var startTelegramBotCmd = &cobra.Command{
 	Use:   "telegram",
 	Short: "Start a telegram bot",
 	Run: func(cmd *cobra.Command, args []string) {
+		logger := newLogger()
+
 		// Initialise DB connection pool
 		dbConnectionStr, err := env.GetDBConnectionString()
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(logger, err)
 		}
 		db, err := sql.Open("postgres", dbConnectionStr)
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(logger, err)
 		}
 
 		// Get bot API token
 		apiToken, err := env.GetTelegramAPIToken()
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(logger, err)
 		}
 
 		// Create a app bot instance
-		newBotAppOptions := []func(*telegram.BotApp) error{}
+		newBotAppOptions := []func(*telegram.BotApp) error{
+			telegram.Logger(logger),
+		}
 
 		TLSCertPath, TLSCertPathErr := env.GetTelegramTLSCertPath()
 		TLSKeyPath, TLSKeyPathErr := env.GetTelegramTLSKeyPath()
@@ -55,13 +58,26 @@ var startTelegramBotCmd = &cobra.Command{
 		}
 
 		if port, err := env.GetTelegramWebhookPort(); err == nil {
-			fmt.Println("port", port)
+			logger.Debug("Custom webhook port is set", "port", port)
 			newBotAppOptions = append(
 				newBotAppOptions,
 				telegram.WebhookPort(port),
 			)
 		}
 
+		if days, err := env.GetAuditLogRetention(); err == nil {
+			retention, err := strconv.Atoi(days)
+			if err != nil {
+				exitWithError(logger, err)
+			}
+
+			logger.Debug("Custom audit log retention is set", "days", retention)
+			newBotAppOptions = append(
+				newBotAppOptions,
+				telegram.AuditLogRetention(retention),
+			)
+		}
+
 		storage := storage.NewSQLStorage(db)
 		botApp, err := telegram.NewBotApp(
 			storage,
@@ -69,10 +85,10 @@ var startTelegramBotCmd = &cobra.Command{
 			newBotAppOptions...,
 		)
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(logger, err)
 		}
 		if err := telegram.StartBotApp(botApp); err != nil {
-			log.Fatal(err)
+			exitWithError(logger, err)
 		}
 	},
 }
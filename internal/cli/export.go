@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"database/sql"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/env"
+	"github.com/m1kola/shipsterbot/internal/pkg/export"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+var exportFlags struct {
+	chatID int64
+	format string
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().Int64Var(
+		&exportFlags.chatID, "chat-id", 0, "ID of a chat which list to export")
+	exportCmd.Flags().StringVar(
+		&exportFlags.format, "format", string(export.FormatCSV),
+		"Format of the list: csv, json or md")
+	exportCmd.MarkFlagRequired("chat-id")
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a shopping list of a chat into stdout",
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := newLogger()
+
+		format, err := export.ParseFormat(exportFlags.format)
+		if err != nil {
+			exitWithError(logger, err)
+		}
+
+		// Open DB connection
+		dbConnectionStr, err := env.GetDBConnectionString()
+		if err != nil {
+			exitWithError(logger, err)
+		}
+		db, err := sql.Open("postgres", dbConnectionStr)
+		if err != nil {
+			exitWithError(logger, err)
+		}
+		defer db.Close()
+
+		items, err := export.Load(storage.NewSQLStorage(db), exportFlags.chatID)
+		if err != nil {
+			exitWithError(logger, err)
+		}
+
+		if err := export.Write(os.Stdout, format, items); err != nil {
+			exitWithError(logger, err)
+		}
+	},
+}
@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/env"
+	"github.com/m1kola/shipsterbot/internal/pkg/logging"
+)
+
+// newLogger creates a logger which level depends on the execution mode
+func newLogger() *logging.Logger {
+	level := logging.LevelInfo
+	if env.IsDebug() {
+		level = logging.LevelDebug
+	}
+
+	return logging.New(os.Stderr, level)
+}
+
+// exitWithError logs the error and terminates the program
+func exitWithError(logger *logging.Logger, err error) {
+	logger.Error("Unable to start", "err", err)
+	os.Exit(1)
+}
@@ -0,0 +1,95 @@
+// Package category sorts shopping items into categories,
+// so users can walk through a store aisle by aisle.
+//
+// Categories are detected by keywords: "oat milk" is dairy,
+// because "milk" is a dairy keyword. Chats can teach the bot
+// their own keywords, which take precedence over built-in ones.
+package category
+
+import (
+	"strings"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/itemname"
+)
+
+// Supported categories
+const (
+	Produce   = "produce"
+	Bakery    = "bakery"
+	Meat      = "meat"
+	Dairy     = "dairy"
+	Frozen    = "frozen"
+	Pantry    = "pantry"
+	Drinks    = "drinks"
+	Household = "household"
+	// Other is a category of items which we can't categorise
+	Other = "other"
+)
+
+// DefaultOrder is the order in which people usually walk through a store.
+// It contains all supported categories
+var DefaultOrder = []string{
+	Produce, Bakery, Meat, Dairy, Frozen, Pantry, Drinks, Household, Other,
+}
+
+// IsSupported reports whether a category is supported
+func IsSupported(category string) bool {
+	for _, c := range DefaultOrder {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Order returns a complete order of categories: categories
+// which are missing in a custom order go after it in the default order
+func Order(custom []string) []string {
+	var order []string
+	seen := map[string]bool{}
+	for _, c := range append(append([]string{}, custom...), DefaultOrder...) {
+		if IsSupported(c) && !seen[c] {
+			seen[c] = true
+			order = append(order, c)
+		}
+	}
+	return order
+}
+
+// Detect returns a category of an item.
+//
+// Keywords are normalized names of items (see itemname.Normalize).
+// Learned keywords are checked before built-in ones.
+// The last word of a name is the most important one:
+// "milk chocolate" is chocolate, not milk
+func Detect(name string, learned map[string]string) string {
+	normalized := itemname.Normalize(name)
+	if normalized == "" {
+		return Other
+	}
+
+	// Longer keywords are more specific: "ice cream" isn't cream.
+	// Then we try words alone, starting from the last one
+	words := strings.Fields(normalized)
+	var candidates []string
+	for i := range words {
+		candidates = append(candidates, strings.Join(words[i:], " "))
+	}
+	for i := len(words) - 2; i >= 0; i-- {
+		candidates = append(candidates, itemname.Normalize(words[i]))
+	}
+
+	for _, dictionary := range []map[string]string{learned, keywords} {
+		for _, candidate := range candidates {
+			if c, ok := dictionary[candidate]; ok && IsSupported(c) {
+				return c
+			}
+		}
+	}
+	return Other
+}
+
+// Keyword returns a keyword for teaching the bot a category of an item
+func Keyword(name string) string {
+	return itemname.Normalize(name)
+}
@@ -0,0 +1,65 @@
+package category
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	learned := map[string]string{
+		"oat milk": Pantry,
+		"kombucha": Drinks,
+		"bread":    "unsupported",
+	}
+
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"Milk", Dairy},
+		{"Eggs", Dairy},
+		{"Milk chocolate", Pantry},
+		{"Vanilla ice cream", Frozen},
+		{"Soft toilet paper", Household},
+		{"Oat milk", Pantry},
+		{"Kombucha", Drinks},
+		{"Bread", Bakery},
+		{"Молоко", Dairy},
+		{"Something new", Other},
+		{"", Other},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			c := Detect(testCase.name, learned)
+			if c != testCase.expected {
+				t.Errorf("Expected %#v, got %#v", testCase.expected, c)
+			}
+		})
+	}
+}
+
+func TestOrder(t *testing.T) {
+	order := Order([]string{Household, "unsupported", Dairy, Household})
+	expected := []string{
+		Household, Dairy, Produce, Bakery, Meat, Frozen, Pantry, Drinks, Other,
+	}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("Expected %#v, got %#v", expected, order)
+	}
+
+	if !reflect.DeepEqual(Order(nil), DefaultOrder) {
+		t.Errorf("Expected the default order, got %#v", Order(nil))
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	for keyword, c := range keywords {
+		if Keyword(keyword) != keyword {
+			t.Errorf("Keyword %#v isn't normalized", keyword)
+		}
+		if !IsSupported(c) {
+			t.Errorf("Keyword %#v has an unsupported category %#v", keyword, c)
+		}
+	}
+}
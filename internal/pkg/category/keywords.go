@@ -0,0 +1,155 @@
+package category
+
+// keywords is the built-in dictionary of keywords.
+// Keywords must be normalized (see itemname.Normalize)
+var keywords = map[string]string{
+	// Produce
+	"apple":    Produce,
+	"banana":   Produce,
+	"orange":   Produce,
+	"lemon":    Produce,
+	"grape":    Produce,
+	"berry":    Produce,
+	"pear":     Produce,
+	"tomato":   Produce,
+	"potato":   Produce,
+	"onion":    Produce,
+	"garlic":   Produce,
+	"carrot":   Produce,
+	"cucumber": Produce,
+	"pepper":   Produce,
+	"lettuce":  Produce,
+	"salad":    Produce,
+	"cabbage":  Produce,
+	"avocado":  Produce,
+	"mushroom": Produce,
+	"яблука":   Produce,
+	"яблуко":   Produce,
+	"банани":   Produce,
+	"банан":    Produce,
+	"помідори": Produce,
+	"картопля": Produce,
+	"цибуля":   Produce,
+	"часник":   Produce,
+	"морква":   Produce,
+	"огірки":   Produce,
+	"капуста":  Produce,
+	"гриби":    Produce,
+
+	// Bakery
+	"bread":     Bakery,
+	"baguette":  Bakery,
+	"bun":       Bakery,
+	"roll":      Bakery,
+	"croissant": Bakery,
+	"cake":      Bakery,
+	"bagel":     Bakery,
+	"хліб":      Bakery,
+	"батон":     Bakery,
+	"булочки":   Bakery,
+	"торт":      Bakery,
+
+	// Meat
+	"chicken":   Meat,
+	"beef":      Meat,
+	"pork":      Meat,
+	"ham":       Meat,
+	"bacon":     Meat,
+	"sausage":   Meat,
+	"mince":     Meat,
+	"fish":      Meat,
+	"salmon":    Meat,
+	"курка":     Meat,
+	"яловичина": Meat,
+	"свинина":   Meat,
+	"ковбаса":   Meat,
+	"сосиски":   Meat,
+	"фарш":      Meat,
+	"риба":      Meat,
+
+	// Dairy
+	"milk":    Dairy,
+	"cheese":  Dairy,
+	"butter":  Dairy,
+	"yogurt":  Dairy,
+	"yoghurt": Dairy,
+	"cream":   Dairy,
+	"egg":     Dairy,
+	"kefir":   Dairy,
+	"молоко":  Dairy,
+	"сир":     Dairy,
+	"масло":   Dairy,
+	"йогурт":  Dairy,
+	"сметана": Dairy,
+	"вершки":  Dairy,
+	"яйця":    Dairy,
+	"кефір":   Dairy,
+
+	// Frozen
+	"ice cream": Frozen,
+	"pizza":     Frozen,
+	"dumpling":  Frozen,
+	"морозиво":  Frozen,
+	"пельмені":  Frozen,
+	"вареники":  Frozen,
+
+	// Pantry
+	"rice":      Pantry,
+	"pasta":     Pantry,
+	"spaghetti": Pantry,
+	"flour":     Pantry,
+	"sugar":     Pantry,
+	"salt":      Pantry,
+	"oil":       Pantry,
+	"cereal":    Pantry,
+	"oat":       Pantry,
+	"bean":      Pantry,
+	"sauce":     Pantry,
+	"ketchup":   Pantry,
+	"honey":     Pantry,
+	"chocolate": Pantry,
+	"cookie":    Pantry,
+	"рис":       Pantry,
+	"макарони":  Pantry,
+	"борошно":   Pantry,
+	"цукор":     Pantry,
+	"сіль":      Pantry,
+	"олія":      Pantry,
+	"гречка":    Pantry,
+	"вівсянка":  Pantry,
+	"мед":       Pantry,
+	"шоколад":   Pantry,
+	"печиво":    Pantry,
+
+	// Drinks
+	"water":  Drinks,
+	"juice":  Drinks,
+	"coffee": Drinks,
+	"tea":    Drinks,
+	"beer":   Drinks,
+	"wine":   Drinks,
+	"soda":   Drinks,
+	"вода":   Drinks,
+	"сік":    Drinks,
+	"кава":   Drinks,
+	"чай":    Drinks,
+	"пиво":   Drinks,
+	"вино":   Drinks,
+
+	// Household
+	"soap":            Household,
+	"shampoo":         Household,
+	"toothpaste":      Household,
+	"detergent":       Household,
+	"toilet paper":    Household,
+	"paper towel":     Household,
+	"sponge":          Household,
+	"bin bag":         Household,
+	"napkin":          Household,
+	"мило":            Household,
+	"шампунь":         Household,
+	"зубна паста":     Household,
+	"туалетний папір": Household,
+	"серветки":        Household,
+	"губки":           Household,
+}
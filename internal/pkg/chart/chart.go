@@ -0,0 +1,66 @@
+// Package chart draws simple charts as PNG images
+// using only the standard library
+package chart
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+// Colors of charts
+var (
+	Background = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	Bar        = color.RGBA{0x2a, 0x9d, 0xd8, 0xff}
+	Axis       = color.RGBA{0x99, 0x99, 0x99, 0xff}
+)
+
+// padding is a space around the plot in pixels
+const padding = 16
+
+// Bars draws a bar chart of values and writes it as a PNG image into w.
+// Bars are scaled, so the highest bar takes the whole height of the plot
+func Bars(w io.Writer, values []int, width, height int) error {
+	if len(values) == 0 {
+		return fmt.Errorf("There are no values to draw")
+	}
+	if width <= 2*padding || height <= 2*padding {
+		return fmt.Errorf("Chart %dx%d is too small", width, height)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{Background}, image.ZP, draw.Src)
+
+	plot := image.Rect(padding, padding, width-padding, height-padding)
+
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	slot := plot.Dx() / len(values)
+	gap := slot / 5
+	for i, v := range values {
+		if v <= 0 || slot-gap < 1 {
+			continue
+		}
+
+		barHeight := v * plot.Dy() / max
+		bar := image.Rect(
+			plot.Min.X+i*slot+gap/2,
+			plot.Max.Y-barHeight,
+			plot.Min.X+(i+1)*slot-gap/2,
+			plot.Max.Y)
+		draw.Draw(img, bar, &image.Uniform{Bar}, image.ZP, draw.Src)
+	}
+
+	axis := image.Rect(plot.Min.X, plot.Max.Y, plot.Max.X, plot.Max.Y+1)
+	draw.Draw(img, axis, &image.Uniform{Axis}, image.ZP, draw.Src)
+
+	return png.Encode(w, img)
+}
@@ -0,0 +1,61 @@
+package chart
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestBars(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := Bars(&buf, []int{0, 2, 4}, 132, 132)
+		if err != nil {
+			t.Fatalf("Unexpected err: got %#v", err)
+		}
+
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatalf("Unable to decode the chart: %v", err)
+		}
+
+		if img.Bounds().Dx() != 132 || img.Bounds().Dy() != 132 {
+			t.Errorf("Expected 132x132 image, got %v", img.Bounds())
+		}
+
+		// Slots are 100/3 = 33 pixels wide, the plot is 100 pixels high
+		testCases := []struct {
+			name     string
+			x, y     int
+			expected color.Color
+		}{
+			{"Empty slot", padding + 16, padding + 99, Background},
+			{"Half bar top", padding + 33 + 16, padding + 50, Bar},
+			{"Above half bar", padding + 33 + 16, padding + 49, Background},
+			{"Full bar top", padding + 66 + 16, padding, Bar},
+			{"Axis", padding + 16, padding + 100, Axis},
+		}
+		for _, tc := range testCases {
+			r, g, b, a := img.At(tc.x, tc.y).RGBA()
+			er, eg, eb, ea := tc.expected.RGBA()
+			if r != er || g != eg || b != eb || a != ea {
+				t.Errorf("%s: unexpected color at (%d, %d)", tc.name, tc.x, tc.y)
+			}
+		}
+	})
+
+	t.Run("No values", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Bars(&buf, nil, 100, 100); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("Too small", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Bars(&buf, []int{1}, 10, 10); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
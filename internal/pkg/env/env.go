@@ -7,6 +7,7 @@ import "os"
 const (
 	dbConnectionStringVarName = "DATABASE_URL"
 	debugVarName              = "DEBUG"
+	auditLogRetentionVarName  = "AUDIT_LOG_RETENTION_DAYS"
 )
 
 // Telegram bot specific env vars
@@ -27,6 +28,11 @@ func GetDBConnectionString() (string, error) {
 	return lookupEnv(dbConnectionStringVarName)
 }
 
+// GetAuditLogRetention returns how many days the audit log keeps changes
+func GetAuditLogRetention() (string, error) {
+	return lookupEnv(auditLogRetentionVarName)
+}
+
 // GetTelegramAPIToken returns telegram bot api token
 func GetTelegramAPIToken() (string, error) {
 	return lookupEnv(telegramAPITokenVarName)
@@ -16,6 +16,11 @@ func TestSimpleEnvLookupFunctions(t *testing.T) {
 			testName:   "GetDBConnectionString",
 			envVarKey:  dbConnectionStringVarName,
 		},
+		{
+			funcToTest: GetAuditLogRetention,
+			testName:   "GetAuditLogRetention",
+			envVarKey:  auditLogRetentionVarName,
+		},
 		{
 			funcToTest: GetTelegramAPIToken,
 			testName:   "GetTelegramAPIToken",
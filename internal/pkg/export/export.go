@@ -0,0 +1,202 @@
+// Package export writes shopping lists in formats
+// which other apps understand: CSV, JSON and Markdown.
+// It also reads lists from CSV, JSON and plain text files
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/storage"
+)
+
+// Format is a format of an exported shopping list
+type Format string
+
+// All supported formats
+const (
+	FormatCSV      Format = "csv"
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "md"
+)
+
+// Formats are all supported formats. The first one is the default
+var Formats = []Format{FormatCSV, FormatJSON, FormatMarkdown}
+
+// timeLayout is how times of items are written
+const timeLayout = time.RFC3339
+
+// csvHeader is the first row of CSV files
+var csvHeader = []string{"name", "quantity", "category", "author_id", "author", "added_at"}
+
+// Item is a shopping item in an exported list
+type Item struct {
+	Name     string     `json:"name"`
+	Quantity int        `json:"quantity"`
+	Category string     `json:"category,omitempty"`
+	AuthorID int        `json:"author_id,omitempty"`
+	Author   string     `json:"author,omitempty"`
+	AddedAt  *time.Time `json:"added_at,omitempty"`
+}
+
+// document is the top level object of JSON files
+type document struct {
+	Items []Item `json:"items"`
+}
+
+// ParseFormat returns a format by its name. Names are case insensitive,
+// the empty name means the default format
+func ParseFormat(name string) (Format, error) {
+	if name == "" {
+		return Formats[0], nil
+	}
+
+	for _, format := range Formats {
+		if strings.EqualFold(name, string(format)) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("Unsupported export format %#v", name)
+}
+
+// FileName returns a name of a file with a list in the format
+func (f Format) FileName() string {
+	return "shopping-list." + string(f)
+}
+
+// Load returns items of a shopping list of a chat
+// with names of users who added them
+func Load(st storage.DataStorageInterface, chatID int64) ([]Item, error) {
+	chatItems, err := st.GetShoppingItems(chatID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Unable to get all shopping items (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	names, err := st.GetActorNames(chatID)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Unable to get names of users (ChatID=%d): %v",
+			chatID, err)
+	}
+
+	return NewItems(chatItems, names), nil
+}
+
+// NewItems converts shopping items into exported items.
+// Authors are looked up in names by user IDs
+func NewItems(chatItems []*models.ShoppingItem, names map[int]string) []Item {
+	items := make([]Item, 0, len(chatItems))
+	for _, chatItem := range chatItems {
+		item := Item{
+			Name:     chatItem.Name,
+			Quantity: chatItem.Quantity,
+			Category: chatItem.Category,
+			AuthorID: chatItem.CreatedBy,
+			Author:   names[chatItem.CreatedBy],
+		}
+
+		// Items which were created without quantity are single items
+		if item.Quantity < 1 {
+			item.Quantity = 1
+		}
+		if chatItem.CreatedAt != nil {
+			addedAt := chatItem.CreatedAt.UTC()
+			item.AddedAt = &addedAt
+		}
+
+		items = append(items, item)
+	}
+	return items
+}
+
+// Write writes items into w in the format
+func Write(w io.Writer, format Format, items []Item) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, items)
+	case FormatJSON:
+		return writeJSON(w, items)
+	case FormatMarkdown:
+		return writeMarkdown(w, items)
+	}
+	return fmt.Errorf("Unsupported export format %#v", format)
+}
+
+func writeCSV(w io.Writer, items []Item) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		authorID := ""
+		if item.AuthorID != 0 {
+			authorID = strconv.Itoa(item.AuthorID)
+		}
+
+		err := cw.Write([]string{
+			item.Name,
+			strconv.Itoa(item.Quantity),
+			item.Category,
+			authorID,
+			item.Author,
+			formatTime(item.AddedAt),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeJSON(w io.Writer, items []Item) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(document{Items: items})
+}
+
+// markdownReplacer escapes characters which break Markdown tables
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`, `|`, `\|`, "\n", " ", "*", `\*`, "_", `\_`, "`", "\\`",
+)
+
+func writeMarkdown(w io.Writer, items []Item) error {
+	lines := []string{
+		"| Item | Quantity | Category | Added by | Added at |",
+		"| --- | ---: | --- | --- | --- |",
+	}
+	for _, item := range items {
+		author := item.Author
+		if author == "" && item.AuthorID != 0 {
+			author = strconv.Itoa(item.AuthorID)
+		}
+
+		lines = append(lines, fmt.Sprintf("| %s | %d | %s | %s | %s |",
+			markdownReplacer.Replace(item.Name),
+			item.Quantity,
+			markdownReplacer.Replace(item.Category),
+			markdownReplacer.Replace(author),
+			formatTime(item.AddedAt)))
+	}
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// formatTime returns an empty string for unknown times
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(timeLayout)
+}
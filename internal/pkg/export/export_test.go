@@ -0,0 +1,156 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+)
+
+var testAddedAt = time.Date(2018, 10, 22, 7, 0, 0, 0, time.UTC)
+
+var testItems = []Item{
+	{
+		Name:     "milk",
+		Quantity: 2,
+		Category: "dairy",
+		AuthorID: 321,
+		Author:   "Alice",
+		AddedAt:  &testAddedAt,
+	},
+	{
+		Name:     "fish | chips",
+		Quantity: 1,
+		AuthorID: 654,
+	},
+}
+
+func TestParseFormat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected Format
+		isValid  bool
+	}{
+		{name: "", expected: FormatCSV, isValid: true},
+		{name: "csv", expected: FormatCSV, isValid: true},
+		{name: "JSON", expected: FormatJSON, isValid: true},
+		{name: "md", expected: FormatMarkdown, isValid: true},
+		{name: "xlsx", isValid: false},
+	}
+
+	for _, tc := range testCases {
+		format, err := ParseFormat(tc.name)
+		if tc.isValid && (err != nil || format != tc.expected) {
+			t.Errorf("Expected %#v for %#v, got %#v (err: %v)",
+				tc.expected, tc.name, format, err)
+		}
+		if !tc.isValid && err == nil {
+			t.Errorf("Expected an error for %#v", tc.name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+	chatID := int64(123)
+	createdAt := time.Date(2018, 10, 22, 10, 0, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+
+	t.Run("Success", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(chatID).Return([]*models.ShoppingItem{
+			{
+				ID: 1, ChatID: chatID, Name: "milk", Quantity: 2, Category: "dairy",
+				CreatedBy: 321, CreatedAt: &createdAt,
+			},
+			{ID: 2, ChatID: chatID, Name: "fish | chips", CreatedBy: 654},
+		}, nil)
+		stMock.EXPECT().GetActorNames(chatID).Return(map[int]string{321: "Alice"}, nil)
+
+		items, err := Load(stMock, chatID)
+		if err != nil {
+			t.Fatalf("Unexpected err: got %#v", err)
+		}
+
+		if !reflect.DeepEqual(items, testItems) {
+			t.Errorf("Expected %#v, got %#v", testItems, items)
+		}
+	})
+
+	t.Run("Storage error", func(t *testing.T) {
+		stMock.EXPECT().GetShoppingItems(chatID).Return(nil, errors.New("fake error"))
+
+		if _, err := Load(stMock, chatID); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestWrite(t *testing.T) {
+	testCases := []struct {
+		format   Format
+		expected string
+	}{
+		{
+			format: FormatCSV,
+			expected: "name,quantity,category,author_id,author,added_at\n" +
+				"milk,2,dairy,321,Alice,2018-10-22T07:00:00Z\n" +
+				"fish | chips,1,,654,,\n",
+		},
+		{
+			format: FormatJSON,
+			expected: `{
+  "items": [
+    {
+      "name": "milk",
+      "quantity": 2,
+      "category": "dairy",
+      "author_id": 321,
+      "author": "Alice",
+      "added_at": "2018-10-22T07:00:00Z"
+    },
+    {
+      "name": "fish | chips",
+      "quantity": 1,
+      "author_id": 654
+    }
+  ]
+}
+`,
+		},
+		{
+			format: FormatMarkdown,
+			expected: "| Item | Quantity | Category | Added by | Added at |\n" +
+				"| --- | ---: | --- | --- | --- |\n" +
+				"| milk | 2 | dairy | Alice | 2018-10-22T07:00:00Z |\n" +
+				"| fish \\| chips | 1 |  | 654 |  |\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tc.format, testItems); err != nil {
+				t.Fatalf("Unexpected err: got %#v", err)
+			}
+
+			if buf.String() != tc.expected {
+				t.Errorf("Expected %#v, got %#v", tc.expected, buf.String())
+			}
+		})
+	}
+
+	t.Run("Unsupported format", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Write(&buf, Format("xlsx"), testItems); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FormatText is a plain text list with an item per line.
+// Lists can be read from plain text, but not written
+const FormatText Format = "txt"
+
+// Limits of imported items
+const (
+	MaxNameLength = 255
+	MaxQuantity   = 999
+)
+
+// listMarkerRegexp matches markers of list items
+// which people use in notes: "- ", "* ", "• ", "- [ ] ", "1. "
+var listMarkerRegexp = regexp.MustCompile(`^(?:[-*•]|\d+[.)])\s+(?:\[[ xX]\]\s+)?`)
+
+// textQuantityRegexp matches lines like "2 x milk" or "2 × milk"
+var textQuantityRegexp = regexp.MustCompile(`^(\d+)\s*[x×*]\s+(.+)$`)
+
+// DetectFormat returns a format of a file by its name.
+// Files with unknown extensions are read as plain text
+func DetectFormat(fileName string) Format {
+	ext := strings.TrimPrefix(path.Ext(fileName), ".")
+	switch strings.ToLower(ext) {
+	case string(FormatCSV):
+		return FormatCSV
+	case string(FormatJSON):
+		return FormatJSON
+	}
+	return FormatText
+}
+
+// Read reads items in the format from r.
+// Names are trimmed and items without names are skipped.
+// Items without quantity are single items
+func Read(r io.Reader, format Format) ([]Item, error) {
+	var items []Item
+	var err error
+	switch format {
+	case FormatCSV:
+		items, err = readCSV(r)
+	case FormatJSON:
+		items, err = readJSON(r)
+	case FormatText:
+		items, err = readText(r)
+	default:
+		err = fmt.Errorf("Unsupported import format %#v", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cleanItems(items)
+}
+
+// readCSV reads files with a header like ours. Files without
+// the "name" column are read as a list of names in the first column
+func readCSV(r io.Reader) ([]Item, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for i, column := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	nameColumn, hasHeader := columns[csvHeader[0]]
+	if hasHeader {
+		rows = rows[1:]
+	} else {
+		columns = map[string]int{}
+	}
+
+	var items []Item
+	for i, row := range rows {
+		item := Item{Name: cell(row, nameColumn)}
+
+		if quantityColumn, ok := columns["quantity"]; ok {
+			if text := cell(row, quantityColumn); text != "" {
+				item.Quantity, err = strconv.Atoi(text)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"Invalid quantity %#v in the row %d", text, i+1)
+				}
+			}
+		}
+		if categoryColumn, ok := columns["category"]; ok {
+			item.Category = cell(row, categoryColumn)
+		}
+
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// cell returns a value of a cell or an empty string
+// if the row doesn't have the column
+func cell(row []string, column int) string {
+	if column >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[column])
+}
+
+func readJSON(r io.Reader) ([]Item, error) {
+	doc := document{}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	// We only take what users see in their lists
+	items := make([]Item, len(doc.Items))
+	for i, item := range doc.Items {
+		items[i] = Item{
+			Name:     item.Name,
+			Quantity: item.Quantity,
+			Category: item.Category,
+		}
+	}
+	return items, nil
+}
+
+func readText(r io.Reader) ([]Item, error) {
+	var items []Item
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		line = listMarkerRegexp.ReplaceAllString(line, "")
+
+		item := Item{Name: line}
+		if match := textQuantityRegexp.FindStringSubmatch(line); match != nil {
+			item.Quantity, _ = strconv.Atoi(match[1])
+			item.Name = match[2]
+		}
+
+		items = append(items, item)
+	}
+	return items, scanner.Err()
+}
+
+// cleanItems validates items and skips items without names
+func cleanItems(items []Item) ([]Item, error) {
+	cleaned := make([]Item, 0, len(items))
+	for _, item := range items {
+		item.Name = strings.TrimSpace(item.Name)
+		if item.Name == "" {
+			continue
+		}
+
+		if utf8.RuneCountInString(item.Name) > MaxNameLength {
+			return nil, fmt.Errorf(
+				"Name %#v is longer than %d characters", item.Name, MaxNameLength)
+		}
+
+		if item.Quantity < 0 || item.Quantity > MaxQuantity {
+			return nil, fmt.Errorf(
+				"Quantity of %#v must be between 1 and %d, got %d",
+				item.Name, MaxQuantity, item.Quantity)
+		}
+		if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+
+		cleaned = append(cleaned, item)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	testCases := []struct {
+		fileName string
+		expected Format
+	}{
+		{fileName: "shopping-list.csv", expected: FormatCSV},
+		{fileName: "List.JSON", expected: FormatJSON},
+		{fileName: "notes.txt", expected: FormatText},
+		{fileName: "notes", expected: FormatText},
+	}
+
+	for _, tc := range testCases {
+		if actual := DetectFormat(tc.fileName); actual != tc.expected {
+			t.Errorf("Expected %#v for %#v, got %#v", tc.expected, tc.fileName, actual)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	testCases := []struct {
+		name     string
+		format   Format
+		input    string
+		expected []Item
+		isValid  bool
+	}{
+		{
+			name:   "CSV export",
+			format: FormatCSV,
+			input: "name,quantity,category,author_id,author,added_at\n" +
+				"milk,2,dairy,321,Alice,2018-10-22T07:00:00Z\n" +
+				"bread,,,,,\n" +
+				",3,,,,\n",
+			expected: []Item{
+				{Name: "milk", Quantity: 2, Category: "dairy"},
+				{Name: "bread", Quantity: 1},
+			},
+			isValid: true,
+		},
+		{
+			name:   "CSV without header",
+			format: FormatCSV,
+			input:  "milk,whatever\nbread\n",
+			expected: []Item{
+				{Name: "milk", Quantity: 1},
+				{Name: "bread", Quantity: 1},
+			},
+			isValid: true,
+		},
+		{
+			name:    "CSV with invalid quantity",
+			format:  FormatCSV,
+			input:   "name,quantity\nmilk,lots\n",
+			isValid: false,
+		},
+		{
+			name:   "JSON export",
+			format: FormatJSON,
+			input: `{"items": [
+				{"name": " milk ", "quantity": 2, "category": "dairy", "author_id": 321},
+				{"name": "bread"}
+			]}`,
+			expected: []Item{
+				{Name: "milk", Quantity: 2, Category: "dairy"},
+				{Name: "bread", Quantity: 1},
+			},
+			isValid: true,
+		},
+		{
+			name:    "Invalid JSON",
+			format:  FormatJSON,
+			input:   `["milk"]`,
+			isValid: false,
+		},
+		{
+			name:   "Text",
+			format: FormatText,
+			input:  "milk\n\n- [ ] bread\n* 2 x eggs\n1. 3 × apples\n7up\n",
+			expected: []Item{
+				{Name: "milk", Quantity: 1},
+				{Name: "bread", Quantity: 1},
+				{Name: "eggs", Quantity: 2},
+				{Name: "apples", Quantity: 3},
+				{Name: "7up", Quantity: 1},
+			},
+			isValid: true,
+		},
+		{
+			name:    "Too long name",
+			format:  FormatText,
+			input:   strings.Repeat("a", MaxNameLength+1),
+			isValid: false,
+		},
+		{
+			name:    "Too many items",
+			format:  FormatText,
+			input:   "1000 x milk",
+			isValid: false,
+		},
+		{
+			name:    "Unsupported format",
+			format:  FormatMarkdown,
+			input:   "| milk |",
+			isValid: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			items, err := Read(strings.NewReader(tc.input), tc.format)
+			if !tc.isValid {
+				if err == nil {
+					t.Errorf("Expected an error, got %#v", items)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected err: got %#v", err)
+			}
+
+			if !reflect.DeepEqual(items, tc.expected) {
+				t.Errorf("Expected %#v, got %#v", tc.expected, items)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package i18n
+
+var englishCatalog = &catalog{
+	name: "English",
+
+	pluralForms: []pluralForm{formOne, formOther},
+	pluralForm: func(n int) pluralForm {
+		if n == 1 {
+			return formOne
+		}
+		return formOther
+	},
+
+	messages: map[string]string{
+		"help.greeting":              "Hi %s,",
+		"help.not_understood":        "%s, I'm very sorry, but I don't understand you.",
+		"help.intro":                 "I can help you to manage your shopping list.\n\nYou can control me by sending these commands:",
+		"help.section.shopping_list": "Shopping list",
+		"help.section.settings":      "Settings",
+
+		"command.help.description":     "Shows this message",
+		"command.add.description":      "Adds an item into your shopping list",
+		"command.list.description":     "Displays items from your shopping list",
+		"command.del.description":      "Removes an item from your shopping list",
+		"command.dedupe.description":   "Merges items with the same name",
+		"command.clear.description":    "Removes all items from the shopping list",
+		"command.language.description": "Changes the language I speak to you",
+		"command.category.description": "Teaches me a category of an item",
+		"command.aisles.description":   "Changes the order of categories in the list",
+		"command.sort.description":     "Changes the order of items in the list",
+		"command.staples.description":  "Adds items into the list on schedule",
+		"command.remind.description":   "Sends the list into this chat on schedule",
+		"command.log.description":      "Shows who changed the list recently",
+		"command.stats.description":    "Shows statistics of the list",
+		"command.export.description":   "Sends the list as a file",
+		"command.import.description":   "Adds items from a file or a pasted list",
+		"command.recipe.description":   "Saves ingredients of recipes",
+		"command.cook.description":     "Adds ingredients of a recipe into the list",
+		"command.budget.description":   "Sets how much you plan to spend on the list",
+		"command.assign.description":   "Assigns items to members of the chat",
+		"command.mine.description":     "Shows items assigned to you",
+		"command.settings.description": "Changes settings of this chat",
+
+		"command.arg.item":       "item",
+		"command.arg.language":   "language",
+		"command.arg.order":      "order",
+		"command.arg.category":   "category",
+		"command.arg.categories": "categories",
+		"command.arg.schedule":   "schedule",
+		"command.arg.chart":      "chart",
+		"command.arg.format":     "format",
+		"command.arg.items":      "items",
+		"command.arg.recipe":     "recipe",
+		"command.arg.servings":   "servings",
+		"command.arg.amount":     "amount",
+
+		"error.unrecoverable": "Sorry, but something went wrong. I'll inform developers about this issue. Please, try again a bit later.",
+
+		"add.prompt":             "Ok %s, what do you want to add into your shopping list?",
+		"add.done":               "Lovely! I've added \"%s\" into your shopping list. Anything else?",
+		"add.duplicate":          "\"%s\" is already in your shopping list. Do you need more?",
+		"add.duplicate.increase": "Yes, buy %d",
+		"add.duplicate.anyway":   "Add anyway",
+		"add.increased":          "Ok, you need %d × \"%s\" now.",
+		"add.frequent":           "Or tap an item which you often buy:",
+		"add.not_found":          "Can't find this item, someone has probably removed it. Please, add it again.",
+
+		"list.empty": "Your shopping list is empty. Who knows, maybe it's a good thing",
+
+		"del.empty":            "Your shopping list is empty. No need to delete items 🙂",
+		"del.prompt":           "Ok, what item do you want to delete from your shopping list?",
+		"del.done":             "It's nice to see that you think that you don't need this \"%s\" thing. I've removed it from your shopping list.\n\nCan I do anything else for you?",
+		"del.not_found":        "Can't find an item, sorry.",
+		"del.not_found.prompt": "Sorry, I can't find \"%s\". What item do you want to delete from your shopping list?",
+		"del.not_found.many":   "Sorry, I can't find these items: %s.",
+		"del.ambiguous.prompt": "I've found several items like \"%s\". Which one do you want to delete?",
+		"del.similar.prompt":   "I can't find \"%s\" exactly, but I've found something similar. Do you want to delete it?",
+
+		"dedupe.none": "There are no duplicates in your shopping list.",
+
+		"category.prompt":  "Tell me an item and its category, for example: /category milk dairy\n\nCategories: %s",
+		"category.unknown": "Sorry, I don't know the \"%s\" category. Categories: %s",
+		"category.done":    "Got it! \"%s\" goes to the %s category.",
+
+		"aisles.prompt": "Items in your shopping list are grouped in this order:\n%s\n\nTo change it, list categories in the order of aisles in your store, for example: /aisles produce, bakery, dairy",
+		"aisles.done":   "Done! From now on I'll group items in this order:\n%s",
+
+		"sort.prompt":      "How should I sort items in your shopping list?",
+		"sort.unknown":     "Sorry, I can't sort items \"%s\". Try one of these: %s",
+		"sort.done":        "Done! From now on I'll sort items %s.",
+		"sort.move":        "Arrange items manually",
+		"sort.move.prompt": "Tap ⬆ or ⬇ to move items. I'll keep this order from now on.",
+
+		"staples.prompt":              "I add these items into your shopping list on schedule. Tap an item to stop adding it.\n\nTo add a staple: /staples milk weekly",
+		"staples.empty":               "You don't have staples yet. Staples are items which I add into your shopping list on schedule, for example: /staples milk weekly",
+		"staples.done":                "Done! I'll add \"%s\" into your shopping list %s.",
+		"staples.button":              "✕ %s, %s",
+		"staples.schedule.daily":      "every day",
+		"staples.schedule.weekly":     "every week",
+		"staples.schedule.daily.arg":  "daily",
+		"staples.schedule.weekly.arg": "weekly",
+
+		"remind.prompt":  "Reminders of this chat. Tap a reminder to delete it.",
+		"remind.empty":   "There are no reminders in this chat yet. I can send your shopping list here on schedule.",
+		"remind.help":    "\n\nTo add a reminder: /remind saturday 10:00\nMore examples: /remind daily 9:00, /remind weekdays 18:30, /remind tomorrow 18:30, /remind 2018-12-24 12:00, /remind cron 0 10 * * 6\n\nTimes are in the %s timezone, you can change it in /settings.",
+		"remind.invalid": "Sorry, I don't understand when to remind you: \"%s\".",
+		"remind.done":    "Done! I'll send your shopping list here: %s. Next time: %s.",
+		"remind.button":  "✕ %s, next: %s",
+		"remind.fired":   "⏰ Time to go shopping!",
+
+		"log.header":  "Recent changes of your shopping list:",
+		"log.empty":   "Nobody has changed your shopping list recently.",
+		"log.bot":     "I",
+		"log.add":     "%s added %s",
+		"log.delete":  "%s removed %s",
+		"log.clear":   "%s cleared the list: %s",
+		"log.edit":    "%s changed %s → %s",
+		"log.reorder": "%s reordered the list",
+
+		"stats.weekly":        "Items added per week:",
+		"stats.top_items":     "Most frequent items:",
+		"stats.top_users":     "Who adds the most:",
+		"stats.chart.arg":     "chart",
+		"stats.chart.caption": "Items added per week from %s to %s",
+
+		"export.invalid": "Sorry, I can't export the list as \"%s\". Supported formats: %s.",
+
+		"import.prompt":    "Send me a file with items (CSV, JSON from /export or plain text) or paste items, one per line.",
+		"import.invalid":   "Sorry, I can't find items there. I understand CSV and JSON files from /export and plain text with one item per line.",
+		"import.too_large": "Sorry, the file is too large. I can import files up to %d KB.",
+		"import.confirm":   "Add",
+		"import.cancel":    "Cancel",
+		"import.canceled":  "Canceling. I haven't added anything.",
+		"import.expired":   "Sorry, this preview has expired. Please, send the items again with /import.",
+
+		"recipe.prompt":    "Recipes of this chat. Tap a recipe to delete it.",
+		"recipe.empty":     "There are no recipes yet.",
+		"recipe.help":      "\n\nTo save a recipe, send me its name and ingredients: /recipe pancakes: flour, 2 eggs, milk\nTo add ingredients into the list: /cook pancakes or /cook pancakes 2 for double servings.",
+		"recipe.button":    "✕ %s: %s",
+		"recipe.show":      "%s: %s.",
+		"recipe.done":      "Done! I've saved the recipe \"%s\": %s.\n\nAdd its ingredients into the list with /cook %s",
+		"recipe.not_found": "I don't know the recipe \"%s\" yet.",
+		"recipe.invalid":   "Sorry, I don't understand the recipe. Separate ingredients with commas, I can save up to %d ingredients.",
+
+		"cook.prompt":    "Which recipe do you want to cook? Recipes of this chat: %s.",
+		"cook.not_found": "I don't know the recipe \"%s\". Recipes of this chat: %s.",
+		"cook.done":      "Done! I've added ingredients of \"%s\" into your shopping list: %s.",
+
+		"list.total":           "Estimated total: %s",
+		"list.budget":          "Budget: %s",
+		"list.budget.exceeded": "⚠️ The list is over the budget by %s",
+
+		"budget.none":    "This list doesn't have a budget yet.",
+		"budget.current": "The budget of this list is %s. Items with prices cost %s so far.",
+		"budget.help":    "\n\nTo set the budget: /budget 50\nTo remove it: /budget 0\nTo add an item with its price: /add milk %s",
+		"budget.done":    "Done! The budget of this list is %s.",
+		"budget.removed": "Ok, I've removed the budget of this list.",
+		"budget.invalid": "Sorry, I don't understand the amount. Send me a number like 50 or 49.99.",
+
+		"assign.prompt":        "Which item do you want to assign?",
+		"assign.member.prompt": "Who buys \"%s\"?",
+		"assign.nobody":        "Nobody",
+		"assign.someone":       "someone",
+		"assign.done":          "%s, you're buying \"%s\".",
+		"assign.removed":       "Ok, nobody is assigned to \"%s\" now.",
+		"assign.not_found":     "I can't find \"%s\" in your shopping list.",
+
+		"mine.empty": "Nobody has assigned items to you yet.",
+
+		"clear.confirm":            "Are you sure that you want to %s from your shopping list?",
+		"clear.confirm.remove_all": "remove all items",
+		"clear.yes":                "Yes",
+		"clear.cancel":             "Cancel",
+		"clear.done":               "Ok, I've deleted all items from your shopping list.\n\nNow you can start from scratch, if you wish.",
+		"clear.canceled":           "Canceling. Your items are still in your list.",
+
+		"language.prompt": "Which language do you want me to speak?",
+		"language.done":   "Done! From now on I'll speak English to you.",
+
+		"settings.title":               "Settings of this chat. Tap a button to change a setting.",
+		"settings.language":            "Language: %s",
+		"settings.language.users":      "Language of each user",
+		"settings.language.prompt":     "Which language should I speak in this chat?",
+		"settings.sort_order":          "Sort items: %s",
+		"settings.sort_order.added":    "by date added",
+		"settings.sort_order.name":     "by name",
+		"settings.sort_order.category": "by category",
+		"settings.sort_order.author":   "by who added",
+		"settings.sort_order.manual":   "manually",
+		"settings.confirm_clear":       "Confirm /clear: %s",
+		"settings.admins_only":         "Only admins clear the list and change settings: %s",
+		"settings.natural_language":    "Understand messages without commands: %s",
+		"settings.on":                  "on",
+		"settings.off":                 "off",
+		"settings.timezone":            "Timezone: %s",
+		"settings.timezone.prompt":     "Which timezone is this chat in?",
+		"settings.currency":            "Currency: %s",
+		"settings.currency.prompt":     "Which currency do you use for prices?",
+		"settings.back":                "« Back",
+		"settings.close":               "Done",
+
+		"category.produce":   "Produce",
+		"category.bakery":    "Bakery",
+		"category.meat":      "Meat",
+		"category.dairy":     "Dairy",
+		"category.frozen":    "Frozen",
+		"category.pantry":    "Pantry",
+		"category.drinks":    "Drinks",
+		"category.household": "Household",
+		"category.other":     "Other",
+
+		"permission.denied": "Sorry, only administrators of this chat can do this.",
+
+		"inline.add.title":       "Add \"%s\" to my list",
+		"inline.add.description": "The item will be added into your shopping list",
+		"inline.add.message":     "I've added \"%s\" to my shopping list 🛒",
+		"inline.share.title":     "Share my list",
+		"inline.share.empty":     "My shopping list is empty",
+	},
+
+	plurals: map[string]map[pluralForm]string{
+		"list.header": {
+			formOne:   "There is %d item in your shopping list:",
+			formOther: "There are %d items in your shopping list:",
+		},
+		"inline.share.header": {
+			formOne:   "There is %d item in my shopping list:",
+			formOther: "There are %d items in my shopping list:",
+		},
+		"dedupe.done": {
+			formOne:   "Done! I've merged %d duplicate into other items.",
+			formOther: "Done! I've merged %d duplicates into other items.",
+		},
+		"staples.schedule.days": {
+			formOne:   "every %d day",
+			formOther: "every %d days",
+		},
+		"staples.added": {
+			formOne:   "It's time to buy staples again. I've added %d item into your shopping list: %s.",
+			formOther: "It's time to buy staples again. I've added %d items into your shopping list: %s.",
+		},
+		"stats.title": {
+			formOne:   "Statistics of your shopping list for the last %d week",
+			formOther: "Statistics of your shopping list for the last %d weeks",
+		},
+		"stats.empty": {
+			formOne:   "Nobody has added or bought anything in the last %d week.",
+			formOther: "Nobody has added or bought anything in the last %d weeks.",
+		},
+		"stats.purchases": {
+			formOne:   "%d item was bought. Items stay in the list for %s on average.",
+			formOther: "%d items were bought. Items stay in the list for %s on average.",
+		},
+		"stats.duration.days": {
+			formOne:   "%d day",
+			formOther: "%d days",
+		},
+		"stats.duration.hours": {
+			formOne:   "%d hour",
+			formOther: "%d hours",
+		},
+		"stats.duration.minutes": {
+			formOne:   "%d minute",
+			formOther: "%d minutes",
+		},
+		"export.done": {
+			formOne:   "Your shopping list with %d item",
+			formOther: "Your shopping list with %d items",
+		},
+		"import.preview": {
+			formOne:   "I'll add %d item into your shopping list:",
+			formOther: "I'll add %d items into your shopping list:",
+		},
+		"import.more": {
+			formOne:   "…and %d more item",
+			formOther: "…and %d more items",
+		},
+		"import.too_many": {
+			formOne:   "Sorry, I can import up to %d item at once.",
+			formOther: "Sorry, I can import up to %d items at once.",
+		},
+		"import.done": {
+			formOne:   "Done! I've added %d item into your shopping list.",
+			formOther: "Done! I've added %d items into your shopping list.",
+		},
+		"import.skipped": {
+			formOne:   "%d item was already in the list.",
+			formOther: "%d items were already in the list.",
+		},
+		"cook.merged": {
+			formOne:   "%d of them was already in the list, so I've increased its quantity.",
+			formOther: "%d of them were already in the list, so I've increased their quantity.",
+		},
+		"list.total.unpriced": {
+			formOne:   "(%d item without a price)",
+			formOther: "(%d items without a price)",
+		},
+		"mine.header": {
+			formOne:   "You're buying %d item:",
+			formOther: "You're buying %d items:",
+		},
+		"del.done.many": {
+			formOne:   "I've removed %d item from your shopping list: %s.",
+			formOther: "I've removed %d items from your shopping list: %s.",
+		},
+	},
+}
@@ -0,0 +1,165 @@
+// Package i18n contains translations of the bot messages
+// and helps to pick the right translation for an user.
+//
+// Messages are identified by keys and are fmt format strings.
+// Messages that depend on a number have plural forms
+// which are chosen using plural rules of a language.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is used when we don't support a locale of an user
+const DefaultLocale = "en"
+
+// pluralForm represents a CLDR plural category
+//
+// See: http://cldr.unicode.org/index/cldr-spec/plural-rules
+type pluralForm int
+
+const (
+	formOne pluralForm = iota
+	formFew
+	formMany
+	formOther
+)
+
+// catalog contains all messages for a single language
+type catalog struct {
+	// name is a name of the language in this language
+	name string
+
+	// pluralForms lists plural forms used in the language
+	pluralForms []pluralForm
+	// pluralForm returns a plural form for a number
+	pluralForm func(n int) pluralForm
+
+	messages map[string]string
+	plurals  map[string]map[pluralForm]string
+}
+
+// catalogs contains all supported languages
+var catalogs = map[string]*catalog{
+	"en": englishCatalog,
+	"uk": ukrainianCatalog,
+}
+
+// Locales returns all supported locales
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	return locales
+}
+
+// IsSupported checks if we have translations for a locale
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// LocaleName returns name of a locale in its language
+func LocaleName(locale string) string {
+	if c, ok := catalogs[locale]; ok {
+		return c.name
+	}
+	return locale
+}
+
+// MatchLocale returns the best supported locale for a language tag.
+// For example, "uk-UA" matches "uk". It falls back to DefaultLocale.
+func MatchLocale(languageTag string) string {
+	languageTag = strings.ToLower(languageTag)
+	if IsSupported(languageTag) {
+		return languageTag
+	}
+
+	if i := strings.IndexAny(languageTag, "-_"); i > 0 {
+		if base := languageTag[:i]; IsSupported(base) {
+			return base
+		}
+	}
+
+	return DefaultLocale
+}
+
+// Translator translates messages into a specific language
+type Translator struct {
+	locale  string
+	catalog *catalog
+}
+
+// NewTranslator creates a translator for the best matching locale
+func NewTranslator(languageTag string) *Translator {
+	locale := MatchLocale(languageTag)
+
+	return &Translator{
+		locale:  locale,
+		catalog: catalogs[locale],
+	}
+}
+
+// Locale returns a locale of the translator
+func (t *Translator) Locale() string {
+	return t.locale
+}
+
+// Template returns an untranslated template for a message.
+//
+// It returns the key itself, if the message doesn't exist.
+func (t *Translator) Template(key string) string {
+	if message, ok := t.catalog.messages[key]; ok {
+		return message
+	}
+
+	if message, ok := catalogs[DefaultLocale].messages[key]; ok {
+		return message
+	}
+
+	return key
+}
+
+// PluralTemplate returns a template for a message
+// in a plural form that matches `n`
+//
+// It returns the key itself, if the message doesn't exist.
+func (t *Translator) PluralTemplate(key string, n int) string {
+	if forms, ok := t.catalog.plurals[key]; ok {
+		if message, ok := forms[t.catalog.pluralForm(n)]; ok {
+			return message
+		}
+	}
+
+	defaultCatalog := catalogs[DefaultLocale]
+	if forms, ok := defaultCatalog.plurals[key]; ok {
+		if message, ok := forms[defaultCatalog.pluralForm(n)]; ok {
+			return message
+		}
+	}
+
+	return key
+}
+
+// T returns a translated message formatted with `args`
+func (t *Translator) T(key string, args ...interface{}) string {
+	return sprintf(t.Template(key), args)
+}
+
+// N returns a translated message in a plural form
+// that matches `n` formatted with `args`
+func (t *Translator) N(key string, n int, args ...interface{}) string {
+	return sprintf(t.PluralTemplate(key, n), args)
+}
+
+func sprintf(format string, args []interface{}) string {
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,180 @@
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+)
+
+var formatVerbRegexp = regexp.MustCompile(`%(\[\d+\])?[a-z]`)
+
+// formatVerbs returns sorted format verbs from a message,
+// so we can compare them regardless of their order
+func formatVerbs(message string) []string {
+	verbs := formatVerbRegexp.FindAllString(message, -1)
+	sort.Strings(verbs)
+	return verbs
+}
+
+func equalVerbs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCatalogsAreComplete(t *testing.T) {
+	defaultCatalog := catalogs[DefaultLocale]
+
+	for locale, c := range catalogs {
+		t.Run(locale, func(t *testing.T) {
+			if c.name == "" {
+				t.Error("Expected the catalog to have a name")
+			}
+
+			for key, defaultMessage := range defaultCatalog.messages {
+				message, ok := c.messages[key]
+				if !ok {
+					t.Errorf("Message %#v is missing", key)
+					continue
+				}
+
+				expectedVerbs := formatVerbs(defaultMessage)
+				if verbs := formatVerbs(message); !equalVerbs(verbs, expectedVerbs) {
+					t.Errorf("Message %#v: expected format verbs %v, got %v",
+						key, expectedVerbs, verbs)
+				}
+			}
+
+			for key := range c.messages {
+				if _, ok := defaultCatalog.messages[key]; !ok {
+					t.Errorf("Message %#v is not in the default catalog", key)
+				}
+			}
+
+			for key := range defaultCatalog.plurals {
+				forms, ok := c.plurals[key]
+				if !ok {
+					t.Errorf("Plural message %#v is missing", key)
+					continue
+				}
+
+				for _, form := range c.pluralForms {
+					if _, ok := forms[form]; !ok {
+						t.Errorf("Plural message %#v: form %d is missing", key, form)
+					}
+				}
+			}
+
+			for key := range c.plurals {
+				if _, ok := defaultCatalog.plurals[key]; !ok {
+					t.Errorf("Plural message %#v is not in the default catalog", key)
+				}
+			}
+		})
+	}
+}
+
+func TestPluralForms(t *testing.T) {
+	testCases := []struct {
+		locale   string
+		n        int
+		expected pluralForm
+	}{
+		{locale: "en", n: 0, expected: formOther},
+		{locale: "en", n: 1, expected: formOne},
+		{locale: "en", n: 2, expected: formOther},
+		{locale: "en", n: 11, expected: formOther},
+		{locale: "uk", n: 0, expected: formMany},
+		{locale: "uk", n: 1, expected: formOne},
+		{locale: "uk", n: 2, expected: formFew},
+		{locale: "uk", n: 5, expected: formMany},
+		{locale: "uk", n: 11, expected: formMany},
+		{locale: "uk", n: 12, expected: formMany},
+		{locale: "uk", n: 21, expected: formOne},
+		{locale: "uk", n: 22, expected: formFew},
+		{locale: "uk", n: 111, expected: formMany},
+	}
+
+	for _, testCase := range testCases {
+		form := catalogs[testCase.locale].pluralForm(testCase.n)
+		if form != testCase.expected {
+			t.Errorf("%s, %d: expected form %d, got %d",
+				testCase.locale, testCase.n, testCase.expected, form)
+		}
+	}
+}
+
+func TestMatchLocale(t *testing.T) {
+	testCases := []struct {
+		languageTag string
+		expected    string
+	}{
+		{languageTag: "", expected: DefaultLocale},
+		{languageTag: "en", expected: "en"},
+		{languageTag: "en-US", expected: "en"},
+		{languageTag: "uk", expected: "uk"},
+		{languageTag: "UK-ua", expected: "uk"},
+		{languageTag: "uk_UA", expected: "uk"},
+		{languageTag: "xx-YY", expected: DefaultLocale},
+	}
+
+	for _, testCase := range testCases {
+		locale := MatchLocale(testCase.languageTag)
+		if locale != testCase.expected {
+			t.Errorf("%#v: expected %#v, got %#v",
+				testCase.languageTag, testCase.expected, locale)
+		}
+	}
+}
+
+func TestTranslator(t *testing.T) {
+	t.Run("Translate", func(t *testing.T) {
+		tr := NewTranslator("uk-UA")
+		if tr.Locale() != "uk" {
+			t.Errorf("Expected locale %#v, got %#v", "uk", tr.Locale())
+		}
+
+		expected := "Привіт, m1kola!"
+		if text := tr.T("help.greeting", "m1kola"); text != expected {
+			t.Errorf("Expected %#v, got %#v", expected, text)
+		}
+	})
+
+	t.Run("Plural", func(t *testing.T) {
+		tr := NewTranslator("uk")
+
+		expected := "У вашому списку покупок 3 товари:"
+		if text := tr.N("list.header", 3, 3); text != expected {
+			t.Errorf("Expected %#v, got %#v", expected, text)
+		}
+	})
+
+	t.Run("Missing message", func(t *testing.T) {
+		tr := NewTranslator("en")
+
+		expected := "missing.key"
+		if text := tr.T(expected); text != expected {
+			t.Errorf("Expected %#v, got %#v", expected, text)
+		}
+		if text := tr.N(expected, 1); text != expected {
+			t.Errorf("Expected %#v, got %#v", expected, text)
+		}
+	})
+
+	t.Run("Fallback to the default catalog", func(t *testing.T) {
+		tr := &Translator{locale: "uk", catalog: &catalog{
+			pluralForm: englishCatalog.pluralForm,
+		}}
+
+		expected := englishCatalog.messages["list.empty"]
+		if text := tr.T("list.empty"); text != expected {
+			t.Errorf("Expected %#v, got %#v", expected, text)
+		}
+	})
+}
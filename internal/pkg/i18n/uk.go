@@ -0,0 +1,332 @@
+package i18n
+
+var ukrainianCatalog = &catalog{
+	name: "Українська",
+
+	pluralForms: []pluralForm{formOne, formFew, formMany},
+	pluralForm: func(n int) pluralForm {
+		mod10, mod100 := n%10, n%100
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return formOne
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return formFew
+		}
+		return formMany
+	},
+
+	messages: map[string]string{
+		"help.greeting":              "Привіт, %s!",
+		"help.not_understood":        "%s, вибачте, але я вас не розумію.",
+		"help.intro":                 "Я можу допомогти вам вести список покупок.\n\nВи можете керувати мною за допомогою цих команд:",
+		"help.section.shopping_list": "Список покупок",
+		"help.section.settings":      "Налаштування",
+
+		"command.help.description":     "Показує це повідомлення",
+		"command.add.description":      "Додає товар до списку покупок",
+		"command.list.description":     "Показує товари зі списку покупок",
+		"command.del.description":      "Видаляє товар зі списку покупок",
+		"command.dedupe.description":   "Об'єднує товари з однаковою назвою",
+		"command.clear.description":    "Видаляє всі товари зі списку покупок",
+		"command.language.description": "Змінює мову, якою я з вами розмовляю",
+		"command.category.description": "Вчить мене категорії товару",
+		"command.aisles.description":   "Змінює порядок категорій у списку",
+		"command.sort.description":     "Змінює порядок товарів у списку",
+		"command.staples.description":  "Додає товари до списку за розкладом",
+		"command.remind.description":   "Надсилає список у цей чат за розкладом",
+		"command.log.description":      "Показує, хто нещодавно змінював список",
+		"command.stats.description":    "Показує статистику списку",
+		"command.export.description":   "Надсилає список файлом",
+		"command.import.description":   "Додає товари з файлу або вставленого списку",
+		"command.recipe.description":   "Зберігає інгредієнти рецептів",
+		"command.cook.description":     "Додає інгредієнти рецепта до списку",
+		"command.budget.description":   "Встановлює, скільки ви плануєте витратити на список",
+		"command.assign.description":   "Доручає товари учасникам чату",
+		"command.mine.description":     "Показує товари, доручені вам",
+		"command.settings.description": "Змінює налаштування цього чату",
+
+		"command.arg.item":       "товар",
+		"command.arg.language":   "мова",
+		"command.arg.order":      "порядок",
+		"command.arg.category":   "категорія",
+		"command.arg.schedule":   "розклад",
+		"command.arg.chart":      "графік",
+		"command.arg.format":     "формат",
+		"command.arg.items":      "товари",
+		"command.arg.categories": "категорії",
+		"command.arg.recipe":     "рецепт",
+		"command.arg.servings":   "порції",
+		"command.arg.amount":     "сума",
+
+		"error.unrecoverable": "Вибачте, але щось пішло не так. Я повідомлю розробників про цю проблему. Будь ласка, спробуйте трохи пізніше.",
+
+		"add.prompt":             "Гаразд, %s, що ви хочете додати до списку покупок?",
+		"add.done":               "Чудово! Я додав «%s» до вашого списку покупок. Щось іще?",
+		"add.duplicate":          "«%s» вже є у вашому списку покупок. Вам потрібно більше?",
+		"add.duplicate.increase": "Так, купити %d",
+		"add.duplicate.anyway":   "Все одно додати",
+		"add.increased":          "Гаразд, тепер вам потрібно %d × «%s».",
+		"add.frequent":           "Або натисніть на товар, який ви часто купуєте:",
+		"add.not_found":          "Не можу знайти цей товар, мабуть, хтось його видалив. Будь ласка, додайте його знову.",
+
+		"list.empty": "Ваш список покупок порожній. Хто знає, можливо, це на краще",
+
+		"del.empty":            "Ваш список покупок порожній. Нічого видаляти 🙂",
+		"del.prompt":           "Гаразд, який товар ви хочете видалити зі списку покупок?",
+		"del.done":             "Приємно бачити, що вам більше не потрібно «%s». Я видалив це зі списку покупок.\n\nЧим іще можу допомогти?",
+		"del.not_found":        "Вибачте, не можу знайти цей товар.",
+		"del.not_found.prompt": "Вибачте, не можу знайти «%s». Який товар ви хочете видалити зі списку покупок?",
+		"del.not_found.many":   "Вибачте, не можу знайти ці товари: %s.",
+		"del.ambiguous.prompt": "Я знайшов кілька товарів, схожих на «%s». Який із них ви хочете видалити?",
+		"del.similar.prompt":   "Я не знайшов саме «%s», але знайшов щось схоже. Хочете це видалити?",
+
+		"dedupe.none": "У вашому списку покупок немає дублікатів.",
+
+		"category.prompt":  "Вкажіть товар і його категорію, наприклад: /category молоко молочне\n\nКатегорії: %s",
+		"category.unknown": "Вибачте, я не знаю категорії «%s». Категорії: %s",
+		"category.done":    "Зрозумів! «%s» тепер у категорії «%s».",
+
+		"aisles.prompt": "Товари у вашому списку покупок згруповані в такому порядку:\n%s\n\nЩоб змінити його, перелічіть категорії в порядку рядів у вашому магазині, наприклад: /aisles овочі, випічка, молочне",
+		"aisles.done":   "Готово! Відтепер я групуватиму товари в такому порядку:\n%s",
+
+		"sort.prompt":      "Як мені сортувати товари у вашому списку покупок?",
+		"sort.unknown":     "Вибачте, я не вмію сортувати товари «%s». Спробуйте один із варіантів: %s",
+		"sort.done":        "Готово! Відтепер я сортуватиму товари %s.",
+		"sort.move":        "Упорядкувати товари вручну",
+		"sort.move.prompt": "Натискайте ⬆ або ⬇, щоб переміщати товари. Я збережу цей порядок.",
+
+		"staples.prompt":              "Я додаю ці товари до списку покупок за розкладом. Натисніть на товар, щоб я більше його не додавав.\n\nЩоб додати постійний товар: /staples молоко щотижня",
+		"staples.empty":               "У вас ще немає постійних товарів. Це товари, які я додаю до списку покупок за розкладом, наприклад: /staples молоко щотижня",
+		"staples.done":                "Готово! Я додаватиму «%s» до списку покупок %s.",
+		"staples.button":              "✕ %s, %s",
+		"staples.schedule.daily":      "щодня",
+		"staples.schedule.weekly":     "щотижня",
+		"staples.schedule.daily.arg":  "щодня",
+		"staples.schedule.weekly.arg": "щотижня",
+
+		"remind.prompt":  "Нагадування цього чату. Натисніть на нагадування, щоб видалити його.",
+		"remind.empty":   "У цьому чаті ще немає нагадувань. Я можу надсилати сюди список покупок за розкладом.",
+		"remind.help":    "\n\nЩоб додати нагадування: /remind суботу 10:00\nІнші приклади: /remind щодня 9:00, /remind будні 18:30, /remind завтра 18:30, /remind 2018-12-24 12:00, /remind cron 0 10 * * 6\n\nЧас вказано в часовому поясі %s, його можна змінити в /settings.",
+		"remind.invalid": "Вибачте, я не розумію, коли вам нагадати: «%s».",
+		"remind.done":    "Готово! Я надсилатиму сюди список покупок: %s. Наступного разу: %s.",
+		"remind.button":  "✕ %s, наступне: %s",
+		"remind.fired":   "⏰ Час іти по покупки!",
+
+		"log.header":  "Останні зміни вашого списку покупок:",
+		"log.empty":   "Останнім часом ніхто не змінював ваш список покупок.",
+		"log.bot":     "Бот",
+		"log.add":     "%s: додано %s",
+		"log.delete":  "%s: видалено %s",
+		"log.clear":   "%s: список очищено: %s",
+		"log.edit":    "%s: змінено %s → %s",
+		"log.reorder": "%s: змінено порядок товарів",
+
+		"stats.weekly":        "Додано товарів за тиждень:",
+		"stats.top_items":     "Найчастіші товари:",
+		"stats.top_users":     "Хто додає найбільше:",
+		"stats.chart.arg":     "графік",
+		"stats.chart.caption": "Додано товарів за тиждень з %s по %s",
+
+		"export.invalid": "Вибачте, я не можу експортувати список у форматі \"%s\". Підтримувані формати: %s.",
+
+		"import.prompt":    "Надішліть мені файл з товарами (CSV, JSON з /export або звичайний текст) або вставте товари, по одному в рядку.",
+		"import.invalid":   "Вибачте, я не знайшов там товарів. Я розумію файли CSV і JSON з /export та звичайний текст з одним товаром у рядку.",
+		"import.too_large": "Вибачте, файл завеликий. Я можу імпортувати файли до %d КБ.",
+		"import.confirm":   "Додати",
+		"import.cancel":    "Скасувати",
+		"import.canceled":  "Скасовую. Я нічого не додав.",
+		"import.expired":   "Вибачте, цей попередній перегляд застарів. Будь ласка, надішліть товари знову з /import.",
+
+		"recipe.prompt":    "Рецепти цього чату. Натисніть на рецепт, щоб видалити його.",
+		"recipe.empty":     "Рецептів ще немає.",
+		"recipe.help":      "\n\nЩоб зберегти рецепт, надішліть мені його назву та інгредієнти: /recipe млинці: борошно, 2 яйця, молоко\nЩоб додати інгредієнти до списку: /cook млинці або /cook млинці 2 для подвійної порції.",
+		"recipe.button":    "✕ %s: %s",
+		"recipe.show":      "%s: %s.",
+		"recipe.done":      "Готово! Я зберіг рецепт \"%s\": %s.\n\nДодайте його інгредієнти до списку командою /cook %s",
+		"recipe.not_found": "Я ще не знаю рецепта \"%s\".",
+		"recipe.invalid":   "Вибачте, я не розумію рецепт. Розділяйте інгредієнти комами, я можу зберегти до %d інгредієнтів.",
+
+		"cook.prompt":    "Який рецепт ви хочете приготувати? Рецепти цього чату: %s.",
+		"cook.not_found": "Я не знаю рецепта \"%s\". Рецепти цього чату: %s.",
+		"cook.done":      "Готово! Я додав інгредієнти \"%s\" до вашого списку покупок: %s.",
+
+		"list.total":           "Орієнтовна сума: %s",
+		"list.budget":          "Бюджет: %s",
+		"list.budget.exceeded": "⚠️ Список перевищує бюджет на %s",
+
+		"budget.none":    "У цього списку ще немає бюджету.",
+		"budget.current": "Бюджет цього списку — %s. Товари з цінами поки коштують %s.",
+		"budget.help":    "\n\nЩоб встановити бюджет: /budget 50\nЩоб видалити його: /budget 0\nЩоб додати товар з ціною: /add молоко %s",
+		"budget.done":    "Готово! Бюджет цього списку — %s.",
+		"budget.removed": "Гаразд, я видалив бюджет цього списку.",
+		"budget.invalid": "Вибачте, я не розумію суму. Надішліть мені число, наприклад 50 або 49.99.",
+
+		"assign.prompt":        "Який товар ви хочете доручити?",
+		"assign.member.prompt": "Хто купує \"%s\"?",
+		"assign.nobody":        "Ніхто",
+		"assign.someone":       "хтось",
+		"assign.done":          "%s, ви купуєте \"%s\".",
+		"assign.removed":       "Гаразд, тепер \"%s\" нікому не доручено.",
+		"assign.not_found":     "Я не можу знайти \"%s\" у вашому списку покупок.",
+
+		"mine.empty": "Вам ще не доручили жодного товару.",
+
+		"clear.confirm":            "Ви впевнені, що хочете %s зі списку покупок?",
+		"clear.confirm.remove_all": "видалити всі товари",
+		"clear.yes":                "Так",
+		"clear.cancel":             "Скасувати",
+		"clear.done":               "Гаразд, я видалив усі товари з вашого списку покупок.\n\nТепер можна почати з чистого аркуша.",
+		"clear.canceled":           "Скасовую. Ваші товари залишилися в списку.",
+
+		"language.prompt": "Якою мовою мені з вами розмовляти?",
+		"language.done":   "Готово! Відтепер я розмовлятиму з вами українською.",
+
+		"settings.title":               "Налаштування цього чату. Натисніть кнопку, щоб змінити налаштування.",
+		"settings.language":            "Мова: %s",
+		"settings.language.users":      "Мова кожного користувача",
+		"settings.language.prompt":     "Якою мовою мені розмовляти в цьому чаті?",
+		"settings.sort_order":          "Сортування: %s",
+		"settings.sort_order.added":    "за датою додавання",
+		"settings.sort_order.name":     "за назвою",
+		"settings.sort_order.category": "за категорією",
+		"settings.sort_order.author":   "за тим, хто додав",
+		"settings.sort_order.manual":   "вручну",
+		"settings.confirm_clear":       "Підтверджувати /clear: %s",
+		"settings.admins_only":         "Лише адміни очищують список і змінюють налаштування: %s",
+		"settings.natural_language":    "Розуміти повідомлення без команд: %s",
+		"settings.on":                  "так",
+		"settings.off":                 "ні",
+		"settings.timezone":            "Часовий пояс: %s",
+		"settings.timezone.prompt":     "У якому часовому поясі цей чат?",
+		"settings.currency":            "Валюта: %s",
+		"settings.currency.prompt":     "Яку валюту ви використовуєте для цін?",
+		"settings.back":                "« Назад",
+		"settings.close":               "Готово",
+
+		"category.produce":   "Овочі",
+		"category.bakery":    "Випічка",
+		"category.meat":      "М'ясо",
+		"category.dairy":     "Молочне",
+		"category.frozen":    "Заморожене",
+		"category.pantry":    "Бакалія",
+		"category.drinks":    "Напої",
+		"category.household": "Побутове",
+		"category.other":     "Інше",
+
+		"permission.denied": "Вибачте, це можуть робити лише адміністратори цього чату.",
+
+		"inline.add.title":       "Додати «%s» до мого списку",
+		"inline.add.description": "Товар буде додано до вашого списку покупок",
+		"inline.add.message":     "Я додав «%s» до свого списку покупок 🛒",
+		"inline.share.title":     "Поділитися моїм списком",
+		"inline.share.empty":     "Мій список покупок порожній",
+	},
+
+	plurals: map[string]map[pluralForm]string{
+		"list.header": {
+			formOne:  "У вашому списку покупок %d товар:",
+			formFew:  "У вашому списку покупок %d товари:",
+			formMany: "У вашому списку покупок %d товарів:",
+		},
+		"inline.share.header": {
+			formOne:  "У моєму списку покупок %d товар:",
+			formFew:  "У моєму списку покупок %d товари:",
+			formMany: "У моєму списку покупок %d товарів:",
+		},
+		"dedupe.done": {
+			formOne:  "Готово! Я об'єднав %d дублікат з іншими товарами.",
+			formFew:  "Готово! Я об'єднав %d дублікати з іншими товарами.",
+			formMany: "Готово! Я об'єднав %d дублікатів з іншими товарами.",
+		},
+		"staples.schedule.days": {
+			formOne:  "кожен %d день",
+			formFew:  "кожні %d дні",
+			formMany: "кожні %d днів",
+		},
+		"staples.added": {
+			formOne:  "Час знову купувати постійні товари. Я додав %d товар до списку покупок: %s.",
+			formFew:  "Час знову купувати постійні товари. Я додав %d товари до списку покупок: %s.",
+			formMany: "Час знову купувати постійні товари. Я додав %d товарів до списку покупок: %s.",
+		},
+		"stats.title": {
+			formOne:  "Статистика вашого списку покупок за останній %d тиждень",
+			formFew:  "Статистика вашого списку покупок за останні %d тижні",
+			formMany: "Статистика вашого списку покупок за останні %d тижнів",
+		},
+		"stats.empty": {
+			formOne:  "За останній %d тиждень ніхто нічого не додавав і не купував.",
+			formFew:  "За останні %d тижні ніхто нічого не додавав і не купував.",
+			formMany: "За останні %d тижнів ніхто нічого не додавав і не купував.",
+		},
+		"stats.purchases": {
+			formOne:  "Куплено %d товар. У середньому товари залишаються в списку %s.",
+			formFew:  "Куплено %d товари. У середньому товари залишаються в списку %s.",
+			formMany: "Куплено %d товарів. У середньому товари залишаються в списку %s.",
+		},
+		"stats.duration.days": {
+			formOne:  "%d день",
+			formFew:  "%d дні",
+			formMany: "%d днів",
+		},
+		"stats.duration.hours": {
+			formOne:  "%d година",
+			formFew:  "%d години",
+			formMany: "%d годин",
+		},
+		"stats.duration.minutes": {
+			formOne:  "%d хвилина",
+			formFew:  "%d хвилини",
+			formMany: "%d хвилин",
+		},
+		"export.done": {
+			formOne:  "Ваш список покупок з %d товаром",
+			formFew:  "Ваш список покупок з %d товарами",
+			formMany: "Ваш список покупок з %d товарами",
+		},
+		"import.preview": {
+			formOne:  "Я додам %d товар до вашого списку покупок:",
+			formFew:  "Я додам %d товари до вашого списку покупок:",
+			formMany: "Я додам %d товарів до вашого списку покупок:",
+		},
+		"import.more": {
+			formOne:  "…і ще %d товар",
+			formFew:  "…і ще %d товари",
+			formMany: "…і ще %d товарів",
+		},
+		"import.too_many": {
+			formOne:  "Вибачте, я можу імпортувати не більше %d товару за раз.",
+			formFew:  "Вибачте, я можу імпортувати не більше %d товарів за раз.",
+			formMany: "Вибачте, я можу імпортувати не більше %d товарів за раз.",
+		},
+		"import.done": {
+			formOne:  "Готово! Я додав %d товар до вашого списку покупок.",
+			formFew:  "Готово! Я додав %d товари до вашого списку покупок.",
+			formMany: "Готово! Я додав %d товарів до вашого списку покупок.",
+		},
+		"import.skipped": {
+			formOne:  "%d товар уже був у списку.",
+			formFew:  "%d товари вже були в списку.",
+			formMany: "%d товарів уже було в списку.",
+		},
+		"cook.merged": {
+			formOne:  "%d з них уже був у списку, тому я збільшив його кількість.",
+			formFew:  "%d з них уже були в списку, тому я збільшив їхню кількість.",
+			formMany: "%d з них уже було в списку, тому я збільшив їхню кількість.",
+		},
+		"list.total.unpriced": {
+			formOne:  "(%d товар без ціни)",
+			formFew:  "(%d товари без ціни)",
+			formMany: "(%d товарів без ціни)",
+		},
+		"mine.header": {
+			formOne:  "Ви купуєте %d товар:",
+			formFew:  "Ви купуєте %d товари:",
+			formMany: "Ви купуєте %d товарів:",
+		},
+		"del.done.many": {
+			formOne:  "Я видалив %d товар зі списку покупок: %s.",
+			formFew:  "Я видалив %d товари зі списку покупок: %s.",
+			formMany: "Я видалив %d товарів зі списку покупок: %s.",
+		},
+	},
+}
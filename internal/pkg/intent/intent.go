@@ -0,0 +1,125 @@
+// Package intent recognises what users want from the bot
+// when they write plain messages instead of commands.
+//
+// For example, "add milk and eggs" means that an user wants
+// to add two items into their shopping list.
+package intent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Kind is a kind of an action an user wants to perform
+type Kind int
+
+// Supported kinds of intents
+const (
+	Unknown Kind = iota
+	Add
+	Remove
+	List
+	Clear
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Add:
+		return "add"
+	case Remove:
+		return "remove"
+	case List:
+		return "list"
+	case Clear:
+		return "clear"
+	}
+	return "unknown"
+}
+
+// Intent is an action which an user wants to perform
+type Intent struct {
+	Kind Kind
+	// Items are names of shopping items for the Add and Remove intents
+	Items []string
+}
+
+// rule recognises one kind of intents. Items are captured
+// by the "items" group of the pattern
+type rule struct {
+	kind    Kind
+	pattern *regexp.Regexp
+}
+
+// rules are checked in order, so more specific rules go first.
+// Patterns of all languages are checked together: people often write
+// in a language which differs from the language of their client
+var rules = []rule{
+	// English
+	{List, pattern(`^(what'?s|what is) (on|in) (my|the|our) (shopping )?list$`)},
+	{List, pattern(`^(show|display)( me)?( my| the| our)?( shopping)? list$`)},
+	{List, pattern(`^(my |the |our )?(shopping )?list$`)},
+	{Clear, pattern(`^(clear|empty|wipe)( (everything|all|(my|the|our)( shopping)? list))?$`)},
+	{Clear, pattern(`^(remove|delete) (everything|all|all items)$`)},
+	{Remove, pattern(`^(remove|delete) (?P<items>.+?)( from (my|the|our)( shopping)? list)?$`)},
+	{Add, pattern(`^(add|buy) (?P<items>.+?)( (to|into) (my|the|our)( shopping)? list)?$`)},
+
+	// Ukrainian
+	{List, pattern(`^що (є )?(в|у) (моєму |нашому )?списку$`)},
+	{List, pattern(`^(покажи( мені)?( мій| наш)? )?список( покупок)?$`)},
+	{Clear, pattern(`^очисти( (все|список))?$`)},
+	{Clear, pattern(`^видали (все|всі товари)$`)},
+	{Remove, pattern(`^(видали|прибери) (?P<items>.+?)( (зі|з) списку)?$`)},
+	{Add, pattern(`^(додай|купи) (?P<items>.+?)( (до списку|в список))?$`)},
+}
+
+// pattern compiles a case insensitive rule pattern
+func pattern(expr string) *regexp.Regexp {
+	return regexp.MustCompile("(?i)" + expr)
+}
+
+// itemsSeparator splits an enumeration of items: "milk, eggs and bread"
+var itemsSeparator = pattern(`\s*,\s*|\s+(and|&|і|й|та)\s+`)
+
+// Parse returns an intent of a message text
+func Parse(text string) Intent {
+	text = normalize(text)
+
+	for _, r := range rules {
+		match := r.pattern.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+
+		intent := Intent{Kind: r.kind}
+		for i, name := range r.pattern.SubexpNames() {
+			if name == "items" {
+				intent.Items = splitItems(match[i])
+			}
+		}
+
+		if (r.kind == Add || r.kind == Remove) && len(intent.Items) == 0 {
+			continue
+		}
+		return intent
+	}
+
+	return Intent{Kind: Unknown}
+}
+
+// normalize removes extra spaces and punctuation, which don't change
+// meaning of a message, and replaces typographic apostrophes
+func normalize(text string) string {
+	text = strings.Replace(text, "’", "'", -1)
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.TrimRight(text, "?!. ")
+}
+
+func splitItems(text string) []string {
+	var items []string
+	for _, item := range itemsSeparator.Split(text, -1) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
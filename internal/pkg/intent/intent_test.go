@@ -0,0 +1,68 @@
+package intent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected Intent
+	}{
+		// English
+		{"add milk", Intent{Kind: Add, Items: []string{"milk"}}},
+		{"Add milk and eggs", Intent{Kind: Add, Items: []string{"milk", "eggs"}}},
+		{"buy Milk, eggs & bread to my shopping list!", Intent{Kind: Add, Items: []string{"Milk", "eggs", "bread"}}},
+		{"add  salt and pepper  to the list", Intent{Kind: Add, Items: []string{"salt", "pepper"}}},
+		{"remove bread", Intent{Kind: Remove, Items: []string{"bread"}}},
+		{"Delete bread and milk from my list", Intent{Kind: Remove, Items: []string{"bread", "milk"}}},
+		{"what's on my list?", Intent{Kind: List}},
+		{"What’s on the shopping list", Intent{Kind: List}},
+		{"what is in our list", Intent{Kind: List}},
+		{"show me my list", Intent{Kind: List}},
+		{"list", Intent{Kind: List}},
+		{"clear everything", Intent{Kind: Clear}},
+		{"Clear", Intent{Kind: Clear}},
+		{"empty the shopping list", Intent{Kind: Clear}},
+		{"delete all", Intent{Kind: Clear}},
+
+		// Ukrainian
+		{"додай молоко і яйця", Intent{Kind: Add, Items: []string{"молоко", "яйця"}}},
+		{"Купи хліб до списку", Intent{Kind: Add, Items: []string{"хліб"}}},
+		{"видали хліб зі списку", Intent{Kind: Remove, Items: []string{"хліб"}}},
+		{"що в моєму списку?", Intent{Kind: List}},
+		{"Покажи список", Intent{Kind: List}},
+		{"очисти все", Intent{Kind: Clear}},
+		{"видали все", Intent{Kind: Clear}},
+
+		// Unknown
+		{"", Intent{Kind: Unknown}},
+		{"hello", Intent{Kind: Unknown}},
+		{"add", Intent{Kind: Unknown}},
+		{"milk", Intent{Kind: Unknown}},
+		{"add ,", Intent{Kind: Unknown}},
+	}
+
+	for _, testCase := range testCases {
+		actual := Parse(testCase.text)
+		if !reflect.DeepEqual(actual, testCase.expected) {
+			t.Errorf("%#v: expected %#v, got %#v",
+				testCase.text, testCase.expected, actual)
+		}
+	}
+}
+
+func TestKindString(t *testing.T) {
+	for kind, expected := range map[Kind]string{
+		Unknown: "unknown",
+		Add:     "add",
+		Remove:  "remove",
+		List:    "list",
+		Clear:   "clear",
+	} {
+		if kind.String() != expected {
+			t.Errorf("Expected %#v, got %#v", expected, kind.String())
+		}
+	}
+}
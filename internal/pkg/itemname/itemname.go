@@ -0,0 +1,144 @@
+// Package itemname compares names of shopping items the way people do:
+// "Milk", " milk " and "milks" are the same item.
+package itemname
+
+import "strings"
+
+// Normalize returns a name which is equal for names of the same item.
+// It ignores case, extra whitespace and simple English plural forms
+func Normalize(name string) string {
+	words := strings.Fields(strings.ToLower(name))
+	if len(words) == 0 {
+		return ""
+	}
+
+	// Only the last word changes in plural: "green apples"
+	last := len(words) - 1
+	words[last] = singular(words[last])
+	return strings.Join(words, " ")
+}
+
+// Equal reports whether two names are names of the same item
+func Equal(a, b string) bool {
+	return Normalize(a) == Normalize(b)
+}
+
+// singular returns a singular form of a word in a simple plural form.
+// Irregular forms ("mice") are not supported: they are rare in shopping lists
+func singular(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		// berries
+		return strings.TrimSuffix(word, "ies") + "y"
+	case len(word) > 4 && hasAnySuffix(word, "sses", "xes", "zes", "ches", "shes"),
+		len(word) > 5 && strings.HasSuffix(word, "oes"):
+		// glasses, boxes, peaches, tomatoes, but not shoes
+		return strings.TrimSuffix(word, "es")
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !hasAnySuffix(word, "ss", "us", "is"):
+		// eggs, but not glass, hummus or jus
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match reports whether a name looks like a query: the query can be
+// words or beginnings of words of the name ("bread" matches "white bread",
+// "choc" matches "dark chocolate", but "ham" doesn't match "shampoo")
+// and it can have a few typos ("bred" matches "bread")
+func Match(name, query string) bool {
+	name, query = Normalize(name), Normalize(query)
+	if name == "" || query == "" {
+		return false
+	}
+
+	if hasWordPrefixes(strings.Fields(name), strings.Fields(query)) {
+		return true
+	}
+
+	typos := maxTypos(query)
+	if distance(name, query) <= typos {
+		return true
+	}
+
+	// Users often type one word of a long name
+	for _, word := range strings.Fields(name) {
+		if distance(word, query) <= typos {
+			return true
+		}
+	}
+	return false
+}
+
+// hasWordPrefixes reports whether query words are beginnings
+// of consecutive words of a name
+func hasWordPrefixes(nameWords, queryWords []string) bool {
+	for i := 0; i+len(queryWords) <= len(nameWords); i++ {
+		matched := true
+		for j, queryWord := range queryWords {
+			if !strings.HasPrefix(nameWords[i+j], queryWord) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// maxTypos returns how many typos we tolerate in a query.
+// Short words differ by a letter too often: "tea" and "pea"
+func maxTypos(query string) int {
+	switch n := len([]rune(query)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	}
+	return 2
+}
+
+// distance returns the Levenshtein distance between two strings
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// We only need the previous row of the matrix
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,99 @@
+package itemname
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		{"Milk", "milk"},
+		{"  milk  ", "milk"},
+		{"Green   Apples", "green apple"},
+		{"eggs", "egg"},
+		{"berries", "berry"},
+		{"tomatoes", "tomato"},
+		{"shoes", "shoe"},
+		{"boxes", "box"},
+		{"peaches", "peach"},
+		{"glasses", "glass"},
+		{"glass", "glass"},
+		{"hummus", "hummus"},
+		{"cheeses", "cheese"},
+		{"gas", "gas"},
+		{"Молоко", "молоко"},
+		{"", ""},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			normalized := Normalize(testCase.name)
+			if normalized != testCase.expected {
+				t.Errorf("Expected %#v, got %#v", testCase.expected, normalized)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	if !Equal("Milk", " milks") {
+		t.Error("Expected names to be equal")
+	}
+
+	if Equal("milk", "oat milk") {
+		t.Error("Expected names to be different")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		expected bool
+	}{
+		{"Milk", "milk", true},
+		{"Oat milk", "milk", true},
+		{"White bread", "bred", true},
+		{"Bread", "braed", false},
+		{"Tomatoes", "tomatos", true},
+		{"Strawberries", "strawbery", true},
+		{"Tea", "pea", false},
+		{"Dark chocolate", "choc", true},
+		{"Green apples", "green app", true},
+		{"Shampoo", "ham", false},
+		{"Steak", "tea", false},
+		{"Green tea", "tea green", false},
+		{"Milk", "bread", false},
+		{"Молоко", "малоко", true},
+		{"Milk", "", false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name+"/"+testCase.query, func(t *testing.T) {
+			matched := Match(testCase.name, testCase.query)
+			if matched != testCase.expected {
+				t.Errorf("Expected %v, got %v", testCase.expected, matched)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"milk", "", 4},
+		{"bread", "bred", 1},
+		{"kitten", "sitting", 3},
+		{"молоко", "малоко", 1},
+	}
+
+	for _, testCase := range testCases {
+		if d := distance(testCase.a, testCase.b); d != testCase.expected {
+			t.Errorf("Expected distance between %#v and %#v to be %d, got %d",
+				testCase.a, testCase.b, testCase.expected, d)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package logging provides a small levelled logger
+// that produces structured records in the logfmt format:
+//
+//	time=2018-10-01T12:00:00Z level=info msg="Message received" update_id=1
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level represents severity of a log record
+type Level int
+
+// All supported log levels
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+// timeNow allows us to have stable timestamps in tests
+var timeNow = time.Now
+
+// Logger writes structured log records into an io.Writer.
+//
+// Loggers derived using the With method share the output and the lock
+// with their parent, so it's safe to use them from different goroutines.
+type Logger struct {
+	mu      *sync.Mutex
+	out     io.Writer
+	level   Level
+	keyvals []interface{}
+}
+
+// New creates a logger which writes records with
+// the `level` severity or higher into `out`
+func New(out io.Writer, level Level) *Logger {
+	return &Logger{
+		mu:    &sync.Mutex{},
+		out:   out,
+		level: level,
+	}
+}
+
+// With returns a new logger that adds `keyvals` to every record.
+// `keyvals` is a list of alternating keys and values.
+func (l *Logger) With(keyvals ...interface{}) *Logger {
+	newKeyvals := make([]interface{}, 0, len(l.keyvals)+len(keyvals))
+	newKeyvals = append(newKeyvals, l.keyvals...)
+	newKeyvals = append(newKeyvals, keyvals...)
+
+	return &Logger{
+		mu:      l.mu,
+		out:     l.out,
+		level:   l.level,
+		keyvals: newKeyvals,
+	}
+}
+
+// Enabled reports whether the logger writes records of the given level
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.level
+}
+
+// Debug writes a record with the debug level
+func (l *Logger) Debug(msg string, keyvals ...interface{}) {
+	l.log(LevelDebug, msg, keyvals)
+}
+
+// Info writes a record with the info level
+func (l *Logger) Info(msg string, keyvals ...interface{}) {
+	l.log(LevelInfo, msg, keyvals)
+}
+
+// Warn writes a record with the warn level
+func (l *Logger) Warn(msg string, keyvals ...interface{}) {
+	l.log(LevelWarn, msg, keyvals)
+}
+
+// Error writes a record with the error level
+func (l *Logger) Error(msg string, keyvals ...interface{}) {
+	l.log(LevelError, msg, keyvals)
+}
+
+func (l *Logger) log(level Level, msg string, keyvals []interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	var buf bytes.Buffer
+	writeKeyval(&buf, "time", timeNow().UTC().Format(time.RFC3339))
+	writeKeyval(&buf, "level", level)
+	writeKeyval(&buf, "msg", msg)
+	writeKeyvals(&buf, l.keyvals)
+	writeKeyvals(&buf, keyvals)
+	buf.WriteByte('\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out.Write(buf.Bytes())
+}
+
+func writeKeyvals(buf *bytes.Buffer, keyvals []interface{}) {
+	for i := 0; i < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+
+		var value interface{} = "(MISSING)"
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+
+		writeKeyval(buf, key, value)
+	}
+}
+
+func writeKeyval(buf *bytes.Buffer, key string, value interface{}) {
+	if buf.Len() > 0 {
+		buf.WriteByte(' ')
+	}
+
+	buf.WriteString(key)
+	buf.WriteByte('=')
+	buf.WriteString(formatValue(value))
+}
+
+// formatValue converts a value into a string
+// and quotes it, if it's required by logfmt
+func formatValue(value interface{}) string {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case error:
+		s = v.Error()
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	if s == "" || strings.ContainsAny(s, " =\"\\") || !strconv.CanBackquote(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger(t *testing.T) {
+	// Mock: timeNow
+	timeNowOld := timeNow
+	defer func() { timeNow = timeNowOld }()
+	timeNow = func() time.Time {
+		return time.Date(2018, 10, 1, 12, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("Record format", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := New(&buf, LevelDebug)
+
+		logger.Info("Message received", "chat_id", 123, "text", "some text")
+
+		expected := `time=2018-10-01T12:00:00Z level=info msg="Message received" chat_id=123 text="some text"` + "\n"
+		if buf.String() != expected {
+			t.Errorf("Expected %#v, got %#v", expected, buf.String())
+		}
+	})
+
+	t.Run("Levels", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := New(&buf, LevelInfo)
+
+		logger.Debug("debug record")
+		logger.Info("info record")
+		logger.Warn("warn record")
+		logger.Error("error record")
+
+		if strings.Contains(buf.String(), "debug record") {
+			t.Error("Expected debug records to be skipped")
+		}
+
+		for _, expected := range []string{
+			"level=info", "level=warn", "level=error",
+		} {
+			if !strings.Contains(buf.String(), expected) {
+				t.Errorf("Expected output to contain %#v, got %#v",
+					expected, buf.String())
+			}
+		}
+
+		if logger.Enabled(LevelDebug) {
+			t.Error("Expected debug level to be disabled")
+		}
+	})
+
+	t.Run("With", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := New(&buf, LevelDebug)
+		childLogger := logger.With("update_id", 1)
+
+		childLogger.Info("child record", "command", "add")
+		logger.Info("parent record")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Expected 2 records, got %d", len(lines))
+		}
+
+		if !strings.HasSuffix(lines[0], `msg="child record" update_id=1 command=add`) {
+			t.Errorf("Unexpected child record: %#v", lines[0])
+		}
+
+		if strings.Contains(lines[1], "update_id") {
+			t.Errorf("Parent record must not contain child fields: %#v", lines[1])
+		}
+	})
+
+	t.Run("Values", func(t *testing.T) {
+		testCases := []struct {
+			value    interface{}
+			expected string
+		}{
+			{value: "simple", expected: "simple"},
+			{value: "", expected: `""`},
+			{value: "with space", expected: `"with space"`},
+			{value: "a=b", expected: `"a=b"`},
+			{value: "multi\nline", expected: `"multi\nline"`},
+			{value: errors.New("fake error"), expected: `"fake error"`},
+			{value: nil, expected: "nil"},
+			{value: LevelWarn, expected: "warn"},
+			{value: int64(-100), expected: "-100"},
+		}
+
+		for _, testCase := range testCases {
+			actual := formatValue(testCase.value)
+			if actual != testCase.expected {
+				t.Errorf("Expected %#v, got %#v", testCase.expected, actual)
+			}
+		}
+	})
+
+	t.Run("Odd number of keyvals", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := New(&buf, LevelDebug)
+
+		logger.Info("record", "key")
+
+		expected := "key=(MISSING)"
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected output to contain %#v, got %#v",
+				expected, buf.String())
+		}
+	})
+}
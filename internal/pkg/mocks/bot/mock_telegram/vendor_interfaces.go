@@ -7,6 +7,7 @@ package mock_telegram
 import (
 	telegram_bot_api_v4 "github.com/go-telegram-bot-api/telegram-bot-api"
 	gomock "github.com/golang/mock/gomock"
+	url "net/url"
 	reflect "reflect"
 )
 
@@ -199,6 +200,150 @@ func (mr *MockcallbackQueryAnswererMockRecorder) AnswerCallbackQuery(config inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnswerCallbackQuery", reflect.TypeOf((*MockcallbackQueryAnswerer)(nil).AnswerCallbackQuery), config)
 }
 
+// MockinlineQueryAnswerer is a mock of inlineQueryAnswerer interface
+type MockinlineQueryAnswerer struct {
+	ctrl     *gomock.Controller
+	recorder *MockinlineQueryAnswererMockRecorder
+}
+
+// MockinlineQueryAnswererMockRecorder is the mock recorder for MockinlineQueryAnswerer
+type MockinlineQueryAnswererMockRecorder struct {
+	mock *MockinlineQueryAnswerer
+}
+
+// NewMockinlineQueryAnswerer creates a new mock instance
+func NewMockinlineQueryAnswerer(ctrl *gomock.Controller) *MockinlineQueryAnswerer {
+	mock := &MockinlineQueryAnswerer{ctrl: ctrl}
+	mock.recorder = &MockinlineQueryAnswererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockinlineQueryAnswerer) EXPECT() *MockinlineQueryAnswererMockRecorder {
+	return m.recorder
+}
+
+// AnswerInlineQuery mocks base method
+func (m *MockinlineQueryAnswerer) AnswerInlineQuery(config telegram_bot_api_v4.InlineConfig) (telegram_bot_api_v4.APIResponse, error) {
+	ret := m.ctrl.Call(m, "AnswerInlineQuery", config)
+	ret0, _ := ret[0].(telegram_bot_api_v4.APIResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnswerInlineQuery indicates an expected call of AnswerInlineQuery
+func (mr *MockinlineQueryAnswererMockRecorder) AnswerInlineQuery(config interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnswerInlineQuery", reflect.TypeOf((*MockinlineQueryAnswerer)(nil).AnswerInlineQuery), config)
+}
+
+// MockchatMemberGetter is a mock of chatMemberGetter interface
+type MockchatMemberGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockchatMemberGetterMockRecorder
+}
+
+// MockchatMemberGetterMockRecorder is the mock recorder for MockchatMemberGetter
+type MockchatMemberGetterMockRecorder struct {
+	mock *MockchatMemberGetter
+}
+
+// NewMockchatMemberGetter creates a new mock instance
+func NewMockchatMemberGetter(ctrl *gomock.Controller) *MockchatMemberGetter {
+	mock := &MockchatMemberGetter{ctrl: ctrl}
+	mock.recorder = &MockchatMemberGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockchatMemberGetter) EXPECT() *MockchatMemberGetterMockRecorder {
+	return m.recorder
+}
+
+// GetChatMember mocks base method
+func (m *MockchatMemberGetter) GetChatMember(config telegram_bot_api_v4.ChatConfigWithUser) (telegram_bot_api_v4.ChatMember, error) {
+	ret := m.ctrl.Call(m, "GetChatMember", config)
+	ret0, _ := ret[0].(telegram_bot_api_v4.ChatMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatMember indicates an expected call of GetChatMember
+func (mr *MockchatMemberGetterMockRecorder) GetChatMember(config interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatMember", reflect.TypeOf((*MockchatMemberGetter)(nil).GetChatMember), config)
+}
+
+// MockfileURLGetter is a mock of fileURLGetter interface
+type MockfileURLGetter struct {
+	ctrl     *gomock.Controller
+	recorder *MockfileURLGetterMockRecorder
+}
+
+// MockfileURLGetterMockRecorder is the mock recorder for MockfileURLGetter
+type MockfileURLGetterMockRecorder struct {
+	mock *MockfileURLGetter
+}
+
+// NewMockfileURLGetter creates a new mock instance
+func NewMockfileURLGetter(ctrl *gomock.Controller) *MockfileURLGetter {
+	mock := &MockfileURLGetter{ctrl: ctrl}
+	mock.recorder = &MockfileURLGetterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockfileURLGetter) EXPECT() *MockfileURLGetterMockRecorder {
+	return m.recorder
+}
+
+// GetFileDirectURL mocks base method
+func (m *MockfileURLGetter) GetFileDirectURL(fileID string) (string, error) {
+	ret := m.ctrl.Call(m, "GetFileDirectURL", fileID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileDirectURL indicates an expected call of GetFileDirectURL
+func (mr *MockfileURLGetterMockRecorder) GetFileDirectURL(fileID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileDirectURL", reflect.TypeOf((*MockfileURLGetter)(nil).GetFileDirectURL), fileID)
+}
+
+// MockrequestMaker is a mock of requestMaker interface
+type MockrequestMaker struct {
+	ctrl     *gomock.Controller
+	recorder *MockrequestMakerMockRecorder
+}
+
+// MockrequestMakerMockRecorder is the mock recorder for MockrequestMaker
+type MockrequestMakerMockRecorder struct {
+	mock *MockrequestMaker
+}
+
+// NewMockrequestMaker creates a new mock instance
+func NewMockrequestMaker(ctrl *gomock.Controller) *MockrequestMaker {
+	mock := &MockrequestMaker{ctrl: ctrl}
+	mock.recorder = &MockrequestMakerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockrequestMaker) EXPECT() *MockrequestMakerMockRecorder {
+	return m.recorder
+}
+
+// MakeRequest mocks base method
+func (m *MockrequestMaker) MakeRequest(endpoint string, params url.Values) (telegram_bot_api_v4.APIResponse, error) {
+	ret := m.ctrl.Call(m, "MakeRequest", endpoint, params)
+	ret0, _ := ret[0].(telegram_bot_api_v4.APIResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MakeRequest indicates an expected call of MakeRequest
+func (mr *MockrequestMakerMockRecorder) MakeRequest(endpoint, params interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeRequest", reflect.TypeOf((*MockrequestMaker)(nil).MakeRequest), endpoint, params)
+}
+
 // MocktokenListenForWebhook is a mock of tokenListenForWebhook interface
 type MocktokenListenForWebhook struct {
 	ctrl     *gomock.Controller
@@ -318,3 +463,55 @@ func (m *MockbotClientInterface) AnswerCallbackQuery(config telegram_bot_api_v4.
 func (mr *MockbotClientInterfaceMockRecorder) AnswerCallbackQuery(config interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnswerCallbackQuery", reflect.TypeOf((*MockbotClientInterface)(nil).AnswerCallbackQuery), config)
 }
+
+// GetChatMember mocks base method
+func (m *MockbotClientInterface) GetChatMember(config telegram_bot_api_v4.ChatConfigWithUser) (telegram_bot_api_v4.ChatMember, error) {
+	ret := m.ctrl.Call(m, "GetChatMember", config)
+	ret0, _ := ret[0].(telegram_bot_api_v4.ChatMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatMember indicates an expected call of GetChatMember
+func (mr *MockbotClientInterfaceMockRecorder) GetChatMember(config interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatMember", reflect.TypeOf((*MockbotClientInterface)(nil).GetChatMember), config)
+}
+
+// MakeRequest mocks base method
+func (m *MockbotClientInterface) MakeRequest(endpoint string, params url.Values) (telegram_bot_api_v4.APIResponse, error) {
+	ret := m.ctrl.Call(m, "MakeRequest", endpoint, params)
+	ret0, _ := ret[0].(telegram_bot_api_v4.APIResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MakeRequest indicates an expected call of MakeRequest
+func (mr *MockbotClientInterfaceMockRecorder) MakeRequest(endpoint, params interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeRequest", reflect.TypeOf((*MockbotClientInterface)(nil).MakeRequest), endpoint, params)
+}
+
+// AnswerInlineQuery mocks base method
+func (m *MockbotClientInterface) AnswerInlineQuery(config telegram_bot_api_v4.InlineConfig) (telegram_bot_api_v4.APIResponse, error) {
+	ret := m.ctrl.Call(m, "AnswerInlineQuery", config)
+	ret0, _ := ret[0].(telegram_bot_api_v4.APIResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnswerInlineQuery indicates an expected call of AnswerInlineQuery
+func (mr *MockbotClientInterfaceMockRecorder) AnswerInlineQuery(config interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnswerInlineQuery", reflect.TypeOf((*MockbotClientInterface)(nil).AnswerInlineQuery), config)
+}
+
+// GetFileDirectURL mocks base method
+func (m *MockbotClientInterface) GetFileDirectURL(fileID string) (string, error) {
+	ret := m.ctrl.Call(m, "GetFileDirectURL", fileID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFileDirectURL indicates an expected call of GetFileDirectURL
+func (mr *MockbotClientInterfaceMockRecorder) GetFileDirectURL(fileID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFileDirectURL", reflect.TypeOf((*MockbotClientInterface)(nil).GetFileDirectURL), fileID)
+}
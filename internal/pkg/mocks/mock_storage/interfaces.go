@@ -7,7 +7,9 @@ package mock_storage
 import (
 	gomock "github.com/golang/mock/gomock"
 	models "github.com/m1kola/shipsterbot/internal/pkg/models"
+	money "github.com/m1kola/shipsterbot/internal/pkg/money"
 	reflect "reflect"
+	time "time"
 )
 
 // MockDataStorageInterface is a mock of DataStorageInterface interface
@@ -131,3 +133,501 @@ func (m *MockDataStorageInterface) DeleteAllShoppingItems(chatID int64) error {
 func (mr *MockDataStorageInterfaceMockRecorder) DeleteAllShoppingItems(chatID interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllShoppingItems", reflect.TypeOf((*MockDataStorageInterface)(nil).DeleteAllShoppingItems), chatID)
 }
+
+// MarkChatActive mocks base method
+func (m *MockDataStorageInterface) MarkChatActive(chatID int64) error {
+	ret := m.ctrl.Call(m, "MarkChatActive", chatID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkChatActive indicates an expected call of MarkChatActive
+func (mr *MockDataStorageInterfaceMockRecorder) MarkChatActive(chatID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkChatActive", reflect.TypeOf((*MockDataStorageInterface)(nil).MarkChatActive), chatID)
+}
+
+// MarkChatInactive mocks base method
+func (m *MockDataStorageInterface) MarkChatInactive(chatID int64) error {
+	ret := m.ctrl.Call(m, "MarkChatInactive", chatID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkChatInactive indicates an expected call of MarkChatInactive
+func (mr *MockDataStorageInterfaceMockRecorder) MarkChatInactive(chatID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkChatInactive", reflect.TypeOf((*MockDataStorageInterface)(nil).MarkChatInactive), chatID)
+}
+
+// GetUserLocale mocks base method
+func (m *MockDataStorageInterface) GetUserLocale(userID int) (string, error) {
+	ret := m.ctrl.Call(m, "GetUserLocale", userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLocale indicates an expected call of GetUserLocale
+func (mr *MockDataStorageInterfaceMockRecorder) GetUserLocale(userID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLocale", reflect.TypeOf((*MockDataStorageInterface)(nil).GetUserLocale), userID)
+}
+
+// SetUserLocale mocks base method
+func (m *MockDataStorageInterface) SetUserLocale(userID int, locale string) error {
+	ret := m.ctrl.Call(m, "SetUserLocale", userID, locale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserLocale indicates an expected call of SetUserLocale
+func (mr *MockDataStorageInterfaceMockRecorder) SetUserLocale(userID, locale interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserLocale", reflect.TypeOf((*MockDataStorageInterface)(nil).SetUserLocale), userID, locale)
+}
+
+// GetChatSettings mocks base method
+func (m *MockDataStorageInterface) GetChatSettings(chatID int64) (*models.ChatSettings, error) {
+	ret := m.ctrl.Call(m, "GetChatSettings", chatID)
+	ret0, _ := ret[0].(*models.ChatSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatSettings indicates an expected call of GetChatSettings
+func (mr *MockDataStorageInterfaceMockRecorder) GetChatSettings(chatID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatSettings", reflect.TypeOf((*MockDataStorageInterface)(nil).GetChatSettings), chatID)
+}
+
+// UpdateChatSettings mocks base method
+func (m *MockDataStorageInterface) UpdateChatSettings(settings models.ChatSettings) error {
+	ret := m.ctrl.Call(m, "UpdateChatSettings", settings)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateChatSettings indicates an expected call of UpdateChatSettings
+func (mr *MockDataStorageInterfaceMockRecorder) UpdateChatSettings(settings interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChatSettings", reflect.TypeOf((*MockDataStorageInterface)(nil).UpdateChatSettings), settings)
+}
+
+// IncreaseShoppingItemQuantity mocks base method
+func (m *MockDataStorageInterface) IncreaseShoppingItemQuantity(itemID int64, by int) error {
+	ret := m.ctrl.Call(m, "IncreaseShoppingItemQuantity", itemID, by)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncreaseShoppingItemQuantity indicates an expected call of IncreaseShoppingItemQuantity
+func (mr *MockDataStorageInterfaceMockRecorder) IncreaseShoppingItemQuantity(itemID, by interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseShoppingItemQuantity", reflect.TypeOf((*MockDataStorageInterface)(nil).IncreaseShoppingItemQuantity), itemID, by)
+}
+
+// MergeShoppingItems mocks base method
+func (m *MockDataStorageInterface) MergeShoppingItems(itemID int64, duplicateIDs []int64) error {
+	ret := m.ctrl.Call(m, "MergeShoppingItems", itemID, duplicateIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeShoppingItems indicates an expected call of MergeShoppingItems
+func (mr *MockDataStorageInterfaceMockRecorder) MergeShoppingItems(itemID, duplicateIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeShoppingItems", reflect.TypeOf((*MockDataStorageInterface)(nil).MergeShoppingItems), itemID, duplicateIDs)
+}
+
+// GetCategoryKeywords mocks base method
+func (m *MockDataStorageInterface) GetCategoryKeywords(chatID int64) (map[string]string, error) {
+	ret := m.ctrl.Call(m, "GetCategoryKeywords", chatID)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryKeywords indicates an expected call of GetCategoryKeywords
+func (mr *MockDataStorageInterfaceMockRecorder) GetCategoryKeywords(chatID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryKeywords", reflect.TypeOf((*MockDataStorageInterface)(nil).GetCategoryKeywords), chatID)
+}
+
+// SetCategoryKeyword mocks base method
+func (m *MockDataStorageInterface) SetCategoryKeyword(chatID int64, keyword, category string) error {
+	ret := m.ctrl.Call(m, "SetCategoryKeyword", chatID, keyword, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCategoryKeyword indicates an expected call of SetCategoryKeyword
+func (mr *MockDataStorageInterfaceMockRecorder) SetCategoryKeyword(chatID, keyword, category interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCategoryKeyword", reflect.TypeOf((*MockDataStorageInterface)(nil).SetCategoryKeyword), chatID, keyword, category)
+}
+
+// UpdateShoppingItemCategory mocks base method
+func (m *MockDataStorageInterface) UpdateShoppingItemCategory(itemID int64, category string) error {
+	ret := m.ctrl.Call(m, "UpdateShoppingItemCategory", itemID, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateShoppingItemCategory indicates an expected call of UpdateShoppingItemCategory
+func (mr *MockDataStorageInterfaceMockRecorder) UpdateShoppingItemCategory(itemID, category interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShoppingItemCategory", reflect.TypeOf((*MockDataStorageInterface)(nil).UpdateShoppingItemCategory), itemID, category)
+}
+
+// ReorderShoppingItems mocks base method
+func (m *MockDataStorageInterface) ReorderShoppingItems(chatID int64, itemIDs []int64) error {
+	ret := m.ctrl.Call(m, "ReorderShoppingItems", chatID, itemIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderShoppingItems indicates an expected call of ReorderShoppingItems
+func (mr *MockDataStorageInterfaceMockRecorder) ReorderShoppingItems(chatID, itemIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderShoppingItems", reflect.TypeOf((*MockDataStorageInterface)(nil).ReorderShoppingItems), chatID, itemIDs)
+}
+
+// AddStaple mocks base method
+func (m *MockDataStorageInterface) AddStaple(staple models.Staple) error {
+	ret := m.ctrl.Call(m, "AddStaple", staple)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddStaple indicates an expected call of AddStaple
+func (mr *MockDataStorageInterfaceMockRecorder) AddStaple(staple interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddStaple", reflect.TypeOf((*MockDataStorageInterface)(nil).AddStaple), staple)
+}
+
+// DeleteStaple mocks base method
+func (m *MockDataStorageInterface) DeleteStaple(stapleID int64) error {
+	ret := m.ctrl.Call(m, "DeleteStaple", stapleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStaple indicates an expected call of DeleteStaple
+func (mr *MockDataStorageInterfaceMockRecorder) DeleteStaple(stapleID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaple", reflect.TypeOf((*MockDataStorageInterface)(nil).DeleteStaple), stapleID)
+}
+
+// GetDueStaples mocks base method
+func (m *MockDataStorageInterface) GetDueStaples(now time.Time) ([]*models.Staple, error) {
+	ret := m.ctrl.Call(m, "GetDueStaples", now)
+	ret0, _ := ret[0].([]*models.Staple)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueStaples indicates an expected call of GetDueStaples
+func (mr *MockDataStorageInterfaceMockRecorder) GetDueStaples(now interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueStaples", reflect.TypeOf((*MockDataStorageInterface)(nil).GetDueStaples), now)
+}
+
+// GetStaple mocks base method
+func (m *MockDataStorageInterface) GetStaple(stapleID int64) (*models.Staple, error) {
+	ret := m.ctrl.Call(m, "GetStaple", stapleID)
+	ret0, _ := ret[0].(*models.Staple)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStaple indicates an expected call of GetStaple
+func (mr *MockDataStorageInterfaceMockRecorder) GetStaple(stapleID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStaple", reflect.TypeOf((*MockDataStorageInterface)(nil).GetStaple), stapleID)
+}
+
+// GetStaples mocks base method
+func (m *MockDataStorageInterface) GetStaples(chatID int64) ([]*models.Staple, error) {
+	ret := m.ctrl.Call(m, "GetStaples", chatID)
+	ret0, _ := ret[0].([]*models.Staple)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStaples indicates an expected call of GetStaples
+func (mr *MockDataStorageInterfaceMockRecorder) GetStaples(chatID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStaples", reflect.TypeOf((*MockDataStorageInterface)(nil).GetStaples), chatID)
+}
+
+// UpdateStapleNextAt mocks base method
+func (m *MockDataStorageInterface) UpdateStapleNextAt(stapleID int64, nextAt time.Time) error {
+	ret := m.ctrl.Call(m, "UpdateStapleNextAt", stapleID, nextAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStapleNextAt indicates an expected call of UpdateStapleNextAt
+func (mr *MockDataStorageInterfaceMockRecorder) UpdateStapleNextAt(stapleID, nextAt interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStapleNextAt", reflect.TypeOf((*MockDataStorageInterface)(nil).UpdateStapleNextAt), stapleID, nextAt)
+}
+
+// AddReminder mocks base method
+func (m *MockDataStorageInterface) AddReminder(reminder models.Reminder) error {
+	ret := m.ctrl.Call(m, "AddReminder", reminder)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReminder indicates an expected call of AddReminder
+func (mr *MockDataStorageInterfaceMockRecorder) AddReminder(reminder interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReminder", reflect.TypeOf((*MockDataStorageInterface)(nil).AddReminder), reminder)
+}
+
+// DeleteReminder mocks base method
+func (m *MockDataStorageInterface) DeleteReminder(reminderID int64) error {
+	ret := m.ctrl.Call(m, "DeleteReminder", reminderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReminder indicates an expected call of DeleteReminder
+func (mr *MockDataStorageInterfaceMockRecorder) DeleteReminder(reminderID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReminder", reflect.TypeOf((*MockDataStorageInterface)(nil).DeleteReminder), reminderID)
+}
+
+// GetDueReminders mocks base method
+func (m *MockDataStorageInterface) GetDueReminders(now time.Time) ([]*models.Reminder, error) {
+	ret := m.ctrl.Call(m, "GetDueReminders", now)
+	ret0, _ := ret[0].([]*models.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueReminders indicates an expected call of GetDueReminders
+func (mr *MockDataStorageInterfaceMockRecorder) GetDueReminders(now interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueReminders", reflect.TypeOf((*MockDataStorageInterface)(nil).GetDueReminders), now)
+}
+
+// GetReminder mocks base method
+func (m *MockDataStorageInterface) GetReminder(reminderID int64) (*models.Reminder, error) {
+	ret := m.ctrl.Call(m, "GetReminder", reminderID)
+	ret0, _ := ret[0].(*models.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReminder indicates an expected call of GetReminder
+func (mr *MockDataStorageInterfaceMockRecorder) GetReminder(reminderID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReminder", reflect.TypeOf((*MockDataStorageInterface)(nil).GetReminder), reminderID)
+}
+
+// GetReminders mocks base method
+func (m *MockDataStorageInterface) GetReminders(chatID int64) ([]*models.Reminder, error) {
+	ret := m.ctrl.Call(m, "GetReminders", chatID)
+	ret0, _ := ret[0].([]*models.Reminder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReminders indicates an expected call of GetReminders
+func (mr *MockDataStorageInterfaceMockRecorder) GetReminders(chatID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReminders", reflect.TypeOf((*MockDataStorageInterface)(nil).GetReminders), chatID)
+}
+
+// UpdateReminderNextAt mocks base method
+func (m *MockDataStorageInterface) UpdateReminderNextAt(reminderID int64, nextAt time.Time) error {
+	ret := m.ctrl.Call(m, "UpdateReminderNextAt", reminderID, nextAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReminderNextAt indicates an expected call of UpdateReminderNextAt
+func (mr *MockDataStorageInterfaceMockRecorder) UpdateReminderNextAt(reminderID, nextAt interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReminderNextAt", reflect.TypeOf((*MockDataStorageInterface)(nil).UpdateReminderNextAt), reminderID, nextAt)
+}
+
+// GetFrequentItems mocks base method
+func (m *MockDataStorageInterface) GetFrequentItems(chatID int64, limit int) ([]*models.ItemHistoryEntry, error) {
+	ret := m.ctrl.Call(m, "GetFrequentItems", chatID, limit)
+	ret0, _ := ret[0].([]*models.ItemHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFrequentItems indicates an expected call of GetFrequentItems
+func (mr *MockDataStorageInterfaceMockRecorder) GetFrequentItems(chatID, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFrequentItems", reflect.TypeOf((*MockDataStorageInterface)(nil).GetFrequentItems), chatID, limit)
+}
+
+// GetItemHistoryEntry mocks base method
+func (m *MockDataStorageInterface) GetItemHistoryEntry(entryID int64) (*models.ItemHistoryEntry, error) {
+	ret := m.ctrl.Call(m, "GetItemHistoryEntry", entryID)
+	ret0, _ := ret[0].(*models.ItemHistoryEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemHistoryEntry indicates an expected call of GetItemHistoryEntry
+func (mr *MockDataStorageInterfaceMockRecorder) GetItemHistoryEntry(entryID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemHistoryEntry", reflect.TypeOf((*MockDataStorageInterface)(nil).GetItemHistoryEntry), entryID)
+}
+
+// AddAuditLogEntry mocks base method
+func (m *MockDataStorageInterface) AddAuditLogEntry(entry models.AuditLogEntry) error {
+	ret := m.ctrl.Call(m, "AddAuditLogEntry", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAuditLogEntry indicates an expected call of AddAuditLogEntry
+func (mr *MockDataStorageInterfaceMockRecorder) AddAuditLogEntry(entry interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAuditLogEntry", reflect.TypeOf((*MockDataStorageInterface)(nil).AddAuditLogEntry), entry)
+}
+
+// DeleteAuditLogEntriesBefore mocks base method
+func (m *MockDataStorageInterface) DeleteAuditLogEntriesBefore(t time.Time) (int64, error) {
+	ret := m.ctrl.Call(m, "DeleteAuditLogEntriesBefore", t)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAuditLogEntriesBefore indicates an expected call of DeleteAuditLogEntriesBefore
+func (mr *MockDataStorageInterfaceMockRecorder) DeleteAuditLogEntriesBefore(t interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuditLogEntriesBefore", reflect.TypeOf((*MockDataStorageInterface)(nil).DeleteAuditLogEntriesBefore), t)
+}
+
+// GetAuditLog mocks base method
+func (m *MockDataStorageInterface) GetAuditLog(chatID int64, limit int) ([]*models.AuditLogEntry, error) {
+	ret := m.ctrl.Call(m, "GetAuditLog", chatID, limit)
+	ret0, _ := ret[0].([]*models.AuditLogEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog
+func (mr *MockDataStorageInterfaceMockRecorder) GetAuditLog(chatID, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockDataStorageInterface)(nil).GetAuditLog), chatID, limit)
+}
+
+// GetChatStats mocks base method
+func (m *MockDataStorageInterface) GetChatStats(chatID int64, since time.Time, limit int) (*models.ChatStats, error) {
+	ret := m.ctrl.Call(m, "GetChatStats", chatID, since, limit)
+	ret0, _ := ret[0].(*models.ChatStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChatStats indicates an expected call of GetChatStats
+func (mr *MockDataStorageInterfaceMockRecorder) GetChatStats(chatID, since, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatStats", reflect.TypeOf((*MockDataStorageInterface)(nil).GetChatStats), chatID, since, limit)
+}
+
+// GetActorNames mocks base method
+func (m *MockDataStorageInterface) GetActorNames(chatID int64) (map[int]string, error) {
+	ret := m.ctrl.Call(m, "GetActorNames", chatID)
+	ret0, _ := ret[0].(map[int]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActorNames indicates an expected call of GetActorNames
+func (mr *MockDataStorageInterfaceMockRecorder) GetActorNames(chatID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorNames", reflect.TypeOf((*MockDataStorageInterface)(nil).GetActorNames), chatID)
+}
+
+// DeletePendingImport mocks base method
+func (m *MockDataStorageInterface) DeletePendingImport(chatID int64, userID int) error {
+	ret := m.ctrl.Call(m, "DeletePendingImport", chatID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePendingImport indicates an expected call of DeletePendingImport
+func (mr *MockDataStorageInterfaceMockRecorder) DeletePendingImport(chatID, userID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePendingImport", reflect.TypeOf((*MockDataStorageInterface)(nil).DeletePendingImport), chatID, userID)
+}
+
+// GetPendingImport mocks base method
+func (m *MockDataStorageInterface) GetPendingImport(chatID int64, userID int) (*models.PendingImport, error) {
+	ret := m.ctrl.Call(m, "GetPendingImport", chatID, userID)
+	ret0, _ := ret[0].(*models.PendingImport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingImport indicates an expected call of GetPendingImport
+func (mr *MockDataStorageInterfaceMockRecorder) GetPendingImport(chatID, userID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingImport", reflect.TypeOf((*MockDataStorageInterface)(nil).GetPendingImport), chatID, userID)
+}
+
+// SetPendingImport mocks base method
+func (m *MockDataStorageInterface) SetPendingImport(pendingImport models.PendingImport) error {
+	ret := m.ctrl.Call(m, "SetPendingImport", pendingImport)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPendingImport indicates an expected call of SetPendingImport
+func (mr *MockDataStorageInterfaceMockRecorder) SetPendingImport(pendingImport interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingImport", reflect.TypeOf((*MockDataStorageInterface)(nil).SetPendingImport), pendingImport)
+}
+
+// DeleteRecipe mocks base method
+func (m *MockDataStorageInterface) DeleteRecipe(chatID, recipeID int64) error {
+	ret := m.ctrl.Call(m, "DeleteRecipe", chatID, recipeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecipe indicates an expected call of DeleteRecipe
+func (mr *MockDataStorageInterfaceMockRecorder) DeleteRecipe(chatID, recipeID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecipe", reflect.TypeOf((*MockDataStorageInterface)(nil).DeleteRecipe), chatID, recipeID)
+}
+
+// GetRecipe mocks base method
+func (m *MockDataStorageInterface) GetRecipe(chatID int64, name string) (*models.Recipe, error) {
+	ret := m.ctrl.Call(m, "GetRecipe", chatID, name)
+	ret0, _ := ret[0].(*models.Recipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipe indicates an expected call of GetRecipe
+func (mr *MockDataStorageInterfaceMockRecorder) GetRecipe(chatID, name interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipe", reflect.TypeOf((*MockDataStorageInterface)(nil).GetRecipe), chatID, name)
+}
+
+// GetRecipes mocks base method
+func (m *MockDataStorageInterface) GetRecipes(chatID int64) ([]*models.Recipe, error) {
+	ret := m.ctrl.Call(m, "GetRecipes", chatID)
+	ret0, _ := ret[0].([]*models.Recipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecipes indicates an expected call of GetRecipes
+func (mr *MockDataStorageInterfaceMockRecorder) GetRecipes(chatID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipes", reflect.TypeOf((*MockDataStorageInterface)(nil).GetRecipes), chatID)
+}
+
+// SetRecipe mocks base method
+func (m *MockDataStorageInterface) SetRecipe(recipe models.Recipe) error {
+	ret := m.ctrl.Call(m, "SetRecipe", recipe)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRecipe indicates an expected call of SetRecipe
+func (mr *MockDataStorageInterfaceMockRecorder) SetRecipe(recipe interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRecipe", reflect.TypeOf((*MockDataStorageInterface)(nil).SetRecipe), recipe)
+}
+
+// AssignShoppingItem mocks base method
+func (m *MockDataStorageInterface) AssignShoppingItem(itemID int64, userID int) error {
+	ret := m.ctrl.Call(m, "AssignShoppingItem", itemID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignShoppingItem indicates an expected call of AssignShoppingItem
+func (mr *MockDataStorageInterfaceMockRecorder) AssignShoppingItem(itemID, userID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignShoppingItem", reflect.TypeOf((*MockDataStorageInterface)(nil).AssignShoppingItem), itemID, userID)
+}
+
+// UpdateShoppingItemPrice mocks base method
+func (m *MockDataStorageInterface) UpdateShoppingItemPrice(itemID int64, price money.Amount) error {
+	ret := m.ctrl.Call(m, "UpdateShoppingItemPrice", itemID, price)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateShoppingItemPrice indicates an expected call of UpdateShoppingItemPrice
+func (mr *MockDataStorageInterfaceMockRecorder) UpdateShoppingItemPrice(itemID, price interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateShoppingItemPrice", reflect.TypeOf((*MockDataStorageInterface)(nil).UpdateShoppingItemPrice), itemID, price)
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/money"
+)
 
 // UnfinishedCommand represents unfinished operations
 // for multi step user interactions.
@@ -16,9 +20,244 @@ type UnfinishedCommand struct {
 
 // ShoppingItem represents an item in a shopping list
 type ShoppingItem struct {
+	ID     int64
+	Name   string
+	ChatID int64
+	// Quantity is how many items users need.
+	// Zero quantity means one item
+	Quantity int
+	// Category is an aisle of a store where users can find the item.
+	// Empty category means that the item hasn't been categorised
+	Category string
+	// Position is a place of the item in the list,
+	// when users arrange items manually
+	Position int
+	// Price is an estimated price of one item in the currency of the chat.
+	// Zero price means that users haven't told us the price
+	Price money.Amount
+	// AssignedTo is an user who buys the item.
+	// Zero means that nobody is assigned
+	AssignedTo int
+	CreatedBy  int
+	CreatedAt  *time.Time
+}
+
+// Events in the history of shopping items
+const (
+	ItemEventAdded     = "added"
+	ItemEventRemoved   = "removed"
+	ItemEventPurchased = "purchased"
+)
+
+// ItemHistoryEntry is an event in the history of shopping items of a chat.
+// The history keeps names of items after items are deleted
+type ItemHistoryEntry struct {
 	ID        int64
+	ChatID    int64
 	Name      string
+	Event     string
+	CreatedAt *time.Time
+}
+
+// Actions in the audit log
+const (
+	AuditActionAdd     = "add"
+	AuditActionDelete  = "delete"
+	AuditActionClear   = "clear"
+	AuditActionEdit    = "edit"
+	AuditActionReorder = "reorder"
+)
+
+// Actor is an user who changes shopping lists.
+// The zero actor is the bot itself: for example, it adds staples
+type Actor struct {
+	ID   int
+	Name string
+}
+
+// ItemState is a state of a shopping item before or after a change
+type ItemState struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity,omitempty"`
+	Category string `json:"category,omitempty"`
+	// AssignedTo is an ID of an user who buys the item
+	AssignedTo int          `json:"assigned_to,omitempty"`
+	Price      money.Amount `json:"price,omitempty"`
+}
+
+// NewItemState returns a current state of a shopping item
+func NewItemState(item *ShoppingItem) ItemState {
+	return ItemState{
+		Name:       item.Name,
+		Quantity:   item.Quantity,
+		Category:   item.Category,
+		AssignedTo: item.AssignedTo,
+		Price:      item.Price,
+	}
+}
+
+// AuditLogEntry is a change of a shopping list made by an actor
+type AuditLogEntry struct {
+	ID        int64
+	ChatID    int64
+	Actor     Actor
+	Action    string
+	Before    []ItemState
+	After     []ItemState
+	CreatedAt *time.Time
+}
+
+// Recipe is a named set of ingredients which users add
+// into the shopping list at once when they cook something
+type Recipe struct {
+	ID          int64
+	ChatID      int64
+	Name        string
+	Ingredients []ItemState
+	CreatedBy   int
+	CreatedAt   *time.Time
+}
+
+// PendingImport is a list of items which an user imports from a file.
+// Items are added into the shopping list after the user confirms the import
+type PendingImport struct {
 	ChatID    int64
+	UserID    int
+	Items     []ItemState
+	CreatedAt *time.Time
+}
+
+// ChatStats are statistics of a shopping list of a chat for a period
+type ChatStats struct {
+	// WeeklyAdded is how many items were added each week.
+	// Weeks without new items are missing
+	WeeklyAdded []WeeklyCount
+	// TopItems are items which were added most often
+	TopItems []ItemCount
+	// TopActors are users who added most items
+	TopActors []ActorCount
+	// Purchases is how many items were purchased
+	Purchases int
+	// AvgTimeToPurchase is how long items stay in the list on average
+	// before users buy them. It's zero when nothing was purchased
+	AvgTimeToPurchase time.Duration
+}
+
+// WeeklyCount is a number of events during a week
+// which starts on Monday in UTC
+type WeeklyCount struct {
+	WeekStart time.Time
+	Count     int
+}
+
+// ItemCount is a number of events with an item
+type ItemCount struct {
+	Name  string
+	Count int
+}
+
+// ActorCount is a number of changes made by an actor
+type ActorCount struct {
+	Actor Actor
+	Count int
+}
+
+// Staple is an item which users buy regularly.
+// The bot adds staples into shopping lists on schedule
+type Staple struct {
+	ID     int64
+	ChatID int64
+	Name   string
+	// IntervalDays is how often the staple is added
+	IntervalDays int
+	// NextAt is when the staple is added next time
+	NextAt    time.Time
 	CreatedBy int
 	CreatedAt *time.Time
 }
+
+// Reminder is a message with a shopping list
+// which the bot sends into a chat on schedule
+type Reminder struct {
+	ID     int64
+	ChatID int64
+	// Description is a schedule as users typed it
+	Description string
+	// Schedule is a cron expression. It's empty for one-off reminders
+	Schedule string
+	// Timezone is a timezone of the chat when the reminder was created
+	Timezone string
+	// NextAt is when the reminder is sent next time
+	NextAt    time.Time
+	CreatedBy int
+	CreatedAt *time.Time
+}
+
+// Supported orders of items in a shopping list
+const (
+	SortOrderAdded    = "added"
+	SortOrderName     = "name"
+	SortOrderCategory = "category"
+	SortOrderAuthor   = "author"
+	// SortOrderManual is the order in which users have arranged items
+	SortOrderManual = "manual"
+)
+
+// SortOrders are all supported orders of items
+var SortOrders = []string{
+	SortOrderAdded,
+	SortOrderName,
+	SortOrderCategory,
+	SortOrderAuthor,
+	SortOrderManual,
+}
+
+// IsSortOrder reports whether an order of items is supported
+func IsSortOrder(sortOrder string) bool {
+	for _, o := range SortOrders {
+		if o == sortOrder {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultTimezone is a timezone of chats which haven't chosen one
+const DefaultTimezone = "UTC"
+
+// ChatSettings represents preferences of a chat
+type ChatSettings struct {
+	ChatID int64
+	// Locale is a language of the chat. Empty locale means
+	// that the bot speaks languages of users
+	Locale       string
+	SortOrder    string
+	ConfirmClear bool
+	Timezone     string
+	// AdminsOnly restricts destructive commands
+	// to administrators of a group
+	AdminsOnly bool
+	// NaturalLanguage allows users to talk to the bot
+	// without commands in private chats
+	NaturalLanguage bool
+	// CategoryOrder is the order of categories in the shopping list.
+	// Empty order means the default order
+	CategoryOrder []string
+	// Currency is a currency of prices and the budget
+	Currency string
+	// Budget is how much the chat plans to spend on the shopping list.
+	// Zero budget means that the chat doesn't have a budget
+	Budget money.Amount
+}
+
+// NewChatSettings returns default settings of a chat
+func NewChatSettings(chatID int64) *ChatSettings {
+	return &ChatSettings{
+		ChatID:          chatID,
+		SortOrder:       SortOrderAdded,
+		ConfirmClear:    true,
+		Timezone:        DefaultTimezone,
+		NaturalLanguage: true,
+		Currency:        money.DefaultCurrency,
+	}
+}
@@ -0,0 +1,160 @@
+// Package money handles estimated prices of shopping items and budgets.
+//
+// Amounts are whole numbers of cents, so sums of prices are exact:
+// 0.10 + 0.20 is 0.30, not 0.30000000000000004.
+// All supported currencies have two decimal places.
+package money
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Amount is an amount of money in hundredths of a currency unit
+type Amount int64
+
+// MaxAmount is the largest amount which fits into storage: 9 999 999.99
+const MaxAmount Amount = 999999999
+
+// Supported currencies
+const (
+	EUR = "EUR"
+	USD = "USD"
+	GBP = "GBP"
+	UAH = "UAH"
+	PLN = "PLN"
+)
+
+// DefaultCurrency is a currency of chats which haven't chosen one
+const DefaultCurrency = EUR
+
+// Currencies are all supported currencies
+var Currencies = []string{EUR, USD, GBP, UAH, PLN}
+
+// symbols of currencies. Symbols which people write
+// after amounts are in symbolsAfter
+var symbols = map[string]string{
+	EUR: "€",
+	USD: "$",
+	GBP: "£",
+	UAH: "₴",
+	PLN: "zł",
+}
+
+var symbolsAfter = map[string]bool{
+	UAH: true,
+	PLN: true,
+}
+
+const amountPattern = `\d{1,7}(?:[.,]\d{1,2})?`
+
+// priceRegexp matches a price with a currency symbol at the end
+// of an item name: "milk €1.20", "milk 1,20€", "milk 30 ₴"
+var priceRegexp = regexp.MustCompile(
+	`^(.*\S)\s+(?:([€$£₴])\s?(` + amountPattern + `)|(` + amountPattern + `)\s?([€$£₴]|zł))$`)
+
+// IsSupported reports whether a currency is supported
+func IsSupported(currency string) bool {
+	_, ok := symbols[currency]
+	return ok
+}
+
+// Parse parses an amount like "1.20", "1,2" or "3".
+// Amounts with more than two decimal places are invalid
+func Parse(s string) (Amount, error) {
+	s = strings.Replace(strings.TrimSpace(s), ",", ".", 1)
+
+	pieces := strings.SplitN(s, ".", 2)
+	if !isDigits(pieces[0]) {
+		return 0, fmt.Errorf("Invalid amount %#v", s)
+	}
+
+	units, err := strconv.ParseInt(pieces[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid amount %#v: %v", s, err)
+	}
+
+	var cents int64
+	if len(pieces) == 2 {
+		fraction := pieces[1]
+		if len(fraction) < 1 || len(fraction) > 2 || !isDigits(fraction) {
+			return 0, fmt.Errorf("Invalid amount %#v", s)
+		}
+		if len(fraction) == 1 {
+			fraction += "0"
+		}
+
+		cents, _ = strconv.ParseInt(fraction, 10, 64)
+	}
+
+	if units > int64(MaxAmount/100) {
+		return 0, fmt.Errorf("Amount %#v is larger than %s", s, MaxAmount)
+	}
+	return Amount(units*100 + cents), nil
+}
+
+// ExtractPrice splits text into an item name and a price,
+// if the text ends with a price with a currency symbol.
+// The symbol only marks the price: amounts are in the currency of a chat
+func ExtractPrice(text string) (name string, price Amount, ok bool) {
+	match := priceRegexp.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil {
+		return text, 0, false
+	}
+
+	amount := match[3]
+	if amount == "" {
+		amount = match[4]
+	}
+
+	price, err := Parse(amount)
+	if err != nil || price == 0 {
+		return text, 0, false
+	}
+	return match[1], price, true
+}
+
+// Times returns the amount multiplied by n
+func (a Amount) Times(n int) Amount {
+	return a * Amount(n)
+}
+
+// String returns the amount with two decimal places: "1.20"
+func (a Amount) String() string {
+	sign := ""
+	if a < 0 {
+		sign = "-"
+		a = -a
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, a/100, a%100)
+}
+
+// Format returns the amount with a symbol of the currency: "€1.20", "30.00 ₴"
+func Format(a Amount, currency string) string {
+	symbol, ok := symbols[currency]
+	if !ok {
+		return a.String() + " " + currency
+	}
+
+	if symbolsAfter[currency] {
+		return a.String() + " " + symbol
+	}
+	if a < 0 {
+		return "-" + symbol + (-a).String()
+	}
+	return symbol + a.String()
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
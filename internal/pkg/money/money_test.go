@@ -0,0 +1,88 @@
+package money
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected Amount
+	}{
+		{"1.20", 120},
+		{"1,2", 120},
+		{"3", 300},
+		{" 0.05 ", 5},
+		{"9999999.99", MaxAmount},
+	}
+
+	for _, tc := range testCases {
+		actual, err := Parse(tc.text)
+		if err != nil {
+			t.Errorf("Unexpected err for %#v: %v", tc.text, err)
+		}
+		if actual != tc.expected {
+			t.Errorf("Expected %d for %#v, got %d", tc.expected, tc.text, actual)
+		}
+	}
+
+	for _, text := range []string{"", "1.234", "-1", "1.", ".5", "1e3", "abc", "10000000"} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("Expected an error for %#v", text)
+		}
+	}
+}
+
+func TestSumIsExact(t *testing.T) {
+	a, _ := Parse("0.10")
+	b, _ := Parse("0.20")
+	if sum := a + b; sum.String() != "0.30" {
+		t.Errorf("Expected 0.30, got %s", sum)
+	}
+}
+
+func TestExtractPrice(t *testing.T) {
+	testCases := []struct {
+		text          string
+		expectedName  string
+		expectedPrice Amount
+		expectedOk    bool
+	}{
+		{"milk €1.20", "milk", 120, true},
+		{"oat milk 1,20€", "oat milk", 120, true},
+		{"bread $ 2", "bread", 200, true},
+		{"eggs 30 ₴", "eggs", 3000, true},
+		{"cheese 12.5 zł", "cheese", 1250, true},
+		{"milk 2", "milk 2", 0, false},
+		{"€1.20", "€1.20", 0, false},
+		{"milk €0", "milk €0", 0, false},
+		{"milk €1.234", "milk €1.234", 0, false},
+	}
+
+	for _, tc := range testCases {
+		name, price, ok := ExtractPrice(tc.text)
+		if name != tc.expectedName || price != tc.expectedPrice || ok != tc.expectedOk {
+			t.Errorf("Expected (%#v, %d, %v) for %#v, got (%#v, %d, %v)",
+				tc.expectedName, tc.expectedPrice, tc.expectedOk, tc.text,
+				name, price, ok)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		amount   Amount
+		currency string
+		expected string
+	}{
+		{120, EUR, "€1.20"},
+		{5, USD, "$0.05"},
+		{-250, GBP, "-£2.50"},
+		{3000, UAH, "30.00 ₴"},
+		{100, "XYZ", "1.00 XYZ"},
+	}
+
+	for _, tc := range testCases {
+		if actual := Format(tc.amount, tc.currency); actual != tc.expected {
+			t.Errorf("Expected %#v, got %#v", tc.expected, actual)
+		}
+	}
+}
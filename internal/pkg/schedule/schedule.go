@@ -0,0 +1,183 @@
+// Package schedule parses cron-like schedules
+// and calculates when they fire next time
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression with five fields:
+// minute, hour, day of month, month and day of week.
+//
+// Fields support "*", numbers, lists ("1,15"), ranges ("1-5")
+// and steps ("*/15", "0-30/10"). Sunday is 0 or 7.
+// Like in cron, if both days of month and days of week are restricted,
+// the schedule fires when either of them matches.
+type Schedule struct {
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+
+	// anyDay and anyWeekday are true when fields start with "*"
+	anyDay     bool
+	anyWeekday bool
+}
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// maxSearchYears limits the search of the next time for schedules
+// which never fire. For example, "0 0 30 2 *"
+const maxSearchYears = 5
+
+// Parse parses a cron expression
+func Parse(expr string) (*Schedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf(
+			"Expected %d fields in the expression %#v, got %d",
+			len(fields), expr, len(parts))
+	}
+
+	var bits [5]uint64
+	for i, part := range parts {
+		b, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, err
+		}
+		bits[i] = b
+	}
+
+	// Sunday can be 0 or 7
+	weekdays := bits[4]
+	if weekdays&(1<<7) != 0 {
+		weekdays |= 1
+	}
+
+	return &Schedule{
+		minutes:    bits[0],
+		hours:      bits[1],
+		days:       bits[2],
+		months:     bits[3],
+		weekdays:   weekdays,
+		anyDay:     strings.HasPrefix(parts[2], "*"),
+		anyWeekday: strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// parseField returns a bitset of values of a field
+func parseField(text string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(text, ",") {
+		rangeText, step := item, 1
+		if pieces := strings.SplitN(item, "/", 2); len(pieces) == 2 {
+			rangeText = pieces[0]
+
+			var err error
+			step, err = strconv.Atoi(pieces[1])
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf(
+					"Invalid step %#v in the %s field", pieces[1], f.name)
+			}
+		}
+
+		from, to := f.min, f.max
+		if rangeText != "*" {
+			pieces := strings.SplitN(rangeText, "-", 2)
+
+			var err error
+			from, err = strconv.Atoi(pieces[0])
+			if err != nil {
+				return 0, fmt.Errorf(
+					"Invalid value %#v in the %s field", item, f.name)
+			}
+
+			to = from
+			if len(pieces) == 2 {
+				to, err = strconv.Atoi(pieces[1])
+				if err != nil {
+					return 0, fmt.Errorf(
+						"Invalid value %#v in the %s field", item, f.name)
+				}
+			} else if step > 1 {
+				// "5/15" means "5-59/15"
+				to = f.max
+			}
+		}
+
+		if from < f.min || to > f.max || from > to {
+			return 0, fmt.Errorf(
+				"Value %#v is out of range %d-%d in the %s field",
+				item, f.min, f.max, f.name)
+		}
+
+		for v := from; v <= to; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time after t when the schedule fires.
+// The time is in the location of t, seconds are always zero.
+// It returns the zero time, if the schedule never fires
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if !has(s.months, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !has(s.hours, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !has(s.minutes, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	day := has(s.days, t.Day())
+	weekday := has(s.weekdays, int(t.Weekday()))
+
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	}
+	return day || weekday
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
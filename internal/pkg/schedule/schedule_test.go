@@ -0,0 +1,78 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	for _, expr := range []string{
+		"* * * * *", "0 10 * * 6", "*/15 9-18 * * 1-5",
+		"0 0 1,15 * *", "30 8 * * 7", "5/20 * * 1-12/2 *",
+	} {
+		if _, err := Parse(expr); err != nil {
+			t.Errorf("Unexpected err for %#v: %v", expr, err)
+		}
+	}
+
+	for _, expr := range []string{
+		"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *",
+		"* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *",
+		"*/0 * * * *", "a * * * *", "1-a * * * *",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Expected an error for %#v", expr)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kiev")
+	if err != nil {
+		t.Skipf("Timezone database is not available: %v", err)
+	}
+
+	// Monday
+	now := time.Date(2018, 10, 22, 12, 30, 45, 0, time.UTC)
+
+	testCases := []struct {
+		expr     string
+		t        time.Time
+		expected time.Time
+	}{
+		{"* * * * *", now, time.Date(2018, 10, 22, 12, 31, 0, 0, time.UTC)},
+		{"0 10 * * 6", now, time.Date(2018, 10, 27, 10, 0, 0, 0, time.UTC)},
+		{"0 10 * * 1", now, time.Date(2018, 10, 29, 10, 0, 0, 0, time.UTC)},
+		{"45 12 * * *", now, time.Date(2018, 10, 22, 12, 45, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", now, time.Date(2018, 10, 23, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", now, time.Date(2018, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", now, time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", now, time.Time{}},
+		// Sunday is 0 and 7
+		{"0 8 * * 7", now, time.Date(2018, 10, 28, 8, 0, 0, 0, time.UTC)},
+		// Either the day of month or the day of week
+		{"0 8 25 * 3", now, time.Date(2018, 10, 24, 8, 0, 0, 0, time.UTC)},
+		// Times are in the location of the argument
+		{"0 10 * * 6", now.In(kyiv), time.Date(2018, 10, 27, 10, 0, 0, 0, kyiv)},
+		// The clock goes back on the 28th of October in Kyiv
+		{"0 10 * * *", time.Date(2018, 10, 27, 10, 0, 0, 0, kyiv),
+			time.Date(2018, 10, 28, 10, 0, 0, 0, kyiv)},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.expr, func(t *testing.T) {
+			next := mustParse(t, testCase.expr).Next(testCase.t)
+			if !next.Equal(testCase.expected) {
+				t.Errorf("Expected %v, got %v", testCase.expected, next)
+			}
+		})
+	}
+}
+
+func mustParse(t *testing.T, expr string) *Schedule {
+	s, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("Unexpected err for %#v: %v", expr, err)
+	}
+	return s
+}
@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/money"
+)
+
+// auditLogStorage records changes of shopping lists made by an actor
+// into the audit log. Other methods go to the underlying storage as is
+type auditLogStorage struct {
+	DataStorageInterface
+
+	actor models.Actor
+}
+
+// WithAuditLog returns a storage which records every change
+// of shopping lists made by the actor into the audit log.
+//
+// Users check items by deleting them, so checks are recorded as deletes
+func WithAuditLog(st DataStorageInterface, actor models.Actor) DataStorageInterface {
+	return &auditLogStorage{
+		DataStorageInterface: st,
+		actor:                actor,
+	}
+}
+
+// AddShoppingItemIntoShoppingList adds an item and records it
+func (s *auditLogStorage) AddShoppingItemIntoShoppingList(item models.ShoppingItem) error {
+	if err := s.DataStorageInterface.AddShoppingItemIntoShoppingList(item); err != nil {
+		return err
+	}
+
+	return s.record(item.ChatID, models.AuditActionAdd,
+		nil, []models.ItemState{models.NewItemState(&item)})
+}
+
+// DeleteShoppingItem deletes an item and records its last state
+func (s *auditLogStorage) DeleteShoppingItem(itemID int64) error {
+	item, err := s.GetShoppingItem(itemID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.DataStorageInterface.DeleteShoppingItem(itemID); err != nil {
+		return err
+	}
+
+	// Someone else has deleted the item already
+	if item == nil {
+		return nil
+	}
+
+	return s.record(item.ChatID, models.AuditActionDelete,
+		[]models.ItemState{models.NewItemState(item)}, nil)
+}
+
+// DeleteAllShoppingItems deletes all items of a chat
+// and records the list as it was before
+func (s *auditLogStorage) DeleteAllShoppingItems(chatID int64) error {
+	items, err := s.GetShoppingItems(chatID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.DataStorageInterface.DeleteAllShoppingItems(chatID); err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	return s.record(chatID, models.AuditActionClear, newItemStates(items), nil)
+}
+
+// IncreaseShoppingItemQuantity changes quantity of an item and records it
+func (s *auditLogStorage) IncreaseShoppingItemQuantity(itemID int64, by int) error {
+	return s.editItem(itemID, func() error {
+		return s.DataStorageInterface.IncreaseShoppingItemQuantity(itemID, by)
+	})
+}
+
+// UpdateShoppingItemCategory changes category of an item and records it
+func (s *auditLogStorage) UpdateShoppingItemCategory(itemID int64, category string) error {
+	return s.editItem(itemID, func() error {
+		return s.DataStorageInterface.UpdateShoppingItemCategory(itemID, category)
+	})
+}
+
+// UpdateShoppingItemPrice changes price of an item and records it
+func (s *auditLogStorage) UpdateShoppingItemPrice(itemID int64, price money.Amount) error {
+	return s.editItem(itemID, func() error {
+		return s.DataStorageInterface.UpdateShoppingItemPrice(itemID, price)
+	})
+}
+
+// AssignShoppingItem assigns an item to an user and records it
+func (s *auditLogStorage) AssignShoppingItem(itemID int64, userID int) error {
+	return s.editItem(itemID, func() error {
+		return s.DataStorageInterface.AssignShoppingItem(itemID, userID)
+	})
+}
+
+// MergeShoppingItems merges duplicates into an item
+// and records states of all merged items
+func (s *auditLogStorage) MergeShoppingItems(itemID int64, duplicateIDs []int64) error {
+	item, err := s.GetShoppingItem(itemID)
+	if err != nil {
+		return err
+	}
+
+	// Merge doesn't change anything when the item doesn't exist
+	if item == nil {
+		return s.DataStorageInterface.MergeShoppingItems(itemID, duplicateIDs)
+	}
+
+	chatItems, err := s.GetShoppingItems(item.ChatID)
+	if err != nil {
+		return err
+	}
+
+	before := []models.ItemState{models.NewItemState(item)}
+	for _, chatItem := range chatItems {
+		for _, duplicateID := range duplicateIDs {
+			if chatItem.ID == duplicateID && chatItem.ID != itemID {
+				before = append(before, models.NewItemState(chatItem))
+				break
+			}
+		}
+	}
+
+	if err := s.DataStorageInterface.MergeShoppingItems(itemID, duplicateIDs); err != nil {
+		return err
+	}
+
+	after, err := s.GetShoppingItem(itemID)
+	if err != nil {
+		return err
+	}
+
+	return s.record(item.ChatID, models.AuditActionEdit,
+		before, newItemStates([]*models.ShoppingItem{after}))
+}
+
+// ReorderShoppingItems arranges items of a chat
+// and records the order before and after
+func (s *auditLogStorage) ReorderShoppingItems(chatID int64, itemIDs []int64) error {
+	before, err := s.GetShoppingItems(chatID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.DataStorageInterface.ReorderShoppingItems(chatID, itemIDs); err != nil {
+		return err
+	}
+
+	after, err := s.GetShoppingItems(chatID)
+	if err != nil {
+		return err
+	}
+
+	return s.record(chatID, models.AuditActionReorder,
+		newItemStates(before), newItemStates(after))
+}
+
+// editItem records states of an item before and after the change
+func (s *auditLogStorage) editItem(itemID int64, change func() error) error {
+	before, err := s.GetShoppingItem(itemID)
+	if err != nil {
+		return err
+	}
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	// There is nothing to change when the item doesn't exist
+	if before == nil {
+		return nil
+	}
+
+	after, err := s.GetShoppingItem(itemID)
+	if err != nil {
+		return err
+	}
+
+	return s.record(before.ChatID, models.AuditActionEdit,
+		newItemStates([]*models.ShoppingItem{before}),
+		newItemStates([]*models.ShoppingItem{after}))
+}
+
+func (s *auditLogStorage) record(
+	chatID int64,
+	action string,
+	before, after []models.ItemState,
+) error {
+	err := s.AddAuditLogEntry(models.AuditLogEntry{
+		ChatID: chatID,
+		Actor:  s.actor,
+		Action: action,
+		Before: before,
+		After:  after,
+	})
+	if err != nil {
+		return fmt.Errorf(
+			"Unable to record a change into the audit log (Action=%s, ChatID=%d): %v",
+			action, chatID, err)
+	}
+	return nil
+}
+
+// newItemStates returns states of items skipping nils
+func newItemStates(items []*models.ShoppingItem) []models.ItemState {
+	var states []models.ItemState
+	for _, item := range items {
+		if item != nil {
+			states = append(states, models.NewItemState(item))
+		}
+	}
+	return states
+}
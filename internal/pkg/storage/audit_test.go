@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/mocks/mock_storage"
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/money"
+)
+
+func TestWithAuditLog(t *testing.T) {
+	chatID := int64(123)
+	actor := models.Actor{ID: 321, Name: "Alice"}
+	milk := &models.ShoppingItem{ID: 1, ChatID: chatID, Name: "milk", Quantity: 1}
+	bread := &models.ShoppingItem{ID: 2, ChatID: chatID, Name: "bread"}
+
+	expectEntry := func(
+		t *testing.T,
+		stMock *mock_storage.MockDataStorageInterface,
+		expectedEntry models.AuditLogEntry,
+	) {
+		stMock.EXPECT().AddAuditLogEntry(gomock.Any()).Do(func(entry models.AuditLogEntry) {
+			if !reflect.DeepEqual(entry, expectedEntry) {
+				t.Errorf("Expected entry %#v, got %#v", expectedEntry, entry)
+			}
+		}).Return(nil)
+	}
+
+	t.Run("Add", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+		stMock.EXPECT().AddShoppingItemIntoShoppingList(*bread).Return(nil)
+		expectEntry(t, stMock, models.AuditLogEntry{
+			ChatID: chatID,
+			Actor:  actor,
+			Action: models.AuditActionAdd,
+			After:  []models.ItemState{{Name: "bread"}},
+		})
+
+		err := WithAuditLog(stMock, actor).AddShoppingItemIntoShoppingList(*bread)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+		gomock.InOrder(
+			stMock.EXPECT().GetShoppingItem(milk.ID).Return(milk, nil),
+			stMock.EXPECT().DeleteShoppingItem(milk.ID).Return(nil),
+		)
+		expectEntry(t, stMock, models.AuditLogEntry{
+			ChatID: chatID,
+			Actor:  actor,
+			Action: models.AuditActionDelete,
+			Before: []models.ItemState{{Name: "milk", Quantity: 1}},
+		})
+
+		err := WithAuditLog(stMock, actor).DeleteShoppingItem(milk.ID)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Delete deleted item", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+		// Nothing is recorded
+		stMock.EXPECT().GetShoppingItem(milk.ID).Return(nil, nil)
+		stMock.EXPECT().DeleteShoppingItem(milk.ID).Return(nil)
+
+		err := WithAuditLog(stMock, actor).DeleteShoppingItem(milk.ID)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+		gomock.InOrder(
+			stMock.EXPECT().GetShoppingItems(chatID).Return(
+				[]*models.ShoppingItem{milk, bread}, nil),
+			stMock.EXPECT().DeleteAllShoppingItems(chatID).Return(nil),
+		)
+		expectEntry(t, stMock, models.AuditLogEntry{
+			ChatID: chatID,
+			Actor:  actor,
+			Action: models.AuditActionClear,
+			Before: []models.ItemState{{Name: "milk", Quantity: 1}, {Name: "bread"}},
+		})
+
+		err := WithAuditLog(stMock, actor).DeleteAllShoppingItems(chatID)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Edit", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+		increased := *milk
+		increased.Quantity = 3
+		gomock.InOrder(
+			stMock.EXPECT().GetShoppingItem(milk.ID).Return(milk, nil),
+			stMock.EXPECT().IncreaseShoppingItemQuantity(milk.ID, 2).Return(nil),
+			stMock.EXPECT().GetShoppingItem(milk.ID).Return(&increased, nil),
+		)
+		expectEntry(t, stMock, models.AuditLogEntry{
+			ChatID: chatID,
+			Actor:  actor,
+			Action: models.AuditActionEdit,
+			Before: []models.ItemState{{Name: "milk", Quantity: 1}},
+			After:  []models.ItemState{{Name: "milk", Quantity: 3}},
+		})
+
+		err := WithAuditLog(stMock, actor).IncreaseShoppingItemQuantity(milk.ID, 2)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Assign", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+		assigned := *milk
+		assigned.AssignedTo = 555
+		gomock.InOrder(
+			stMock.EXPECT().GetShoppingItem(milk.ID).Return(milk, nil),
+			stMock.EXPECT().AssignShoppingItem(milk.ID, 555).Return(nil),
+			stMock.EXPECT().GetShoppingItem(milk.ID).Return(&assigned, nil),
+		)
+		expectEntry(t, stMock, models.AuditLogEntry{
+			ChatID: chatID,
+			Actor:  actor,
+			Action: models.AuditActionEdit,
+			Before: []models.ItemState{{Name: "milk", Quantity: 1}},
+			After:  []models.ItemState{{Name: "milk", Quantity: 1, AssignedTo: 555}},
+		})
+
+		err := WithAuditLog(stMock, actor).AssignShoppingItem(milk.ID, 555)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Price", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+		priced := *milk
+		priced.Price = 120
+		gomock.InOrder(
+			stMock.EXPECT().GetShoppingItem(milk.ID).Return(milk, nil),
+			stMock.EXPECT().UpdateShoppingItemPrice(milk.ID, money.Amount(120)).Return(nil),
+			stMock.EXPECT().GetShoppingItem(milk.ID).Return(&priced, nil),
+		)
+		expectEntry(t, stMock, models.AuditLogEntry{
+			ChatID: chatID,
+			Actor:  actor,
+			Action: models.AuditActionEdit,
+			Before: []models.ItemState{{Name: "milk", Quantity: 1}},
+			After:  []models.ItemState{{Name: "milk", Quantity: 1, Price: 120}},
+		})
+
+		err := WithAuditLog(stMock, actor).UpdateShoppingItemPrice(milk.ID, 120)
+		if err != nil {
+			t.Errorf("Unexpected err: got %#v", err)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		stMock := mock_storage.NewMockDataStorageInterface(mockCtrl)
+
+		// Failed changes are not recorded
+		errMock := errors.New("fake error")
+		stMock.EXPECT().GetShoppingItems(chatID).Return(nil, nil)
+		stMock.EXPECT().DeleteAllShoppingItems(chatID).Return(errMock)
+
+		err := WithAuditLog(stMock, actor).DeleteAllShoppingItems(chatID)
+		if err != errMock {
+			t.Errorf("Expected %#v, got %#v", errMock, err)
+		}
+	})
+}
@@ -1,6 +1,11 @@
 package storage
 
-import "github.com/m1kola/shipsterbot/internal/pkg/models"
+import (
+	"time"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/money"
+)
 
 // Generates mocks for tests
 //go:generate mockgen -source=$GOFILE -destination=../mocks/mock_$GOPACKAGE/$GOFILE -package=mock_$GOPACKAGE
@@ -16,4 +21,55 @@ type DataStorageInterface interface {
 	DeleteShoppingItem(itemID int64) error
 	GetShoppingItems(chatID int64) ([]*models.ShoppingItem, error)
 	DeleteAllShoppingItems(chatID int64) error
+	IncreaseShoppingItemQuantity(itemID int64, by int) error
+	MergeShoppingItems(itemID int64, duplicateIDs []int64) error
+	UpdateShoppingItemCategory(itemID int64, category string) error
+	UpdateShoppingItemPrice(itemID int64, price money.Amount) error
+	AssignShoppingItem(itemID int64, userID int) error
+	ReorderShoppingItems(chatID int64, itemIDs []int64) error
+
+	SetPendingImport(pendingImport models.PendingImport) error
+	GetPendingImport(chatID int64, userID int) (*models.PendingImport, error)
+	DeletePendingImport(chatID int64, userID int) error
+
+	GetItemHistoryEntry(entryID int64) (*models.ItemHistoryEntry, error)
+	GetFrequentItems(chatID int64, limit int) ([]*models.ItemHistoryEntry, error)
+
+	SetRecipe(recipe models.Recipe) error
+	GetRecipe(chatID int64, name string) (*models.Recipe, error)
+	GetRecipes(chatID int64) ([]*models.Recipe, error)
+	DeleteRecipe(chatID int64, recipeID int64) error
+
+	AddStaple(staple models.Staple) error
+	GetStaple(stapleID int64) (*models.Staple, error)
+	GetStaples(chatID int64) ([]*models.Staple, error)
+	GetDueStaples(now time.Time) ([]*models.Staple, error)
+	UpdateStapleNextAt(stapleID int64, nextAt time.Time) error
+	DeleteStaple(stapleID int64) error
+
+	AddReminder(reminder models.Reminder) error
+	GetReminder(reminderID int64) (*models.Reminder, error)
+	GetReminders(chatID int64) ([]*models.Reminder, error)
+	GetDueReminders(now time.Time) ([]*models.Reminder, error)
+	UpdateReminderNextAt(reminderID int64, nextAt time.Time) error
+	DeleteReminder(reminderID int64) error
+
+	AddAuditLogEntry(entry models.AuditLogEntry) error
+	GetAuditLog(chatID int64, limit int) ([]*models.AuditLogEntry, error)
+	DeleteAuditLogEntriesBefore(t time.Time) (int64, error)
+	GetActorNames(chatID int64) (map[int]string, error)
+
+	GetChatStats(chatID int64, since time.Time, limit int) (*models.ChatStats, error)
+
+	GetCategoryKeywords(chatID int64) (map[string]string, error)
+	SetCategoryKeyword(chatID int64, keyword, category string) error
+
+	MarkChatActive(chatID int64) error
+	MarkChatInactive(chatID int64) error
+
+	GetUserLocale(userID int) (string, error)
+	SetUserLocale(userID int, locale string) error
+
+	GetChatSettings(chatID int64) (*models.ChatSettings, error)
+	UpdateChatSettings(settings models.ChatSettings) error
 }
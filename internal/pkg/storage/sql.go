@@ -2,8 +2,15 @@ package storage
 
 import (
 	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
 
+	"github.com/lib/pq"
+
+	"github.com/m1kola/shipsterbot/internal/pkg/category"
 	"github.com/m1kola/shipsterbot/internal/pkg/models"
+	"github.com/m1kola/shipsterbot/internal/pkg/money"
 )
 
 // SQLStorage implements the DataStorageInterface
@@ -91,11 +98,31 @@ func (s *SQLStorage) DeleteUnfinishedCommand(chatID int64, userID int) error {
 // AddShoppingItemIntoShoppingList adds a shoping item into a shipping list
 // of a specific chat
 func (s *SQLStorage) AddShoppingItemIntoShoppingList(item models.ShoppingItem) error {
+	quantity := item.Quantity
+	if quantity < 1 {
+		quantity = 1
+	}
+
+	// New items go to the end of the list
 	_, err := s.db.Exec(
-		`INSERT INTO
-			shopping_items (name, chat_id, created_by)
-		VALUES ($1, $2, $3)`,
-		item.Name, item.ChatID, item.CreatedBy)
+		`WITH added AS (
+			INSERT INTO
+				shopping_items (
+					name, chat_id, created_by, quantity, category, price, position)
+			SELECT
+				$1, $2, $3, $4, $5, $7, coalesce(max(position), 0) + 1
+			FROM shopping_items
+			WHERE
+				chat_id = $2
+			RETURNING chat_id, name, created_by
+		)
+		INSERT INTO
+			shopping_item_history (chat_id, name, event, created_by)
+		SELECT
+			chat_id, name, $6, created_by
+		FROM added`,
+		item.Name, item.ChatID, item.CreatedBy, quantity, item.Category,
+		models.ItemEventAdded, amountValue(item.Price))
 
 	return err
 }
@@ -104,13 +131,30 @@ func (s *SQLStorage) AddShoppingItemIntoShoppingList(item models.ShoppingItem) e
 func (s *SQLStorage) GetShoppingItems(chatID int64) ([]*models.ShoppingItem, error) {
 	var itemsList []*models.ShoppingItem
 
+	// Items are sorted according to the chat settings.
+	// Categories follow the order of aisles of the chat: categories
+	// which are missing in it go after it in the default order.
+	// Positions make the order stable: they follow the order
+	// in which items were added, until users rearrange items
 	rows, err := s.db.Query(
 		`SELECT
-			id, name, chat_id, created_by, created_at
-		FROM shopping_items
+			si.id, si.name, si.chat_id, si.quantity, si.category,
+			si.position, si.price, coalesce(si.assigned_to, 0), si.created_by,
+			si.created_at
+		FROM shopping_items si
+		LEFT JOIN chat_settings cs ON
+			cs.chat_id = si.chat_id
 		WHERE
-			chat_id = $1`,
-		chatID)
+			si.chat_id = $1
+		ORDER BY
+			CASE WHEN cs.sort_order = 'name' THEN lower(si.name) END,
+			CASE WHEN cs.sort_order = 'category' THEN array_position(
+				string_to_array(concat_ws(',', nullif(cs.category_order, ''), $2), ','),
+				si.category) END,
+			CASE WHEN cs.sort_order = 'author' THEN si.created_by END,
+			CASE WHEN cs.sort_order = 'added' THEN si.created_at END,
+			si.position, si.id`,
+		chatID, strings.Join(category.DefaultOrder, ","))
 
 	if err != nil {
 		return nil, err
@@ -119,14 +163,20 @@ func (s *SQLStorage) GetShoppingItems(chatID int64) ([]*models.ShoppingItem, err
 	defer rows.Close()
 	for rows.Next() {
 		item := models.ShoppingItem{}
+		var price sql.NullString
 		err = rows.Scan(
-			&item.ID, &item.Name, &item.ChatID,
+			&item.ID, &item.Name, &item.ChatID, &item.Quantity,
+			&item.Category, &item.Position, &price, &item.AssignedTo,
 			&item.CreatedBy, &item.CreatedAt)
 
 		if err != nil {
 			return nil, err
 		}
 
+		if item.Price, err = scanAmount(price); err != nil {
+			return nil, err
+		}
+
 		itemsList = append(itemsList, &item)
 
 	}
@@ -138,9 +188,11 @@ func (s *SQLStorage) GetShoppingItems(chatID int64) ([]*models.ShoppingItem, err
 // GetShoppingItem returns a shopping item by id from a specific chat
 func (s *SQLStorage) GetShoppingItem(itemID int64) (*models.ShoppingItem, error) {
 	item := models.ShoppingItem{}
+	var price sql.NullString
 	row := s.db.QueryRow(
 		`SELECT
-			id, name, chat_id, created_by, created_at
+			id, name, chat_id, quantity, category, position, price,
+			coalesce(assigned_to, 0), created_by, created_at
 		FROM shopping_items
 		WHERE
 			id = $1`,
@@ -150,36 +202,1208 @@ func (s *SQLStorage) GetShoppingItem(itemID int64) (*models.ShoppingItem, error)
 		&item.ID,
 		&item.Name,
 		&item.ChatID,
+		&item.Quantity,
+		&item.Category,
+		&item.Position,
+		&price,
+		&item.AssignedTo,
 		&item.CreatedBy,
 		&item.CreatedAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	item.Price, err = scanAmount(price)
 	return &item, err
 }
 
 // DeleteShoppingItem deletes a shipping item from a shipping lits
-// for a specific chat
+// for a specific chat.
+//
+// Users delete items one by one when they buy them,
+// so the history records a purchase
 func (s *SQLStorage) DeleteShoppingItem(itemID int64) error {
 	_, err := s.db.Exec(
+		`WITH deleted AS (
+			DELETE FROM
+				shopping_items
+			WHERE
+				id = $1
+			RETURNING chat_id, name, created_by
+		)
+		INSERT INTO
+			shopping_item_history (chat_id, name, event, created_by)
+		SELECT
+			chat_id, name, $2, created_by
+		FROM deleted`,
+		itemID, models.ItemEventPurchased)
+
+	return err
+}
+
+// DeleteAllShoppingItems deletes all shopping items for a specific chat.
+//
+// Users clear lists for different reasons,
+// so the history records removals
+func (s *SQLStorage) DeleteAllShoppingItems(chatID int64) error {
+	_, err := s.db.Exec(
+		`WITH deleted AS (
+			DELETE FROM
+				shopping_items
+			WHERE
+				chat_id = $1
+			RETURNING chat_id, name, created_by, position, id
+		)
+		INSERT INTO
+			shopping_item_history (chat_id, name, event, created_by)
+		SELECT
+			chat_id, name, $2, created_by
+		FROM deleted
+		ORDER BY
+			position, id`,
+		chatID, models.ItemEventRemoved)
+
+	return err
+}
+
+// SetPendingImport saves items which an user imports.
+// An user has only one pending import in a chat, so it replaces the previous one
+func (s *SQLStorage) SetPendingImport(pendingImport models.PendingImport) error {
+	items, err := json.Marshal(itemStates(pendingImport.Items))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(
+		`INSERT INTO
+			pending_imports (chat_id, user_id, items)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (chat_id, user_id) DO UPDATE SET
+			items = EXCLUDED.items,
+			created_at = now() at time zone 'utc'`,
+		pendingImport.ChatID, pendingImport.UserID, string(items))
+
+	return err
+}
+
+// GetPendingImport returns items which an user imports in a chat
+func (s *SQLStorage) GetPendingImport(chatID int64, userID int) (*models.PendingImport, error) {
+	pendingImport := models.PendingImport{}
+	var items []byte
+	err := s.db.QueryRow(
+		`SELECT
+			chat_id, user_id, items, created_at
+		FROM pending_imports
+		WHERE
+			chat_id = $1
+			AND user_id = $2`,
+		chatID, userID,
+	).Scan(
+		&pendingImport.ChatID,
+		&pendingImport.UserID,
+		&items,
+		&pendingImport.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(items, &pendingImport.Items); err != nil {
+		return nil, err
+	}
+	return &pendingImport, nil
+}
+
+// DeletePendingImport deletes items which an user imports in a chat
+func (s *SQLStorage) DeletePendingImport(chatID int64, userID int) error {
+	_, err := s.db.Exec(
+		`DELETE FROM
+			pending_imports
+		WHERE
+			chat_id = $1
+			AND user_id = $2`,
+		chatID, userID)
+
+	return err
+}
+
+// GetItemHistoryEntry returns an entry of the item history by id
+func (s *SQLStorage) GetItemHistoryEntry(entryID int64) (*models.ItemHistoryEntry, error) {
+	entry := models.ItemHistoryEntry{}
+	row := s.db.QueryRow(
+		`SELECT
+			id, chat_id, name, event, created_at
+		FROM shopping_item_history
+		WHERE
+			id = $1`,
+		entryID)
+
+	err := row.Scan(
+		&entry.ID,
+		&entry.ChatID,
+		&entry.Name,
+		&entry.Event,
+		&entry.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &entry, err
+}
+
+// GetFrequentItems returns the most frequently bought items of a chat.
+// There is one entry per name: the latest purchase of the item.
+// Names are compared case insensitively
+func (s *SQLStorage) GetFrequentItems(chatID int64, limit int) ([]*models.ItemHistoryEntry, error) {
+	var entries []*models.ItemHistoryEntry
+
+	rows, err := s.db.Query(
+		`SELECT
+			h.id, h.chat_id, h.name, h.event, h.created_at
+		FROM (
+			SELECT
+				max(id) AS id, count(*) AS purchases
+			FROM shopping_item_history
+			WHERE
+				chat_id = $1
+				AND event = $2
+			GROUP BY
+				lower(name)
+			ORDER BY
+				purchases DESC, max(id) DESC
+			LIMIT $3
+		) f
+		INNER JOIN shopping_item_history h ON h.id = f.id
+		ORDER BY
+			f.purchases DESC, f.id DESC`,
+		chatID, models.ItemEventPurchased, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		entry := models.ItemHistoryEntry{}
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ChatID,
+			&entry.Name,
+			&entry.Event,
+			&entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	err = rows.Err()
+	return entries, err
+}
+
+// IncreaseShoppingItemQuantity increases quantity of a shopping item
+func (s *SQLStorage) IncreaseShoppingItemQuantity(itemID int64, by int) error {
+	_, err := s.db.Exec(
+		`UPDATE
+			shopping_items
+		SET
+			quantity = quantity + $2
+		WHERE
+			id = $1`,
+		itemID, by)
+
+	return err
+}
+
+// MergeShoppingItems merges duplicates into a shopping item:
+// quantities of duplicates are added to the item
+// and duplicates are deleted
+func (s *SQLStorage) MergeShoppingItems(itemID int64, duplicateIDs []int64) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Only items from the same chat can be merged
+	_, err = tx.Exec(
+		`UPDATE
+			shopping_items si
+		SET
+			quantity = si.quantity + (
+				SELECT
+					coalesce(sum(d.quantity), 0)
+				FROM shopping_items d
+				WHERE
+					d.id = ANY($2)
+					AND d.id <> si.id
+					AND d.chat_id = si.chat_id
+			)
+		WHERE
+			si.id = $1`,
+		itemID, pq.Array(duplicateIDs))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
 		`DELETE FROM
+			shopping_items d
+		USING shopping_items si
+		WHERE
+			si.id = $1
+			AND d.id = ANY($2)
+			AND d.id <> si.id
+			AND d.chat_id = si.chat_id`,
+		itemID, pq.Array(duplicateIDs))
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+// UpdateShoppingItemCategory changes a category of a shopping item
+func (s *SQLStorage) UpdateShoppingItemCategory(itemID int64, category string) error {
+	_, err := s.db.Exec(
+		`UPDATE
 			shopping_items
+		SET
+			category = $2
 		WHERE
 			id = $1`,
-		itemID)
+		itemID, category)
 
 	return err
 }
 
-// DeleteAllShoppingItems deletes all shopping items for a specific chat
-func (s *SQLStorage) DeleteAllShoppingItems(chatID int64) error {
+// UpdateShoppingItemPrice changes an estimated price of a shopping item.
+// Zero price removes the price
+func (s *SQLStorage) UpdateShoppingItemPrice(itemID int64, price money.Amount) error {
 	_, err := s.db.Exec(
-		`DELETE FROM
+		`UPDATE
 			shopping_items
+		SET
+			price = $2
 		WHERE
-			chat_id = $1`,
+			id = $1`,
+		itemID, amountValue(price))
+
+	return err
+}
+
+// AssignShoppingItem assigns a shopping item to an user.
+// Zero userID means that nobody is assigned
+func (s *SQLStorage) AssignShoppingItem(itemID int64, userID int) error {
+	_, err := s.db.Exec(
+		`UPDATE
+			shopping_items
+		SET
+			assigned_to = nullif($2, 0)
+		WHERE
+			id = $1`,
+		itemID, userID)
+
+	return err
+}
+
+// ReorderShoppingItems arranges items of a chat in the given order.
+// Items which are missing in the order keep their positions
+func (s *SQLStorage) ReorderShoppingItems(chatID int64, itemIDs []int64) error {
+	_, err := s.db.Exec(
+		`UPDATE
+			shopping_items si
+		SET
+			position = o.position
+		FROM unnest($2::bigint[]) WITH ORDINALITY AS o(id, position)
+		WHERE
+			si.id = o.id
+			AND si.chat_id = $1`,
+		chatID, pq.Array(itemIDs))
+
+	return err
+}
+
+// SetRecipe saves a recipe of a chat.
+// Names are case insensitive: a recipe with the same name is replaced
+func (s *SQLStorage) SetRecipe(recipe models.Recipe) error {
+	ingredients, err := json.Marshal(itemStates(recipe.Ingredients))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(
+		`INSERT INTO
+			recipes (chat_id, name, ingredients, created_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (chat_id, lower(name)) DO UPDATE SET
+			name = EXCLUDED.name,
+			ingredients = EXCLUDED.ingredients,
+			created_by = EXCLUDED.created_by,
+			created_at = current_timestamp`,
+		recipe.ChatID, recipe.Name, string(ingredients), recipe.CreatedBy)
+
+	return err
+}
+
+// GetRecipe returns a recipe of a chat by a case insensitive name
+func (s *SQLStorage) GetRecipe(chatID int64, name string) (*models.Recipe, error) {
+	row := s.db.QueryRow(
+		`SELECT
+			id, chat_id, name, ingredients, created_by, created_at
+		FROM recipes
+		WHERE
+			chat_id = $1
+			AND lower(name) = lower($2)`,
+		chatID, name)
+
+	recipe, err := scanRecipe(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return recipe, err
+}
+
+// GetRecipes returns recipes of a chat sorted by name
+func (s *SQLStorage) GetRecipes(chatID int64) ([]*models.Recipe, error) {
+	var recipes []*models.Recipe
+
+	rows, err := s.db.Query(
+		`SELECT
+			id, chat_id, name, ingredients, created_by, created_at
+		FROM recipes
+		WHERE
+			chat_id = $1
+		ORDER BY
+			lower(name)`,
+		chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		recipe, err := scanRecipe(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		recipes = append(recipes, recipe)
+	}
+
+	err = rows.Err()
+	return recipes, err
+}
+
+// DeleteRecipe deletes a recipe of a chat
+func (s *SQLStorage) DeleteRecipe(chatID int64, recipeID int64) error {
+	_, err := s.db.Exec(
+		`DELETE FROM
+			recipes
+		WHERE
+			chat_id = $1
+			AND id = $2`,
+		chatID, recipeID)
+
+	return err
+}
+
+func scanRecipe(row scanner) (*models.Recipe, error) {
+	recipe := models.Recipe{}
+	var ingredients []byte
+	err := row.Scan(
+		&recipe.ID,
+		&recipe.ChatID,
+		&recipe.Name,
+		&ingredients,
+		&recipe.CreatedBy,
+		&recipe.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(ingredients, &recipe.Ingredients); err != nil {
+		return nil, err
+	}
+	return &recipe, nil
+}
+
+// AddStaple adds a staple of a chat
+func (s *SQLStorage) AddStaple(staple models.Staple) error {
+	_, err := s.db.Exec(
+		`INSERT INTO
+			staples (chat_id, name, interval_days, next_at, created_by)
+		VALUES ($1, $2, $3, $4, $5)`,
+		staple.ChatID, staple.Name, staple.IntervalDays, staple.NextAt.UTC(),
+		staple.CreatedBy)
+
+	return err
+}
+
+// GetStaple returns a staple by id
+func (s *SQLStorage) GetStaple(stapleID int64) (*models.Staple, error) {
+	row := s.db.QueryRow(
+		`SELECT
+			id, chat_id, name, interval_days, next_at, created_by, created_at
+		FROM staples
+		WHERE
+			id = $1`,
+		stapleID)
+
+	staple, err := scanStaple(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return staple, err
+}
+
+// GetStaples returns staples of a chat
+func (s *SQLStorage) GetStaples(chatID int64) ([]*models.Staple, error) {
+	return s.queryStaples(
+		`SELECT
+			id, chat_id, name, interval_days, next_at, created_by, created_at
+		FROM staples
+		WHERE
+			chat_id = $1
+		ORDER BY
+			created_at, id`,
 		chatID)
+}
+
+// GetDueStaples returns staples of all chats
+// which must be added into shopping lists.
+// It skips chats where the bot can't send messages
+func (s *SQLStorage) GetDueStaples(now time.Time) ([]*models.Staple, error) {
+	return s.queryStaples(
+		`SELECT
+			s.id, s.chat_id, s.name, s.interval_days, s.next_at,
+			s.created_by, s.created_at
+		FROM staples s
+		LEFT JOIN chats c ON c.id = s.chat_id
+		WHERE
+			s.next_at <= $1 AND
+			coalesce(c.is_active, true)
+		ORDER BY
+			s.chat_id, s.next_at, s.id`,
+		now.UTC())
+}
+
+// UpdateStapleNextAt changes when a staple is added next time
+func (s *SQLStorage) UpdateStapleNextAt(stapleID int64, nextAt time.Time) error {
+	_, err := s.db.Exec(
+		`UPDATE
+			staples
+		SET
+			next_at = $2
+		WHERE
+			id = $1`,
+		stapleID, nextAt.UTC())
+
+	return err
+}
+
+// DeleteStaple deletes a staple
+func (s *SQLStorage) DeleteStaple(stapleID int64) error {
+	_, err := s.db.Exec(
+		`DELETE FROM
+			staples
+		WHERE
+			id = $1`,
+		stapleID)
 
 	return err
 }
+
+func (s *SQLStorage) queryStaples(query string, args ...interface{}) ([]*models.Staple, error) {
+	var staples []*models.Staple
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		staple, err := scanStaple(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		staples = append(staples, staple)
+	}
+
+	err = rows.Err()
+	return staples, err
+}
+
+// AddReminder adds a reminder of a chat
+func (s *SQLStorage) AddReminder(reminder models.Reminder) error {
+	_, err := s.db.Exec(
+		`INSERT INTO
+			reminders (chat_id, description, schedule, timezone, next_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		reminder.ChatID, reminder.Description, reminder.Schedule,
+		reminder.Timezone, reminder.NextAt.UTC(), reminder.CreatedBy)
+
+	return err
+}
+
+// GetReminder returns a reminder by id
+func (s *SQLStorage) GetReminder(reminderID int64) (*models.Reminder, error) {
+	row := s.db.QueryRow(
+		`SELECT
+			id, chat_id, description, schedule, timezone, next_at,
+			created_by, created_at
+		FROM reminders
+		WHERE
+			id = $1`,
+		reminderID)
+
+	reminder, err := scanReminder(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return reminder, err
+}
+
+// GetReminders returns reminders of a chat
+func (s *SQLStorage) GetReminders(chatID int64) ([]*models.Reminder, error) {
+	return s.queryReminders(
+		`SELECT
+			id, chat_id, description, schedule, timezone, next_at,
+			created_by, created_at
+		FROM reminders
+		WHERE
+			chat_id = $1
+		ORDER BY
+			next_at, id`,
+		chatID)
+}
+
+// GetDueReminders returns reminders of all chats which must be sent.
+// It skips chats where the bot can't send messages
+func (s *SQLStorage) GetDueReminders(now time.Time) ([]*models.Reminder, error) {
+	return s.queryReminders(
+		`SELECT
+			r.id, r.chat_id, r.description, r.schedule, r.timezone, r.next_at,
+			r.created_by, r.created_at
+		FROM reminders r
+		LEFT JOIN chats c ON c.id = r.chat_id
+		WHERE
+			r.next_at <= $1 AND
+			coalesce(c.is_active, true)
+		ORDER BY
+			r.next_at, r.id`,
+		now.UTC())
+}
+
+// UpdateReminderNextAt changes when a reminder is sent next time
+func (s *SQLStorage) UpdateReminderNextAt(reminderID int64, nextAt time.Time) error {
+	_, err := s.db.Exec(
+		`UPDATE
+			reminders
+		SET
+			next_at = $2
+		WHERE
+			id = $1`,
+		reminderID, nextAt.UTC())
+
+	return err
+}
+
+// DeleteReminder deletes a reminder
+func (s *SQLStorage) DeleteReminder(reminderID int64) error {
+	_, err := s.db.Exec(
+		`DELETE FROM
+			reminders
+		WHERE
+			id = $1`,
+		reminderID)
+
+	return err
+}
+
+func (s *SQLStorage) queryReminders(query string, args ...interface{}) ([]*models.Reminder, error) {
+	var reminders []*models.Reminder
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		reminders = append(reminders, reminder)
+	}
+
+	err = rows.Err()
+	return reminders, err
+}
+
+func scanReminder(row scanner) (*models.Reminder, error) {
+	reminder := models.Reminder{}
+	err := row.Scan(
+		&reminder.ID,
+		&reminder.ChatID,
+		&reminder.Description,
+		&reminder.Schedule,
+		&reminder.Timezone,
+		&reminder.NextAt,
+		&reminder.CreatedBy,
+		&reminder.CreatedAt)
+
+	return &reminder, err
+}
+
+// AddAuditLogEntry records a change of a shopping list
+func (s *SQLStorage) AddAuditLogEntry(entry models.AuditLogEntry) error {
+	before, err := json.Marshal(itemStates(entry.Before))
+	if err != nil {
+		return err
+	}
+	after, err := json.Marshal(itemStates(entry.After))
+	if err != nil {
+		return err
+	}
+
+	// Names of users are kept for good, because
+	// entries of the audit log are deleted after a while
+	_, err = s.db.Exec(
+		`WITH actor AS (
+			INSERT INTO
+				actors (chat_id, user_id, name)
+			SELECT
+				$1, $2, $3
+			WHERE
+				$2 <> 0
+			ON CONFLICT (chat_id, user_id) DO UPDATE SET
+				name = EXCLUDED.name,
+				updated_at = now() at time zone 'utc'
+		)
+		INSERT INTO
+			audit_log (chat_id, user_id, user_name, action, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		entry.ChatID, entry.Actor.ID, entry.Actor.Name, entry.Action,
+		string(before), string(after))
+
+	return err
+}
+
+// GetAuditLog returns recent changes of a shopping list of a chat.
+// The most recent changes go first
+func (s *SQLStorage) GetAuditLog(chatID int64, limit int) ([]*models.AuditLogEntry, error) {
+	var entries []*models.AuditLogEntry
+
+	rows, err := s.db.Query(
+		`SELECT
+			id, chat_id, user_id, user_name, action, before, after, created_at
+		FROM audit_log
+		WHERE
+			chat_id = $1
+		ORDER BY
+			created_at DESC, id DESC
+		LIMIT $2`,
+		chatID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		entry := models.AuditLogEntry{}
+		var before, after []byte
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ChatID,
+			&entry.Actor.ID,
+			&entry.Actor.Name,
+			&entry.Action,
+			&before,
+			&after,
+			&entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(before, &entry.Before); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(after, &entry.After); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	err = rows.Err()
+	return entries, err
+}
+
+// DeleteAuditLogEntriesBefore deletes changes which are older than t.
+// It returns a number of deleted entries
+func (s *SQLStorage) DeleteAuditLogEntriesBefore(t time.Time) (int64, error) {
+	result, err := s.db.Exec(
+		`DELETE FROM
+			audit_log
+		WHERE
+			created_at < $1`,
+		t.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// GetActorNames returns the latest known names of users
+// who changed a shopping list of a chat
+func (s *SQLStorage) GetActorNames(chatID int64) (map[int]string, error) {
+	names := map[int]string{}
+
+	rows, err := s.db.Query(
+		`SELECT
+			user_id, name
+		FROM actors
+		WHERE
+			chat_id = $1`,
+		chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var userID int
+		var name string
+		if err := rows.Scan(&userID, &name); err != nil {
+			return nil, err
+		}
+
+		names[userID] = name
+	}
+
+	err = rows.Err()
+	return names, err
+}
+
+// GetChatStats returns statistics of a shopping list of a chat
+// since the given time. Top lists contain up to `limit` entries
+func (s *SQLStorage) GetChatStats(chatID int64, since time.Time, limit int) (*models.ChatStats, error) {
+	stats := models.ChatStats{}
+	since = since.UTC()
+
+	var err error
+	stats.WeeklyAdded, err = s.getWeeklyAdded(chatID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.TopItems, err = s.getTopItems(chatID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.TopActors, err = s.getTopActors(chatID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Purchases are matched with the last addition
+	// of an item with the same name
+	var avgSeconds sql.NullFloat64
+	err = s.db.QueryRow(
+		`SELECT
+			count(*), extract(epoch FROM avg(p.created_at - a.created_at))
+		FROM shopping_item_history p
+		LEFT JOIN LATERAL (
+			SELECT
+				created_at
+			FROM shopping_item_history
+			WHERE
+				chat_id = p.chat_id
+				AND lower(name) = lower(p.name)
+				AND event = $3
+				AND created_at <= p.created_at
+			ORDER BY
+				created_at DESC, id DESC
+			LIMIT 1
+		) a ON true
+		WHERE
+			p.chat_id = $1
+			AND p.event = $2
+			AND p.created_at >= $4`,
+		chatID, models.ItemEventPurchased, models.ItemEventAdded, since,
+	).Scan(&stats.Purchases, &avgSeconds)
+	if err != nil {
+		return nil, err
+	}
+	if avgSeconds.Valid {
+		stats.AvgTimeToPurchase = time.Duration(avgSeconds.Float64 * float64(time.Second))
+	}
+
+	return &stats, nil
+}
+
+func (s *SQLStorage) getWeeklyAdded(chatID int64, since time.Time) ([]models.WeeklyCount, error) {
+	var counts []models.WeeklyCount
+
+	rows, err := s.db.Query(
+		`SELECT
+			date_trunc('week', created_at) AS week, count(*)
+		FROM shopping_item_history
+		WHERE
+			chat_id = $1
+			AND event = $2
+			AND created_at >= $3
+		GROUP BY
+			week
+		ORDER BY
+			week`,
+		chatID, models.ItemEventAdded, since)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		count := models.WeeklyCount{}
+		if err := rows.Scan(&count.WeekStart, &count.Count); err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, count)
+	}
+
+	err = rows.Err()
+	return counts, err
+}
+
+func (s *SQLStorage) getTopItems(chatID int64, since time.Time, limit int) ([]models.ItemCount, error) {
+	var counts []models.ItemCount
+
+	rows, err := s.db.Query(
+		`SELECT
+			min(name), count(*) AS added
+		FROM shopping_item_history
+		WHERE
+			chat_id = $1
+			AND event = $2
+			AND created_at >= $3
+		GROUP BY
+			lower(name)
+		ORDER BY
+			added DESC, lower(name)
+		LIMIT $4`,
+		chatID, models.ItemEventAdded, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		count := models.ItemCount{}
+		if err := rows.Scan(&count.Name, &count.Count); err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, count)
+	}
+
+	err = rows.Err()
+	return counts, err
+}
+
+// getTopActors returns users who added most items.
+// Staples count for users who created them
+func (s *SQLStorage) getTopActors(chatID int64, since time.Time, limit int) ([]models.ActorCount, error) {
+	var counts []models.ActorCount
+
+	// Users can change their names, so we show the latest ones
+	rows, err := s.db.Query(
+		`SELECT
+			h.created_by, coalesce(a.name, ''), count(*) AS added
+		FROM shopping_item_history h
+		LEFT JOIN actors a ON
+			a.chat_id = h.chat_id
+			AND a.user_id = h.created_by
+		WHERE
+			h.chat_id = $1
+			AND h.event = $2
+			AND h.created_at >= $3
+			AND h.created_by <> 0
+		GROUP BY
+			h.created_by, a.name
+		ORDER BY
+			added DESC, h.created_by
+		LIMIT $4`,
+		chatID, models.ItemEventAdded, since, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		count := models.ActorCount{}
+		if err := rows.Scan(&count.Actor.ID, &count.Actor.Name, &count.Count); err != nil {
+			return nil, err
+		}
+
+		counts = append(counts, count)
+	}
+
+	err = rows.Err()
+	return counts, err
+}
+
+// itemStates makes sure that empty states are stored
+// as empty JSON arrays instead of nulls
+func itemStates(states []models.ItemState) []models.ItemState {
+	if states == nil {
+		return []models.ItemState{}
+	}
+	return states
+}
+
+// scanner is implemented by sql.Row and sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanStaple(row scanner) (*models.Staple, error) {
+	staple := models.Staple{}
+	err := row.Scan(
+		&staple.ID,
+		&staple.ChatID,
+		&staple.Name,
+		&staple.IntervalDays,
+		&staple.NextAt,
+		&staple.CreatedBy,
+		&staple.CreatedAt)
+
+	return &staple, err
+}
+
+// GetCategoryKeywords returns keywords of categories
+// which users have taught the bot in a chat
+func (s *SQLStorage) GetCategoryKeywords(chatID int64) (map[string]string, error) {
+	keywords := map[string]string{}
+
+	rows, err := s.db.Query(
+		`SELECT
+			keyword, category
+		FROM category_keywords
+		WHERE
+			chat_id = $1`,
+		chatID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var keyword, category string
+		if err := rows.Scan(&keyword, &category); err != nil {
+			return nil, err
+		}
+
+		keywords[keyword] = category
+	}
+
+	err = rows.Err()
+	return keywords, err
+}
+
+// SetCategoryKeyword saves a keyword of a category in a chat
+func (s *SQLStorage) SetCategoryKeyword(chatID int64, keyword, category string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO
+			category_keywords (chat_id, keyword, category)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (chat_id, keyword) DO UPDATE
+		SET
+			category = $3`,
+		chatID, keyword, category)
+
+	return err
+}
+
+// MarkChatActive marks a chat as a chat where the bot can send messages
+func (s *SQLStorage) MarkChatActive(chatID int64) error {
+	// We call this on every update, so we only read
+	// the chat, if it's already active
+	var isActive bool
+	err := s.db.QueryRow(
+		"SELECT is_active FROM chats WHERE id = $1",
+		chatID).Scan(&isActive)
+	if err == nil && isActive {
+		return nil
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	_, err = s.db.Exec(
+		`INSERT INTO
+			chats (id, is_active)
+		VALUES ($1, true)
+		ON CONFLICT (id) DO UPDATE
+		SET
+			is_active = true,
+			updated_at = current_timestamp
+		WHERE
+			chats.is_active = false`,
+		chatID)
+
+	return err
+}
+
+// MarkChatInactive marks a chat as a chat where the bot can't send messages.
+// For example, when an user blocked the bot
+func (s *SQLStorage) MarkChatInactive(chatID int64) error {
+	_, err := s.db.Exec(
+		`INSERT INTO
+			chats (id, is_active)
+		VALUES ($1, false)
+		ON CONFLICT (id) DO UPDATE
+		SET
+			is_active = false,
+			updated_at = current_timestamp`,
+		chatID)
+
+	return err
+}
+
+// GetUserLocale returns a locale chosen by an user.
+// It returns an empty string, if the user hasn't chosen a locale
+func (s *SQLStorage) GetUserLocale(userID int) (string, error) {
+	var locale string
+	row := s.db.QueryRow(
+		`SELECT
+			locale
+		FROM user_settings
+		WHERE
+			user_id = $1`,
+		userID)
+
+	err := row.Scan(&locale)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return locale, err
+}
+
+// SetUserLocale saves a locale chosen by an user
+func (s *SQLStorage) SetUserLocale(userID int, locale string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO
+			user_settings (user_id, locale)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET
+			locale = $2,
+			updated_at = current_timestamp`,
+		userID, locale)
+
+	return err
+}
+
+// GetChatSettings returns settings of a chat.
+// It returns default settings, if the chat hasn't changed them
+func (s *SQLStorage) GetChatSettings(chatID int64) (*models.ChatSettings, error) {
+	settings := models.NewChatSettings(chatID)
+	var categoryOrder string
+	var budget sql.NullString
+	row := s.db.QueryRow(
+		`SELECT
+			locale, sort_order, confirm_clear, timezone, admins_only,
+			natural_language, category_order, currency, budget
+		FROM chat_settings
+		WHERE
+			chat_id = $1`,
+		chatID)
+
+	err := row.Scan(
+		&settings.Locale,
+		&settings.SortOrder,
+		&settings.ConfirmClear,
+		&settings.Timezone,
+		&settings.AdminsOnly,
+		&settings.NaturalLanguage,
+		&categoryOrder,
+		&settings.Currency,
+		&budget)
+
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if categoryOrder != "" {
+		settings.CategoryOrder = strings.Split(categoryOrder, ",")
+	}
+	settings.Budget, err = scanAmount(budget)
+	return settings, err
+}
+
+// UpdateChatSettings saves settings of a chat
+func (s *SQLStorage) UpdateChatSettings(settings models.ChatSettings) error {
+	_, err := s.db.Exec(
+		`INSERT INTO
+			chat_settings (
+				chat_id, locale, sort_order, confirm_clear, timezone, admins_only,
+				natural_language, category_order, currency, budget)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (chat_id) DO UPDATE
+		SET
+			locale = $2,
+			sort_order = $3,
+			confirm_clear = $4,
+			timezone = $5,
+			admins_only = $6,
+			natural_language = $7,
+			category_order = $8,
+			currency = $9,
+			budget = $10,
+			updated_at = current_timestamp`,
+		settings.ChatID, settings.Locale, settings.SortOrder,
+		settings.ConfirmClear, settings.Timezone, settings.AdminsOnly,
+		settings.NaturalLanguage, strings.Join(settings.CategoryOrder, ","),
+		settings.Currency, amountValue(settings.Budget))
+
+	return err
+}
+
+// amountValue returns a value of a numeric column for an amount.
+// Amounts are passed as decimal strings, so Postgres never sees floats.
+// Zero amounts are stored as NULL
+func amountValue(amount money.Amount) interface{} {
+	if amount == 0 {
+		return nil
+	}
+	return amount.String()
+}
+
+// scanAmount parses a value of a numeric column.
+// NULL is the zero amount
+func scanAmount(value sql.NullString) (money.Amount, error) {
+	if !value.Valid {
+		return 0, nil
+	}
+	return money.Parse(value.String)
+}
@@ -0,0 +1,9 @@
+BEGIN;
+
+create table chats (
+	id bigint primary key,
+	is_active boolean default true not null,
+	updated_at timestamp default current_timestamp not null
+);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+create table user_settings (
+	user_id integer primary key,
+	locale varchar(16) not null,
+	updated_at timestamp default current_timestamp not null
+);
+
+COMMIT;
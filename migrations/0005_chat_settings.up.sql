@@ -0,0 +1,12 @@
+BEGIN;
+
+create table chat_settings (
+	chat_id bigint primary key,
+	locale varchar(16) default '' not null,
+	sort_order varchar(16) default 'added' not null,
+	confirm_clear boolean default true not null,
+	timezone varchar(64) default 'UTC' not null,
+	updated_at timestamp default current_timestamp not null
+);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+alter table chat_settings
+	drop column admins_only;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+alter table chat_settings
+	add column admins_only boolean default false not null;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+alter table chat_settings
+	drop column natural_language;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+alter table chat_settings
+	add column natural_language boolean default true not null;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+alter table shopping_items
+	drop column quantity;
+
+COMMIT;
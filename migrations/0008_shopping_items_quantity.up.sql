@@ -0,0 +1,6 @@
+BEGIN;
+
+alter table shopping_items
+	add column quantity int default 1 not null;
+
+COMMIT;
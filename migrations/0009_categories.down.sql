@@ -0,0 +1,11 @@
+BEGIN;
+
+drop table category_keywords;
+
+alter table chat_settings
+	drop column category_order;
+
+alter table shopping_items
+	drop column category;
+
+COMMIT;
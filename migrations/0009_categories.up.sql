@@ -0,0 +1,17 @@
+BEGIN;
+
+alter table shopping_items
+	add column category varchar(32) default '' not null;
+
+alter table chat_settings
+	add column category_order varchar(255) default '' not null;
+
+create table category_keywords (
+	chat_id bigint not null,
+	keyword varchar(255) not null,
+	category varchar(32) not null,
+	created_at timestamp default current_timestamp not null,
+	primary key (chat_id, keyword)
+);
+
+COMMIT;
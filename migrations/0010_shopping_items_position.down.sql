@@ -0,0 +1,8 @@
+BEGIN;
+
+drop index shopping_items_chat_id_position_idx;
+
+alter table shopping_items
+	drop column position;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+alter table shopping_items
+	add column position int default 0 not null;
+
+-- Existing items keep the order in which they were added
+update shopping_items si
+set
+	position = p.position
+from (
+	select
+		id,
+		row_number() over (partition by chat_id order by created_at, id) as position
+	from shopping_items
+) p
+where
+	si.id = p.id;
+
+create index shopping_items_chat_id_position_idx
+	on shopping_items (chat_id, position);
+
+COMMIT;
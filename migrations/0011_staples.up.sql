@@ -0,0 +1,15 @@
+BEGIN;
+
+create table staples (
+	id serial primary key,
+	chat_id bigint not null,
+	name varchar(255) not null,
+	interval_days int not null,
+	next_at timestamp not null,
+	created_by int not null,
+	created_at timestamp default current_timestamp not null
+);
+
+create index staples_next_at_idx on staples (next_at);
+
+COMMIT;
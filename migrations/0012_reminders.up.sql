@@ -0,0 +1,16 @@
+BEGIN;
+
+create table reminders (
+	id serial primary key,
+	chat_id bigint not null,
+	description varchar(255) not null,
+	schedule varchar(64) default '' not null,
+	timezone varchar(64) not null,
+	next_at timestamp not null,
+	created_by int not null,
+	created_at timestamp default current_timestamp not null
+);
+
+create index reminders_next_at_idx on reminders (next_at);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+drop table shopping_item_history;
+
+COMMIT;
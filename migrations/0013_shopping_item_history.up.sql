@@ -0,0 +1,22 @@
+BEGIN;
+
+create table shopping_item_history (
+	id serial primary key,
+	chat_id bigint not null,
+	name varchar(255) not null,
+	event varchar(16) not null,
+	created_at timestamp default (now() at time zone 'utc') not null
+);
+
+create index shopping_item_history_chat_id_event_idx
+	on shopping_item_history (chat_id, event);
+
+-- Items which are in lists now were added at some point.
+-- The bot compares times of events in UTC, but times of items
+-- are in the timezone of the server
+insert into shopping_item_history (chat_id, name, event, created_at)
+	select chat_id, name, 'added', created_at::timestamptz at time zone 'utc'
+	from shopping_items
+	order by created_at, id;
+
+COMMIT;
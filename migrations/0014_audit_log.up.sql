@@ -0,0 +1,17 @@
+BEGIN;
+
+create table audit_log (
+	id serial primary key,
+	chat_id bigint not null,
+	user_id int not null,
+	user_name varchar(255) default '' not null,
+	action varchar(16) not null,
+	before jsonb default '[]' not null,
+	after jsonb default '[]' not null,
+	created_at timestamp default (now() at time zone 'utc') not null
+);
+
+create index audit_log_chat_id_created_at_idx on audit_log (chat_id, created_at);
+create index audit_log_created_at_idx on audit_log (created_at);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+create table pending_imports (
+	chat_id bigint not null,
+	user_id int not null,
+	items jsonb not null,
+	created_at timestamp default (now() at time zone 'utc') not null,
+	primary key (chat_id, user_id)
+);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+create table recipes (
+	id serial primary key,
+	chat_id bigint not null,
+	name varchar(255) not null,
+	ingredients jsonb not null,
+	created_by int not null,
+	created_at timestamp default current_timestamp not null
+);
+
+create unique index recipes_chat_id_name_idx on recipes (chat_id, lower(name));
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+alter table chat_settings
+	drop column budget,
+	drop column currency;
+
+alter table shopping_items
+	drop column price;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+alter table shopping_items
+	add column price numeric(9, 2);
+
+alter table chat_settings
+	add column currency varchar(3) default 'EUR' not null,
+	add column budget numeric(9, 2);
+
+COMMIT;
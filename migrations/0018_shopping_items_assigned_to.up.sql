@@ -0,0 +1,6 @@
+BEGIN;
+
+alter table shopping_items
+	add column assigned_to int;
+
+COMMIT;
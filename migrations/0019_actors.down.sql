@@ -0,0 +1,8 @@
+BEGIN;
+
+drop table actors;
+
+alter table shopping_item_history
+	drop column created_by;
+
+COMMIT;
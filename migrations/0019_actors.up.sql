@@ -0,0 +1,23 @@
+BEGIN;
+
+-- Authors of items. Older events have no author
+alter table shopping_item_history
+	add column created_by int default 0 not null;
+
+-- Names of users who changed shopping lists. The audit log
+-- keeps changes for a short time, but names must outlive them
+create table actors (
+	chat_id bigint not null,
+	user_id int not null,
+	name varchar(255) default '' not null,
+	updated_at timestamp default (now() at time zone 'utc') not null,
+	primary key (chat_id, user_id)
+);
+
+insert into actors (chat_id, user_id, name, updated_at)
+	select distinct on (chat_id, user_id) chat_id, user_id, user_name, created_at
+	from audit_log
+	where user_id <> 0
+	order by chat_id, user_id, created_at desc, id desc;
+
+COMMIT;